@@ -0,0 +1,53 @@
+package adapters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/galaxyblack/microspace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapters(t *testing.T) {
+	var points []*microspace.Point[float64]
+	for i := 0; i < 200; i++ {
+		points = append(points, &microspace.Point[float64]{X: rand.Float64(), Y: rand.Float64()})
+	}
+
+	rtree := NewRTree[float64](4, 16)
+	exact := microspace.NewAxdex[float64](uint(len(points)))
+	for _, p := range points {
+		rtree.Insert(p)
+		exact.Insert(p)
+	}
+
+	for _, idx := range []microspace.Index[float64]{rtree, NewKDTree(points)} {
+		assert.Len(t, idx.Points(), len(points))
+		for _, p := range points[:20] {
+			assert.Equal(t, exact.NearestNAt(0, p, 4, 0.2), idx.NearestN(p, 4, 0.2))
+		}
+	}
+}
+
+func TestAdaptersNearestMax(t *testing.T) {
+	a, b, c := &microspace.Point[float32]{X: 0, Y: 0}, &microspace.Point[float32]{X: 0, Y: 0.5}, &microspace.Point[float32]{X: 0.1, Y: 5}
+	points := []*microspace.Point[float32]{a, b, c}
+	rtree := NewRTree[float32](2, 4)
+	for _, p := range points {
+		rtree.Insert(p)
+	}
+
+	// A max of 0 or less is unlimited, as for the package's own indexes.
+	for _, idx := range []microspace.Index[float32]{rtree, NewKDTree(points)} {
+		assert.Equal(t, []*microspace.Point[float32]{a, b}, idx.NearestN(a, 3, 1))
+		assert.Equal(t, []*microspace.Point[float32]{a, b, c}, idx.NearestN(a, -1, 0))
+		assert.Equal(t, []*microspace.Point[float32]{c, b}, idx.NearestN(c, 2, -1))
+		assert.Len(t, idx.NearestN(a, math.MaxInt, 0), 3)
+	}
+
+	// Points inserted later are counted too.
+	tree := NewKDTree(points[:1])
+	tree.Insert(b)
+	assert.Equal(t, []*microspace.Point[float32]{a, b}, tree.NearestN(a, -1, 0))
+}
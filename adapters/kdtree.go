@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"github.com/galaxyblack/microspace"
+	"github.com/kyroy/kdtree"
+)
+
+// KDTree adapts a kyroy/kdtree k-d tree to the microspace Index interface.
+type KDTree[T microspace.Float] struct {
+	tree *kdtree.KDTree
+	// size is the number of points in the tree, which the tree itself
+	// can only count by walking it.
+	size int
+}
+
+// kdPoint is a point stored in the k-d tree.
+type kdPoint[T microspace.Float] struct {
+	p *microspace.Point[T]
+}
+
+// Dimensions implements kdtree.Point.Dimensions
+func (k kdPoint[T]) Dimensions() int {
+	return 2
+}
+
+// Dimension implements kdtree.Point.Dimension
+func (k kdPoint[T]) Dimension(i int) float64 {
+	if i == 0 {
+		return float64(k.p.X)
+	}
+
+	return float64(k.p.Y)
+}
+
+// NewKDTree returns a new adapter over a k-d tree built from the points.
+func NewKDTree[T microspace.Float](points []*microspace.Point[T]) *KDTree[T] {
+	kd := make([]kdtree.Point, len(points))
+	for i, p := range points {
+		kd[i] = kdPoint[T]{p: p}
+	}
+
+	return &KDTree[T]{tree: kdtree.New(kd), size: len(points)}
+}
+
+var _ microspace.Index[float32] = new(KDTree[float32])
+
+// Insert adds a point to the k-d tree.
+func (k *KDTree[T]) Insert(p *microspace.Point[T]) {
+	k.tree.Insert(kdPoint[T]{p: p})
+	k.size++
+}
+
+// NearestN implements Index.NearestN
+func (k *KDTree[T]) NearestN(p *microspace.Point[T], n int, max T) []*microspace.Point[T] {
+	max = searchRadius(max)
+	if n == -1 || n > k.size {
+		n = k.size
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	var results []*microspace.Point[T]
+	for _, q := range k.tree.KNN(kdPoint[T]{p: p}, n) {
+		point := q.(kdPoint[T]).p
+		if point.DistanceToSqr(p) > max*max {
+			break
+		}
+
+		results = append(results, point)
+	}
+
+	return results
+}
+
+// Points implements Index.Points
+func (k *KDTree[T]) Points() []*microspace.Point[T] {
+	kd := k.tree.Points()
+	points := make([]*microspace.Point[T], len(kd))
+	for i, q := range kd {
+		points[i] = q.(kdPoint[T]).p
+	}
+
+	return points
+}
@@ -0,0 +1,83 @@
+// Package adapters wraps third-party spatial indexes behind the microspace
+// Index interface, so they can be benchmarked against the package's own
+// indexes and swapped in without changing call sites.
+package adapters
+
+import (
+	"math"
+
+	"github.com/dhconnelly/rtreego"
+	"github.com/galaxyblack/microspace"
+)
+
+// rtreeTolerance is the half-size of the rectangle each point is stored
+// as, since rtreego only indexes rectangles.
+const rtreeTolerance = 1e-9
+
+// RTree adapts an rtreego R-tree to the microspace Index interface.
+type RTree[T microspace.Float] struct {
+	tree   *rtreego.Rtree
+	points []*microspace.Point[T]
+}
+
+// rtreeItem is a point stored in the R-tree.
+type rtreeItem[T microspace.Float] struct {
+	p *microspace.Point[T]
+}
+
+// Bounds implements rtreego.Spatial.Bounds
+func (r *rtreeItem[T]) Bounds() rtreego.Rect {
+	return rtreego.Point{float64(r.p.X), float64(r.p.Y)}.ToRect(rtreeTolerance)
+}
+
+// NewRTree returns a new adapter over an R-tree whose nodes hold between
+// `min` and `max` children.
+func NewRTree[T microspace.Float](min, max int) *RTree[T] {
+	return &RTree[T]{tree: rtreego.NewTree(2, min, max)}
+}
+
+var _ microspace.Index[float32] = new(RTree[float32])
+
+// Insert adds a point to the R-tree.
+func (r *RTree[T]) Insert(p *microspace.Point[T]) {
+	r.tree.Insert(&rtreeItem[T]{p: p})
+	r.points = append(r.points, p)
+}
+
+// NearestN implements Index.NearestN
+func (r *RTree[T]) NearestN(p *microspace.Point[T], n int, max T) []*microspace.Point[T] {
+	max = searchRadius(max)
+	if size := r.tree.Size(); n == -1 || n > size {
+		n = size
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	var results []*microspace.Point[T]
+	for _, s := range r.tree.NearestNeighbors(n, rtreego.Point{float64(p.X), float64(p.Y)}) {
+		item, ok := s.(*rtreeItem[T])
+		if !ok || item.p.DistanceToSqr(p) > max*max {
+			break
+		}
+
+		results = append(results, item.p)
+	}
+
+	return results
+}
+
+// Points implements Index.Points
+func (r *RTree[T]) Points() []*microspace.Point[T] {
+	return r.points
+}
+
+// searchRadius returns the distance to search within for a `max` passed
+// to a query, where a max of 0 or less is unlimited, as for every index.
+func searchRadius[T microspace.Float](max T) T {
+	if max <= 0 {
+		return T(math.Inf(1))
+	}
+
+	return max
+}
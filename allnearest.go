@@ -0,0 +1,231 @@
+package microspace
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+)
+
+// AllNearestN returns the `k` nearest neighbors within `max` of every
+// point in the index. Rather than a NearestN query per point, it cuts the
+// sorted axis into strips of neighboring points and sorts each strip by
+// the other coordinate once, so every search only visits the few points
+// near its own in both coordinates. The result at each position belongs
+// to the point at the same position in Points, and like NearestN, each
+// point is included among its own neighbors.
+func (a *Axdex[T]) AllNearestN(k int, max T) [][]*Point[T] {
+	lists := a.allNearest(k, max)
+	results := make([][]*Point[T], len(lists))
+	for i := range lists {
+		results[i] = lists[i].Sorted()
+	}
+
+	return results
+}
+
+// KNNGraph returns the `k` nearest neighbors of every point in the index,
+// with their distances, as the adjacency lists of the k-nearest-neighbor
+// graph. It's built the same way as AllNearestN with no `max`, and the
+// list at each position belongs to the point at the same position in
+// Points.
+// Unlike AllNearestN, points aren't included among their own neighbors.
+func (a *Axdex[T]) KNNGraph(k int) [][]Neighbor[T] {
+	graph := make([][]Neighbor[T], len(a.points))
+	if k <= 0 {
+		return graph
+	}
+
+	lists := a.allNearest(k+1, 0)
+	neighbors := make([]Neighbor[T], 0, len(lists)*min(k, max(len(lists)-1, 0)))
+	for i := range lists {
+		start := len(neighbors)
+		for j, p := range lists[i].Sorted() {
+			if p != a.points[i] && len(neighbors)-start < k {
+				neighbors = append(neighbors, Neighbor[T]{Point: p, DistSqr: lists[i].dists[j]})
+			}
+		}
+		graph[i] = neighbors[start:len(neighbors):len(neighbors)]
+	}
+
+	return graph
+}
+
+// allNearest returns the lists of the `k` nearest neighbors within `max`
+// of every point, in the order of Points, or empty lists if k is 0. The
+// lists share their storage, so there are only a few allocations however
+// many points there are.
+func (a *Axdex[T]) allNearest(k int, max T) []neighborList[T] {
+	max = searchRadius(max)
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	var (
+		data    = a.axis.data
+		size    = len(data)
+		results = make([]neighborList[T], len(a.points))
+	)
+	if k == -1 || k > size {
+		k = size
+	}
+	if k <= 0 {
+		return results
+	}
+
+	var (
+		strips = newAxisStrips(data, a.crossValue(), k)
+		byAxis = make([]neighborList[T], size)
+		items  = make([]*Point[T], size*k)
+		dists  = make([]T, size*k)
+	)
+	for i := range data {
+		list := &byAxis[i]
+		list.items = items[i*k : (i+1)*k : (i+1)*k]
+		list.dists = dists[i*k : (i+1)*k : (i+1)*k]
+		strips.nearest(i, max, list)
+		list.Sort()
+	}
+
+	for i, p := range a.points {
+		results[i] = byAxis[a.axis.IndexFor(p)]
+	}
+
+	return results
+}
+
+// crossValue returns the coordinate the points aren't sorted by.
+func (a *Axdex[T]) crossValue() func(*Point[T]) T {
+	if a.along == AxisY {
+		return func(p *Point[T]) T { return p.X }
+	}
+
+	return func(p *Point[T]) T { return p.Y }
+}
+
+// axisStrips cuts a sorted axis into strips of consecutive points, each
+// sorted again by the other coordinate. Every point in a strip shares its
+// order, so the candidates near a point are found by searching outwards
+// in both coordinates at once, rather than by sweeping through every
+// point in between along the axis.
+type axisStrips[T Float] struct {
+	data  axisPointList[T]
+	width int // the number of points in each strip but the last
+
+	// cross holds the other coordinate of the points, strip by strip, each
+	// strip in order of it, and slots holds their positions on the axis.
+	// ranks is the reverse, the position in cross of each point on the
+	// axis.
+	cross []T
+	slots []int32
+	ranks []int32
+}
+
+// newAxisStrips returns the strips of the sorted points, with about as
+// many strips as there are points in each when looking for the `k`
+// nearest neighbors of each. For points spread out evenly, that makes
+// strips about as wide as the distance to the k-th neighbor.
+func newAxisStrips[T Float](data axisPointList[T], value func(*Point[T]) T, k int) *axisStrips[T] {
+	size := len(data)
+	s := &axisStrips[T]{
+		data:  data,
+		width: max(1, int(math.Sqrt(float64(size)*float64(k)))),
+		cross: make([]T, size),
+		slots: make([]int32, size),
+		ranks: make([]int32, size),
+	}
+
+	values := make([]T, size)
+	for i := range data {
+		values[i] = value(data[i].p)
+		s.slots[i] = int32(i)
+	}
+
+	for lo := 0; lo < size; lo += s.width {
+		slots := s.slots[lo:min(lo+s.width, size)]
+		slices.SortFunc(slots, func(i, j int32) int {
+			return cmp.Compare(values[i], values[j])
+		})
+		for j, slot := range slots {
+			s.cross[lo+j] = values[slot]
+			s.ranks[slot] = int32(lo + j)
+		}
+	}
+
+	return s
+}
+
+// nearest fills the list with the nearest neighbors within `max` of the
+// point at position i on the axis, including itself. It searches the
+// point's own strip first, then the strips to either side in order of
+// their distance along the axis, until they're too far away to hold
+// anything nearer than what's in the list.
+func (s *axisStrips[T]) nearest(i int, max T, list *neighborList[T]) {
+	var (
+		size  = len(s.data)
+		value = s.data[i].value
+		own   = i / s.width
+	)
+	s.search(i, own*s.width, int(s.ranks[i]), max, list)
+
+	left, right := own-1, own+1
+	for {
+		var (
+			gap   T
+			found bool
+			next  int
+		)
+		if left >= 0 {
+			gap, found, next = value-s.data[(left+1)*s.width-1].value, true, left
+		}
+		if lo := right * s.width; lo < size && (!found || s.data[lo].value-value < gap) {
+			gap, found, next = s.data[lo].value-value, true, right
+		}
+		if !found || gap > max || (list.Full() && gap*gap >= list.Worst()) {
+			return
+		}
+
+		lo := next * s.width
+		cross := s.cross[lo:min(lo+s.width, size)]
+		c := s.cross[s.ranks[i]]
+		s.search(i, lo, lo+sort.Search(len(cross), func(j int) bool { return cross[j] >= c }), max, list)
+		if next == left {
+			left--
+		} else {
+			right++
+		}
+	}
+}
+
+// search adds the points of the strip starting at `lo` near the point at
+// position i on the axis to the list, moving outwards in both directions
+// from the position `start` in cross, which is where the point's own
+// coordinate falls in the strip.
+func (s *axisStrips[T]) search(i, lo, start int, max T, list *neighborList[T]) {
+	var (
+		hi   = min(lo+s.width, len(s.data))
+		p    = s.data[i].p
+		c    = s.cross[s.ranks[i]]
+		down = start - 1
+		up   = start
+	)
+	for down >= lo || up < hi {
+		var j int
+		var gap T
+		if up < hi && (down < lo || s.cross[up]-c <= c-s.cross[down]) {
+			j, gap = up, s.cross[up]-c
+			up++
+		} else {
+			j, gap = down, c-s.cross[down]
+			down--
+		}
+		if gap > max || (list.Full() && gap*gap >= list.Worst()) {
+			return
+		}
+
+		q := s.data[s.slots[j]].p
+		if d := q.DistanceToSqr(p); d <= max*max {
+			list.Insert(q, d)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllNearestN(t *testing.T) {
+	idx := generateIndex(300)
+	all := idx.AllNearestN(4, 0.1)
+	assert.Len(t, all, 300)
+
+	for i, p := range idx.Points() {
+		expected := idx.NearestNAt(0, p, 4, 0.1)
+		assert.Equal(t, p, all[i][0])
+		assert.Equal(t, len(expected), len(all[i]))
+		for j := range expected {
+			assert.Equal(t, expected[j].DistanceToSqr(p), all[i][j].DistanceToSqr(p))
+		}
+	}
+}
+
+func TestAllNearestNUnlimited(t *testing.T) {
+	// Points in a few columns along the axis, so the first points along
+	// the axis from each one are mostly far away.
+	idx := NewAxdex[float32](300)
+	for i := 0; i < 300; i++ {
+		idx.Insert(&Point[float32]{float32(i % 3), rand.Float32() * 100})
+	}
+
+	all := idx.AllNearestN(5, 0)
+	for i, p := range idx.Points() {
+		expected := idx.NearestN(p, 5, 0)
+		assert.Len(t, all[i], 5)
+		for j := range expected {
+			assert.Equal(t, expected[j].DistanceToSqr(p), all[i][j].DistanceToSqr(p))
+		}
+	}
+}
+
+func BenchmarkAllNearestN(b *testing.B) {
+	idx := generateIndex(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		idx.AllNearestN(3, 0.25)
+	}
+}
+
+func TestKNNGraph(t *testing.T) {
+	idx := generateIndex(300)
+	graph := idx.KNNGraph(3)
+	assert.Len(t, graph, 300)
+
+	for i, p := range idx.Points() {
+		expected := idx.NearestN(p, 4, 0)[1:]
+		assert.Len(t, graph[i], 3)
+		for j, n := range graph[i] {
+			assert.NotEqual(t, p, n.Point)
+			assert.Equal(t, n.Point.DistanceToSqr(p), n.DistSqr)
+			assert.Equal(t, expected[j].DistanceToSqr(p), n.DistSqr)
+		}
+	}
+
+	assert.Len(t, idx.KNNGraph(0), 300)
+	assert.Len(t, idx.KNNGraph(500)[0], 299)
+}
+
+func BenchmarkAllNearestNByQuery(b *testing.B) {
+	idx := generateIndex(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, p := range idx.Points() {
+			idx.NearestN(p, 3, 0.25)
+		}
+	}
+}
+
+func TestAllNearestNSameAxisValue(t *testing.T) {
+	// Every point has the same X, so every strip is as near as any other
+	// along the axis, and some points share their position.
+	idx := NewAxdex[float32](200)
+	for i := 0; i < 200; i++ {
+		idx.Insert(&Point[float32]{1, float32(rand.Intn(100))})
+	}
+
+	all := idx.AllNearestN(4, 0)
+	for i, p := range idx.Points() {
+		expected := idx.NearestN(p, 4, 0)
+		assert.Len(t, all[i], 4)
+		for j := range expected {
+			assert.Equal(t, expected[j].DistanceToSqr(p), all[i][j].DistanceToSqr(p))
+		}
+	}
+}
+
+func BenchmarkKNNGraph(b *testing.B) {
+	idx := generateIndex(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		idx.KNNGraph(3)
+	}
+}
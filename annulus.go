@@ -0,0 +1,31 @@
+package microspace
+
+// Annulus is a ring-shaped region between two circles around the same
+// center, including both edges.
+type Annulus[T Float] struct {
+	Center       Point[T]
+	Inner, Outer T
+}
+
+var _ Region[float32] = new(Annulus[float32])
+
+// Contains returns true if the point lies within the ring.
+func (r *Annulus[T]) Contains(p *Point[T]) bool {
+	d := r.Center.DistanceToSqr(p)
+	return d >= r.Inner*r.Inner && d <= r.Outer*r.Outer
+}
+
+// Bounds returns the smallest rectangle containing the ring.
+func (r *Annulus[T]) Bounds() Rect[T] {
+	return Rect[T]{
+		Min: Point[T]{X: r.Center.X - r.Outer, Y: r.Center.Y - r.Outer},
+		Max: Point[T]{X: r.Center.X + r.Outer, Y: r.Center.Y + r.Outer},
+	}
+}
+
+// WithinAnnulus returns the points at least `rMin` and at most `rMax` from
+// p, such as for spawning near but not too near a player. Only the points
+// in the range of the outer circle along the axis are checked.
+func (a *Axdex[T]) WithinAnnulus(p *Point[T], rMin, rMax T) []*Point[T] {
+	return a.within(&Annulus[T]{Center: *p, Inner: rMin, Outer: rMax})
+}
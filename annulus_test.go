@@ -0,0 +1,30 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithinAnnulus(t *testing.T) {
+	idx := NewAxdex[float64](0)
+	points := []*Point[float64]{{0, 0}, {1, 0}, {0, 2}, {-3, 0}, {0, -4}}
+	for _, p := range points {
+		idx.Insert(p)
+	}
+
+	assert.ElementsMatch(t, []*Point[float64]{points[1], points[2], points[3]}, idx.WithinAnnulus(&Point[float64]{}, 1, 3))
+	assert.ElementsMatch(t, []*Point[float64]{points[0]}, idx.WithinAnnulus(&Point[float64]{}, 0, 0.5))
+	assert.Empty(t, idx.WithinAnnulus(&Point[float64]{}, 5, 10))
+
+	random := generateIndex(1000)
+	for _, p := range randomPoints(10) {
+		var expected []*Point[float32]
+		for _, q := range random.Points() {
+			if d := q.DistanceToSqr(p); d >= 0.1*0.1 && d <= 0.2*0.2 {
+				expected = append(expected, q)
+			}
+		}
+		assert.ElementsMatch(t, expected, random.WithinAnnulus(p, 0.1, 0.2))
+	}
+}
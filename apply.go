@@ -0,0 +1,61 @@
+package microspace
+
+// ApplyWithin calls fn with every point in the index within the region,
+// which may move the point. Once all points have been visited, the axis
+// is repaired so that moved points are found at their new positions, and
+// the versions of moved points are bumped.
+// Like the rest of Axdex it's not safe to call concurrently with queries;
+// use DoubleBuffered.ApplyWithin for that.
+func (a *Axdex[T]) ApplyWithin(region Region[T], fn func(*Point[T])) {
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	bounds := region.Bounds()
+	lo, hi := a.axis.rangeOf(bounds)
+
+	moved, changed := false, false
+	for i := a.axis.Search(lo); i < len(a.axis.data) && a.axis.data[i].value <= hi; i++ {
+		ap := &a.axis.data[i]
+		if !region.Contains(ap.p) {
+			continue
+		}
+
+		before := *ap.p
+		fn(ap.p)
+		if *ap.p != before {
+			a.bumpVersion(ap.p)
+			changed = true
+		}
+		if value := a.axis.ValueFor(ap.p); value != ap.value {
+			ap.value = value
+			moved = true
+		}
+	}
+
+	if moved {
+		a.axis.runSort()
+	}
+	if changed && a.cross != nil {
+		a.cross.refresh()
+	}
+}
+
+// refresh recomputes the value of every point on the axis and sorts it
+// again.
+func (a *axis[T]) refresh() {
+	for i := range a.data {
+		a.data[i].value = a.ValueFor(a.data[i].p)
+	}
+
+	a.settled = 0
+	a.runSort()
+}
+
+// ApplyWithin calls Axdex.ApplyWithin on the write index, synchronized
+// with Insert and Swap, so the changes are published with the next Swap.
+func (d *DoubleBuffered[T]) ApplyWithin(region Region[T], fn func(*Point[T])) {
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+	d.write.ApplyWithin(region, fn)
+}
@@ -0,0 +1,34 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyWithin(t *testing.T) {
+	idx := generateIndex(500)
+	blast := &Circle[float32]{Center: Point[float32]{0.5, 0.5}, Radius: 0.2}
+
+	var hit []*Point[float32]
+	for _, p := range idx.Points() {
+		if blast.Contains(p) {
+			hit = append(hit, p)
+		}
+	}
+
+	var visited []*Point[float32]
+	idx.ApplyWithin(blast, func(p *Point[float32]) {
+		visited = append(visited, p)
+		p.X += 0.5
+	})
+	assert.ElementsMatch(t, hit, visited)
+
+	for i := 1; i < len(idx.axis.data); i++ {
+		assert.True(t, idx.axis.data[i-1].value <= idx.axis.data[i].value)
+		assert.Equal(t, idx.axis.data[i].p.X, idx.axis.data[i].value)
+	}
+	for _, p := range hit {
+		assert.Equal(t, p, idx.NearestN(p, 1, 0.1)[0])
+	}
+}
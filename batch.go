@@ -0,0 +1,54 @@
+package microspace
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// NearestNBatch runs NearestN for every query point, spread across one
+// worker per CPU, returning the results in the same order as the queries.
+// The index is sorted once up front rather than checked by each query,
+// and queries are handed out in order along the axis, so each worker
+// scans neighboring parts of the axis which are likely still cached.
+func (a *Axdex[T]) NearestNBatch(queries []*Point[T], n int, max T) [][]*Point[T] {
+	results := make([][]*Point[T], len(queries))
+	if len(queries) == 0 {
+		return results
+	}
+
+	// Settle everything queries would lazily modify, so the workers
+	// only ever read from the index.
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+	if a.cross != nil && !a.cross.sorted {
+		a.cross.runSort()
+	}
+
+	order := make([]int, len(queries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return a.axis.ValueFor(queries[order[i]]) < a.axis.ValueFor(queries[order[j]])
+	})
+
+	workers := min(runtime.GOMAXPROCS(0), len(queries))
+	size := (len(queries) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(order); start += size {
+		chunk := order[start:min(start+size, len(order))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, i := range chunk {
+				results[i] = a.NearestN(queries[i], n, max)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,37 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNBatch(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	for _, p := range randomPoints(1000) {
+		idx.Insert(p)
+	}
+
+	queries := randomPoints(200)
+	results := idx.NearestNBatch(queries, 4, 0.2)
+	assert.Len(t, results, len(queries))
+	for i, q := range queries {
+		assert.Equal(t, idx.NearestN(q, 4, 0.2), results[i])
+	}
+
+	assert.Empty(t, idx.NearestNBatch(nil, 4, 0))
+}
+
+func TestNearestNBatchDual(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	for _, p := range randomPoints(500) {
+		idx.Insert(p)
+	}
+	idx.EnableDualAxis()
+
+	queries := randomPoints(50)
+	results := idx.NearestNBatch(queries, 3, 0)
+	for i, q := range queries {
+		assert.Equal(t, idx.NearestN(q, 3, 0), results[i])
+	}
+}
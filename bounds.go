@@ -0,0 +1,92 @@
+package microspace
+
+import (
+	"fmt"
+	"math"
+)
+
+// BoundsMode describes what an index does with points inserted outside
+// of its world bounds.
+type BoundsMode int
+
+const (
+	// BoundsReject panics when a point outside the bounds is inserted.
+	BoundsReject BoundsMode = iota
+	// BoundsClamp moves the point onto the nearest edge of the bounds.
+	BoundsClamp
+	// BoundsWrap wraps the point around to the opposite side of the
+	// bounds, as in a toroidal world.
+	BoundsWrap
+)
+
+// SetBounds restricts the points that may be inserted into the index to
+// the provided world bounds, handling points outside of them according
+// to the mode. Clamped and wrapped points are moved in place. Points with
+// an infinite or NaN coordinate have nowhere to be moved to, so they panic
+// in every mode. Points that are already in the index are not checked.
+func (a *Axdex[T]) SetBounds(bounds Rect[T], mode BoundsMode) {
+	a.bounds = &bounds
+	a.boundsMode = mode
+}
+
+// InBounds returns true if the point may be inserted without being
+// rejected or moved, always the case if no bounds are set.
+func (a *Axdex[T]) InBounds(p *Point[T]) bool {
+	return a.bounds == nil || a.bounds.Contains(p)
+}
+
+// applyBounds enforces the index's bounds on a point being inserted.
+func (a *Axdex[T]) applyBounds(p *Point[T]) {
+	if a.bounds == nil {
+		return
+	}
+	if !finite(p.X) || !finite(p.Y) {
+		panic(fmt.Sprintf("Cannot insert point %s with a coordinate that isn't finite.", p))
+	}
+	if a.bounds.Contains(p) {
+		return
+	}
+
+	b := a.bounds
+	switch a.boundsMode {
+	case BoundsClamp:
+		p.X = clamp(p.X, b.Min.X, b.Max.X)
+		p.Y = clamp(p.Y, b.Min.Y, b.Max.Y)
+	case BoundsWrap:
+		p.X = wrap(p.X, b.Min.X, b.Max.X)
+		p.Y = wrap(p.Y, b.Min.Y, b.Max.Y)
+	default:
+		panic(fmt.Sprintf("Cannot insert point %s outside of the world bounds.", p))
+	}
+}
+
+// clamp returns v limited to the range [min, max].
+func clamp[T Float](v, min, max T) T {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+
+	return v
+}
+
+// wrap returns v wrapped into the range [min, max).
+func wrap[T Float](v, min, max T) T {
+	if v >= min && v < max {
+		return v
+	}
+
+	width := float64(max - min)
+	if width <= 0 {
+		return min
+	}
+
+	offset := math.Mod(float64(v-min), width)
+	if offset < 0 {
+		offset += width
+	}
+
+	return min + T(offset)
+}
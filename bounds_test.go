@@ -0,0 +1,43 @@
+package microspace
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBounds(t *testing.T) {
+	world := Rect[float32]{Min: Point[float32]{0, 0}, Max: Point[float32]{10, 10}}
+
+	idx := NewAxdex[float32](2)
+	idx.SetBounds(world, BoundsReject)
+	assert.True(t, idx.InBounds(&Point[float32]{10, 0}))
+	assert.NotPanics(t, func() { idx.Insert(&Point[float32]{5, 5}) })
+	assert.Panics(t, func() { idx.Insert(&Point[float32]{5, 11}) })
+	assert.Len(t, idx.Points(), 1)
+
+	idx = NewAxdex[float32](1)
+	idx.SetBounds(world, BoundsClamp)
+	p := &Point[float32]{-3, 1e30}
+	idx.Insert(p)
+	assert.Equal(t, Point[float32]{0, 10}, *p)
+
+	idx = NewAxdex[float32](2)
+	idx.SetBounds(world, BoundsWrap)
+	p, q := &Point[float32]{12, -1}, &Point[float32]{10, 25}
+	idx.Insert(p)
+	idx.Insert(q)
+	assert.Equal(t, Point[float32]{2, 9}, *p)
+	assert.Equal(t, Point[float32]{0, 5}, *q)
+
+	// Coordinates that escaped to infinity have nowhere to be moved to.
+	for _, mode := range []BoundsMode{BoundsReject, BoundsClamp, BoundsWrap} {
+		idx = NewAxdex[float32](1)
+		idx.SetBounds(world, mode)
+		nan, inf := float32(math.NaN()), float32(math.Inf(-1))
+		assert.Panics(t, func() { idx.Insert(&Point[float32]{nan, 5}) })
+		assert.Panics(t, func() { idx.Insert(&Point[float32]{5, inf}) })
+		assert.Empty(t, idx.Points())
+	}
+}
@@ -0,0 +1,271 @@
+package microspace
+
+import "sort"
+
+// Pair is a pair of overlapping boxes in a Broadphase, with A < B.
+type Pair struct {
+	A, B Handle
+}
+
+// PairEvent is sent when a pair of boxes starts or stops overlapping.
+type PairEvent struct {
+	Pair
+	// Added is true if the boxes started overlapping, and false if they
+	// stopped.
+	Added bool
+}
+
+// Broadphase finds the overlapping pairs among a set of moving
+// axis-aligned boxes, as the broadphase of collision detection, using
+// sweep and prune. The minimum and maximum edges of the boxes are kept
+// sorted along both axes. When a box moves its edges are shifted into
+// place, and each edge they pass is where a pair may start or stop
+// overlapping. Since boxes usually move a little between steps, each move
+// only passes a few edges, and the set of overlapping pairs is kept up to
+// date without testing every pair.
+type Broadphase[T Float] struct {
+	boxes []broadBox[T]
+	// ends holds the edges of the boxes along each axis, sorted.
+	ends    [2][]boxEnd[T]
+	pairs   map[Pair]struct{}
+	handler func(PairEvent)
+}
+
+// broadBox is a box in the broadphase, with the slots of its edges.
+type broadBox[T Float] struct {
+	rect Rect[T]
+	// slots holds the positions of its minimum and maximum edges along
+	// each axis, or -1 once it's been removed.
+	slots [2][2]int
+}
+
+// boxEnd is an edge of a box along an axis.
+type boxEnd[T Float] struct {
+	value T
+	box   Handle
+	max   bool
+}
+
+// less returns whether the edge is sorted before the other. Minimum
+// edges are sorted before maximum edges at the same value, so boxes
+// which only touch overlap.
+func (e boxEnd[T]) less(other boxEnd[T]) bool {
+	return e.value < other.value || (e.value == other.value && !e.max && other.max)
+}
+
+// NewBroadphase returns a new, empty broadphase. The handler is called
+// with each pair that starts or stops overlapping, as it happens, and may
+// be nil.
+func NewBroadphase[T Float](handler func(PairEvent)) *Broadphase[T] {
+	return &Broadphase[T]{pairs: make(map[Pair]struct{}), handler: handler}
+}
+
+// Add adds a box to the broadphase, returning its handle.
+func (b *Broadphase[T]) Add(r Rect[T]) Handle {
+	h := Handle(len(b.boxes))
+	b.boxes = append(b.boxes, broadBox[T]{rect: r})
+
+	// The edges start past every other edge and are shifted into place,
+	// finding the box's pairs on the way.
+	for axis := range b.ends {
+		for _, max := range []bool{true, false} {
+			b.ends[axis] = append(b.ends[axis], boxEnd[T]{value: b.edge(h, axis, max), box: h, max: max})
+			b.boxes[h].slots[axis][boolIndex(max)] = len(b.ends[axis]) - 1
+			b.shift(axis, len(b.ends[axis])-1)
+		}
+	}
+
+	return h
+}
+
+// Move moves the box to a new rectangle, returning false if there's no
+// such box.
+func (b *Broadphase[T]) Move(h Handle, r Rect[T]) bool {
+	if !b.has(h) {
+		return false
+	}
+
+	b.boxes[h].rect = r
+	for axis := range b.ends {
+		for _, max := range []bool{false, true} {
+			i := b.boxes[h].slots[axis][boolIndex(max)]
+			b.ends[axis][i].value = b.edge(h, axis, max)
+			b.shift(axis, i)
+		}
+	}
+
+	return true
+}
+
+// Remove removes the box, returning false if there's no such box. The
+// handler is called for each pair the box was in. The handle isn't
+// reused.
+func (b *Broadphase[T]) Remove(h Handle) bool {
+	if !b.has(h) {
+		return false
+	}
+
+	for _, pair := range b.PairsOf(h) {
+		b.unpair(pair.A, pair.B)
+	}
+
+	for axis := range b.ends {
+		ends := b.ends[axis][:0]
+		for _, e := range b.ends[axis] {
+			if e.box != h {
+				b.boxes[e.box].slots[axis][boolIndex(e.max)] = len(ends)
+				ends = append(ends, e)
+			}
+		}
+		b.ends[axis] = ends
+	}
+	b.boxes[h].slots = [2][2]int{{-1, -1}, {-1, -1}}
+
+	return true
+}
+
+// Rect returns the box's rectangle, and false if there's no such box.
+func (b *Broadphase[T]) Rect(h Handle) (Rect[T], bool) {
+	if !b.has(h) {
+		return Rect[T]{}, false
+	}
+
+	return b.boxes[h].rect, true
+}
+
+// Len returns the number of boxes in the broadphase.
+func (b *Broadphase[T]) Len() int {
+	return len(b.ends[0]) / 2
+}
+
+// Pairs returns every pair of overlapping boxes, in order.
+func (b *Broadphase[T]) Pairs() []Pair {
+	pairs := make([]Pair, 0, len(b.pairs))
+	for pair := range b.pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sortPairs(pairs)
+	return pairs
+}
+
+// PairsOf returns the pairs the box is in, in order.
+func (b *Broadphase[T]) PairsOf(h Handle) []Pair {
+	var pairs []Pair
+	for pair := range b.pairs {
+		if pair.A == h || pair.B == h {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	sortPairs(pairs)
+	return pairs
+}
+
+// has returns whether the box is in the broadphase.
+func (b *Broadphase[T]) has(h Handle) bool {
+	return h >= 0 && int(h) < len(b.boxes) && b.boxes[h].slots[0][0] != -1
+}
+
+// edge returns the value of the box's minimum or maximum edge along the
+// axis.
+func (b *Broadphase[T]) edge(h Handle, axis int, max bool) T {
+	r := &b.boxes[h].rect
+	switch {
+	case axis == 0 && !max:
+		return r.Min.X
+	case axis == 0:
+		return r.Max.X
+	case !max:
+		return r.Min.Y
+	default:
+		return r.Max.Y
+	}
+}
+
+// shift moves the edge in slot `i` along the axis to its sorted place.
+// Passing the minimum edge of another box past its maximum edge, or the
+// reverse, is where the boxes may start or stop overlapping.
+func (b *Broadphase[T]) shift(axis, i int) {
+	ends := b.ends[axis]
+	e := ends[i]
+	for ; i > 0 && e.less(ends[i-1]); i-- {
+		other := ends[i-1]
+		switch {
+		case !e.max && other.max:
+			b.pairIfOverlapping(e.box, other.box)
+		case e.max && !other.max:
+			b.unpair(e.box, other.box)
+		}
+		b.place(axis, i, other)
+	}
+	for ; i < len(ends)-1 && ends[i+1].less(e); i++ {
+		other := ends[i+1]
+		switch {
+		case e.max && !other.max:
+			b.pairIfOverlapping(e.box, other.box)
+		case !e.max && other.max:
+			b.unpair(e.box, other.box)
+		}
+		b.place(axis, i, other)
+	}
+	b.place(axis, i, e)
+}
+
+// place puts the edge in slot `i` along the axis.
+func (b *Broadphase[T]) place(axis, i int, e boxEnd[T]) {
+	b.ends[axis][i] = e
+	b.boxes[e.box].slots[axis][boolIndex(e.max)] = i
+}
+
+// pairIfOverlapping adds the pair of boxes if they overlap and aren't
+// already paired.
+func (b *Broadphase[T]) pairIfOverlapping(h1, h2 Handle) {
+	pair := newPair(h1, h2)
+	if _, ok := b.pairs[pair]; ok || h1 == h2 || !b.boxes[h1].rect.overlaps(&b.boxes[h2].rect) {
+		return
+	}
+
+	b.pairs[pair] = struct{}{}
+	if b.handler != nil {
+		b.handler(PairEvent{Pair: pair, Added: true})
+	}
+}
+
+// unpair removes the pair of boxes if they're paired.
+func (b *Broadphase[T]) unpair(h1, h2 Handle) {
+	pair := newPair(h1, h2)
+	if _, ok := b.pairs[pair]; !ok {
+		return
+	}
+
+	delete(b.pairs, pair)
+	if b.handler != nil {
+		b.handler(PairEvent{Pair: pair})
+	}
+}
+
+// newPair returns the pair of the boxes, in order.
+func newPair(h1, h2 Handle) Pair {
+	if h1 > h2 {
+		h1, h2 = h2, h1
+	}
+
+	return Pair{A: h1, B: h2}
+}
+
+// sortPairs sorts the pairs by their first box, then their second.
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].A < pairs[j].A || (pairs[i].A == pairs[j].A && pairs[i].B < pairs[j].B)
+	})
+}
+
+// boolIndex returns 1 for true and 0 for false.
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,108 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomBox returns a small box somewhere in the unit square.
+func randomBox() Rect[float64] {
+	x, y := rand.Float64(), rand.Float64()
+	return Rect[float64]{Min: Point[float64]{x, y}, Max: Point[float64]{x + rand.Float64()*0.1, y + rand.Float64()*0.1}}
+}
+
+func TestBroadphase(t *testing.T) {
+	tracked := map[Pair]bool{}
+	b := NewBroadphase[float64](func(e PairEvent) {
+		assert.NotEqual(t, e.Added, tracked[e.Pair])
+		tracked[e.Pair] = e.Added
+	})
+
+	boxes := map[Handle]Rect[float64]{}
+	for i := 0; i < 100; i++ {
+		r := randomBox()
+		boxes[b.Add(r)] = r
+	}
+
+	for step := 0; step < 50; step++ {
+		for h, r := range boxes {
+			switch rand.Intn(20) {
+			case 0:
+				assert.True(t, b.Remove(h))
+				delete(boxes, h)
+			case 1:
+				r = randomBox()
+				boxes[b.Add(r)] = r
+			default:
+				dx, dy := (rand.Float64()-0.5)*0.02, (rand.Float64()-0.5)*0.02
+				r = Rect[float64]{Min: Point[float64]{r.Min.X + dx, r.Min.Y + dy}, Max: Point[float64]{r.Max.X + dx, r.Max.Y + dy}}
+				assert.True(t, b.Move(h, r))
+				boxes[h] = r
+			}
+		}
+
+		var expected []Pair
+		for h1, r1 := range boxes {
+			for h2, r2 := range boxes {
+				if h1 < h2 && r1.overlaps(&r2) {
+					expected = append(expected, Pair{h1, h2})
+				}
+			}
+		}
+		sortPairs(expected)
+
+		pairs := b.Pairs()
+		assert.Equal(t, expected, pairs)
+		assert.Equal(t, len(boxes), b.Len())
+		for pair, added := range tracked {
+			if !added {
+				delete(tracked, pair)
+			}
+		}
+		assert.Len(t, tracked, len(pairs))
+	}
+}
+
+func TestBroadphaseTouching(t *testing.T) {
+	var events []PairEvent
+	b := NewBroadphase[float32](func(e PairEvent) { events = append(events, e) })
+	a := b.Add(Rect[float32]{Max: Point[float32]{1, 1}})
+	c := b.Add(Rect[float32]{Min: Point[float32]{1, 0}, Max: Point[float32]{2, 1}})
+	assert.Equal(t, []Pair{{a, c}}, b.Pairs())
+
+	assert.True(t, b.Move(c, Rect[float32]{Min: Point[float32]{1.5, 0}, Max: Point[float32]{2, 1}}))
+	assert.Empty(t, b.Pairs())
+	assert.Equal(t, []PairEvent{{Pair{a, c}, true}, {Pair{a, c}, false}}, events)
+
+	r, ok := b.Rect(c)
+	assert.True(t, ok)
+	assert.Equal(t, float32(1.5), r.Min.X)
+
+	assert.True(t, b.Remove(a))
+	assert.False(t, b.Remove(a))
+	assert.False(t, b.Move(a, Rect[float32]{}))
+	_, ok = b.Rect(a)
+	assert.False(t, ok)
+	assert.Equal(t, 1, b.Len())
+}
+
+func BenchmarkBroadphaseStep(b *testing.B) {
+	bp := NewBroadphase[float64](nil)
+	boxes := make([]Rect[float64], 10000)
+	for i := range boxes {
+		boxes[i] = randomBox()
+		boxes[i].Max.X, boxes[i].Max.Y = boxes[i].Min.X+0.005, boxes[i].Min.Y+0.005
+		bp.Add(boxes[i])
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for h := range boxes {
+			d := (rand.Float64() - 0.5) * 0.001
+			boxes[h].Min.X, boxes[h].Max.X = boxes[h].Min.X+d, boxes[h].Max.X+d
+			bp.Move(Handle(h), boxes[h])
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package microspace
+
+import (
+	"math"
+	"sync"
+)
+
+// Budget wraps an index and limits the work its queries may do each frame,
+// measured in candidate points scanned. Once a frame's budget runs out,
+// queries return the results cached for the same query in this or the
+// previous frame, or else the best results they can find with whatever
+// budget is left.
+type Budget[T Float] struct {
+	index    *Axdex[T]
+	perFrame int
+
+	mu       sync.Mutex
+	spent    int
+	cache    map[budgetKey[T]][]*Point[T]
+	previous map[budgetKey[T]][]*Point[T]
+}
+
+// budgetKey identifies a query in the Budget's cache.
+type budgetKey[T Float] struct {
+	p   *Point[T]
+	n   int
+	max T
+}
+
+// NewBudget returns a budget allowing the index to scan `perFrame`
+// candidate points per frame.
+func NewBudget[T Float](index *Axdex[T], perFrame int) *Budget[T] {
+	return &Budget[T]{
+		index:    index,
+		perFrame: perFrame,
+		cache:    map[budgetKey[T]][]*Point[T]{},
+	}
+}
+
+var _ Index[float32] = new(Budget[float32])
+
+// NearestN implements Index.NearestN, within the frame's budget.
+func (b *Budget[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := budgetKey[T]{p: p, n: n, max: max}
+	if b.spent >= b.perFrame {
+		if cached, ok := b.cached(key); ok {
+			return cached
+		}
+	}
+
+	list, scanned, truncated := b.index.nearestScan(p, n, max, b.perFrame-b.spent)
+	results := list.Sorted()
+	b.spent += scanned
+	if !truncated {
+		b.cache[key] = results
+	} else if cached, ok := b.cached(key); ok {
+		return cached
+	}
+
+	return results
+}
+
+// cached returns the results last cached for the query.
+func (b *Budget[T]) cached(key budgetKey[T]) ([]*Point[T], bool) {
+	if results, ok := b.cache[key]; ok {
+		return results, true
+	}
+
+	results, ok := b.previous[key]
+	return results, ok
+}
+
+// Points implements Index.Points
+func (b *Budget[T]) Points() []*Point[T] {
+	return b.index.Points()
+}
+
+// Spent returns the number of candidates scanned so far this frame.
+func (b *Budget[T]) Spent() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// Exhausted returns true once this frame's budget has been used up.
+func (b *Budget[T]) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent >= b.perFrame
+}
+
+// EndFrame resets the budget for the next frame. Results cached during
+// the frame stay available for one more frame.
+func (b *Budget[T]) EndFrame() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.spent = 0
+	b.previous = b.cache
+	b.cache = map[budgetKey[T]][]*Point[T]{}
+}
+
+// nearestScan finds up to the `n` nearest neighbors of p within `max`,
+// scanning at most `limit` candidates along the axis, where a negative
+// limit is unbounded. It returns how many candidates were scanned, and
+// whether the scan stopped at the limit with results possibly missing.
+func (a *Axdex[T]) nearestScan(p *Point[T], n int, max T, limit int) (results *neighborList[T], scanned int, truncated bool) {
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return &neighborList[T]{}, 0, false
+	}
+
+	results, scanned, truncated, _ = a.axis.nearest(p, n, max, limit)
+	return results, scanned, truncated
+}
+
+// nearest is the scan behind Axdex.nearestScan, run along this axis. `n`
+// must be positive. It also returns the least distance beyond `max` that
+// a point left out could be at, which is infinite once every point has
+// been scanned and found within `max`.
+func (a *axis[T]) nearest(p *Point[T], n int, max T, limit int) (results *neighborList[T], scanned int, truncated bool, beyond T) {
+	max = searchRadius(max)
+	if !a.sorted {
+		a.runSort()
+	}
+
+	c := a.cursor(a.ValueFor(p))
+	list := newNeighborList[T](n)
+	beyondSqr := T(math.Inf(1))
+	for {
+		i, gap, ok := c.Next()
+		if !ok {
+			break
+		}
+		if gap > max || (list.Full() && gap*gap >= list.Worst()) {
+			beyondSqr = min(beyondSqr, gap*gap)
+			break
+		}
+		if limit >= 0 && scanned >= limit {
+			return list, scanned, true, gap
+		}
+
+		scanned++
+
+		q := a.data[i].p
+		if d := q.DistanceToSqr(p); d <= max*max {
+			list.Insert(q, d)
+		} else {
+			beyondSqr = min(beyondSqr, d)
+		}
+	}
+
+	return list, scanned, false, T(math.Sqrt(float64(beyondSqr)))
+}
@@ -0,0 +1,34 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	idx := NewAxdex[float32](10)
+	for i := 0; i < 10; i++ {
+		idx.Insert(&Point[float32]{float32(i), 0})
+	}
+
+	b := NewBudget(idx, 6)
+	p := idx.Points()[5]
+	full := idx.NearestNAt(0, p, 3, 10)
+
+	assert.Equal(t, full, b.NearestN(p, 3, 10))
+	assert.Equal(t, 3, b.Spent())
+
+	// The second query runs out of budget part way through.
+	q := idx.Points()[0]
+	assert.Len(t, b.NearestN(q, 5, 10), 3)
+	assert.True(t, b.Exhausted())
+
+	// Once exhausted, cached queries are still answered in full.
+	assert.Equal(t, full, b.NearestN(p, 3, 10))
+	assert.Empty(t, b.NearestN(q, 5, 10))
+
+	b.EndFrame()
+	assert.Equal(t, 0, b.Spent())
+	assert.Equal(t, full, b.NearestN(p, 3, 10))
+}
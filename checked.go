@@ -0,0 +1,127 @@
+package microspace
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrFrozen is returned when writing to an index that has been frozen.
+	ErrFrozen = errors.New("microspace: index is frozen")
+	// ErrNotFound is returned when a point isn't in the index.
+	ErrNotFound = errors.New("microspace: point not found")
+	// ErrInvalidCoordinate is returned for points with NaN or infinite
+	// coordinates, and for points outside of an index's world bounds.
+	ErrInvalidCoordinate = errors.New("microspace: invalid coordinate")
+)
+
+// Index2 is a spatial index whose methods report misuse as errors rather
+// than panicking or silently doing nothing, for code such as servers that
+// can't trust its input.
+type Index2[T Float] interface {
+	// Insert adds a point to the index.
+	Insert(p *Point[T]) error
+	// Remove removes a point from the index.
+	Remove(p *Point[T]) error
+	// Update moves a point in the index to (x, y).
+	Update(p *Point[T], x, y T) error
+	// NearestN returns up the `n` nearest neighbors of the point, as
+	// Index.NearestN does.
+	NearestN(p *Point[T], n int, max T) ([]*Point[T], error)
+	// Points returns all points contained in the spatial index.
+	Points() []*Point[T]
+}
+
+// Checked wraps an Axdex to implement Index2, validating every call
+// before passing it on.
+type Checked[T Float] struct {
+	index  *Axdex[T]
+	frozen bool
+}
+
+var _ Index2[float32] = new(Checked[float32])
+
+// NewChecked returns an Index2 over the index. The index shouldn't be
+// written to directly anymore, or the checks may be bypassed.
+func NewChecked[T Float](index *Axdex[T]) *Checked[T] {
+	return &Checked[T]{index: index}
+}
+
+// Freeze stops the index from accepting any more writes, which return
+// ErrFrozen from then on. Queries are unaffected.
+func (c *Checked[T]) Freeze() {
+	c.frozen = true
+}
+
+// Insert implements Index2.Insert
+func (c *Checked[T]) Insert(p *Point[T]) error {
+	if c.frozen {
+		return ErrFrozen
+	}
+	if err := c.validate(*p); err != nil {
+		return err
+	}
+
+	c.index.Insert(p)
+	return nil
+}
+
+// Remove implements Index2.Remove
+func (c *Checked[T]) Remove(p *Point[T]) error {
+	if c.frozen {
+		return ErrFrozen
+	}
+	if !c.index.Remove(p) {
+		return fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+
+	return nil
+}
+
+// Update implements Index2.Update
+func (c *Checked[T]) Update(p *Point[T], x, y T) error {
+	if c.frozen {
+		return ErrFrozen
+	}
+	if err := c.validate(Point[T]{X: x, Y: y}); err != nil {
+		return err
+	}
+	if !c.index.Update(p, x, y) {
+		return fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+
+	return nil
+}
+
+// NearestN implements Index2.NearestN
+func (c *Checked[T]) NearestN(p *Point[T], n int, max T) ([]*Point[T], error) {
+	if !finite(p.X) || !finite(p.Y) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCoordinate, p)
+	}
+
+	return c.index.NearestN(p, n, max), nil
+}
+
+// Points implements Index2.Points
+func (c *Checked[T]) Points() []*Point[T] {
+	return c.index.Points()
+}
+
+// validate returns an error if the point can't be written to the index:
+// if it isn't finite, or lies outside bounds that reject it.
+func (c *Checked[T]) validate(p Point[T]) error {
+	if !finite(p.X) || !finite(p.Y) {
+		return fmt.Errorf("%w: %s", ErrInvalidCoordinate, &p)
+	}
+	if !c.index.InBounds(&p) && c.index.boundsMode == BoundsReject {
+		return fmt.Errorf("%w: %s is outside of the world bounds", ErrInvalidCoordinate, &p)
+	}
+
+	return nil
+}
+
+// finite returns true if the value is neither NaN nor infinite.
+func finite[T Float](v T) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
@@ -0,0 +1,56 @@
+package microspace
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecked(t *testing.T) {
+	idx := NewChecked(NewAxdex[float64](4))
+	a, b := &Point[float64]{1, 1}, &Point[float64]{2, 2}
+
+	assert.NoError(t, idx.Insert(a))
+	assert.NoError(t, idx.Insert(b))
+	assert.ErrorIs(t, idx.Insert(&Point[float64]{math.NaN(), 0}), ErrInvalidCoordinate)
+	assert.ErrorIs(t, idx.Insert(&Point[float64]{0, math.Inf(1)}), ErrInvalidCoordinate)
+
+	found, err := idx.NearestN(&Point[float64]{0, 0}, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Point[float64]{a}, found)
+	_, err = idx.NearestN(&Point[float64]{math.NaN(), 0}, 1, 0)
+	assert.ErrorIs(t, err, ErrInvalidCoordinate)
+
+	// Inserting after a query is allowed.
+	c := &Point[float64]{3, 3}
+	assert.NoError(t, idx.Insert(c))
+
+	assert.NoError(t, idx.Update(c, 0, 0))
+	assert.ErrorIs(t, idx.Update(c, math.NaN(), 0), ErrInvalidCoordinate)
+	assert.ErrorIs(t, idx.Update(&Point[float64]{}, 1, 1), ErrNotFound)
+
+	assert.NoError(t, idx.Remove(a))
+	assert.ErrorIs(t, idx.Remove(a), ErrNotFound)
+	assert.Equal(t, []*Point[float64]{b, c}, idx.Points())
+
+	idx.Freeze()
+	assert.ErrorIs(t, idx.Insert(&Point[float64]{}), ErrFrozen)
+	assert.ErrorIs(t, idx.Update(b, 1, 1), ErrFrozen)
+	assert.ErrorIs(t, idx.Remove(b), ErrFrozen)
+	found, err = idx.NearestN(&Point[float64]{0, 0}, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Point[float64]{c}, found)
+}
+
+func TestCheckedBounds(t *testing.T) {
+	a := NewAxdex[float64](1)
+	a.SetBounds(Rect[float64]{Max: Point[float64]{10, 10}}, BoundsReject)
+	idx := NewChecked(a)
+
+	p := &Point[float64]{5, 5}
+	assert.NoError(t, idx.Insert(p))
+	assert.ErrorIs(t, idx.Insert(&Point[float64]{11, 5}), ErrInvalidCoordinate)
+	assert.ErrorIs(t, idx.Update(p, -1, 5), ErrInvalidCoordinate)
+	assert.Equal(t, Point[float64]{5, 5}, *p)
+}
@@ -0,0 +1,80 @@
+package microspace
+
+// Clone returns an independent copy of the index, holding the same points,
+// which may be queried and changed without affecting the original. The
+// points themselves are shared, so they must not be moved in place, such
+// as with Update or ApplyWithin, while the other index is in use; see
+// DeepCopy for that.
+func (a *Axdex[T]) Clone() *Axdex[T] {
+	clone, _ := a.clone(false)
+	return clone
+}
+
+// DeepCopy is like Clone, but also copies the points, so the copy is
+// unaffected by anything done to the original. Queries against the copy
+// return its own points.
+func (a *Axdex[T]) DeepCopy() *Axdex[T] {
+	clone, _ := a.clone(true)
+	return clone
+}
+
+// clone returns a sorted copy of the index, with its settings and the
+// state attached to its points, other than statistics. If `deep` is set,
+// the points are copied too, and the map from the original points to
+// their copies is returned.
+func (a *Axdex[T]) clone(deep bool) (*Axdex[T], map[*Point[T]]*Point[T]) {
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	c := newAxdex(uint(len(a.points)), a.along, a.axis.value)
+	c.boundsMode, c.dual, c.maxCandidates, c.metric = a.boundsMode, a.dual, a.maxCandidates, a.metric
+	if a.bounds != nil {
+		bounds := *a.bounds
+		c.bounds = &bounds
+	}
+
+	c.axis.data = append(c.axis.data, a.axis.data...)
+	c.points = append(c.points, a.points...)
+
+	var copyOf map[*Point[T]]*Point[T]
+	if deep {
+		copyOf = make(map[*Point[T]]*Point[T], len(a.points))
+		copies := make([]Point[T], len(a.points))
+		for i, ap := range c.axis.data {
+			copies[i] = *ap.p
+			copyOf[ap.p] = &copies[i]
+			c.axis.data[i].p = &copies[i]
+		}
+		for i, p := range c.points {
+			c.points[i] = copyOf[p]
+		}
+	}
+	key := func(p *Point[T]) *Point[T] {
+		if deep {
+			return copyOf[p]
+		}
+		return p
+	}
+
+	c.maxSpeedSqr = a.maxSpeedSqr
+	if a.velocities != nil {
+		c.velocities = make(map[*Point[T]]Point[T], len(a.velocities))
+		for p, v := range a.velocities {
+			c.velocities[key(p)] = v
+		}
+	}
+	if a.versions != nil {
+		c.versions = make(map[*Point[T]]uint64, len(a.versions))
+		for p, v := range a.versions {
+			c.versions[key(p)] = v
+		}
+	}
+
+	c.axis.markSorted()
+	if a.cross != nil {
+		c.crossAxis()
+	}
+
+	return c, copyOf
+}
@@ -0,0 +1,44 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	idx := NewAxdex[float64](3)
+	a, b := &Point[float64]{1, 1}, &Point[float64]{5, 5}
+	idx.Insert(a)
+	idx.Insert(b)
+	idx.SetVelocity(b, Point[float64]{1, 0})
+
+	clone := idx.Clone()
+	c := &Point[float64]{2, 2}
+	clone.Insert(c)
+	assert.True(t, clone.Remove(a))
+
+	assert.Equal(t, []*Point[float64]{a, b}, idx.Points())
+	assert.Equal(t, []*Point[float64]{b, c}, clone.Points())
+	assert.Equal(t, []*Point[float64]{a}, idx.NearestN(&Point[float64]{0, 0}, 1, 0))
+	assert.Equal(t, []*Point[float64]{c}, clone.NearestN(&Point[float64]{0, 0}, 1, 0))
+	assert.Equal(t, Point[float64]{1, 0}, clone.VelocityOf(b))
+}
+
+func TestDeepCopy(t *testing.T) {
+	idx := NewAxdex[float64](3)
+	idx.EnableDualAxis()
+	a, b := &Point[float64]{1, 1}, &Point[float64]{5, 5}
+	idx.Insert(a)
+	idx.Insert(b)
+	idx.Update(a, 2, 2)
+
+	deep := idx.DeepCopy()
+	idx.Update(a, 10, 10)
+
+	found := deep.NearestN(&Point[float64]{0, 0}, 2, 0)
+	assert.Equal(t, []*Point[float64]{{2, 2}, {5, 5}}, found)
+	assert.True(t, found[0] != a)
+	assert.Equal(t, idx.VersionOf(a)-1, deep.VersionOf(found[0]))
+	assert.False(t, deep.Remove(a))
+}
@@ -0,0 +1,125 @@
+package microspace
+
+import (
+	"math"
+	"slices"
+)
+
+// ClosestPair returns the two points in the index nearest to each other,
+// and the distance between them, or nil points if there are fewer than
+// two. It divides the points by X and sweeps the strip along each
+// division for pairs across it, taking O(n log n) time rather than
+// comparing every pair.
+func (a *Axdex[T]) ClosestPair() (*Point[T], *Point[T], T) {
+	if len(a.points) < 2 {
+		return nil, nil, 0
+	}
+
+	points := a.pointsByX()
+	c := closestPair[T]{best: T(math.Inf(1)), buf: make([]*Point[T], len(points))}
+	c.search(points)
+	return c.p, c.q, T(math.Sqrt(float64(c.best)))
+}
+
+// pointsByX returns a copy of the points sorted by X, using the sorted
+// axis if that's what it's sorted by.
+func (a *Axdex[T]) pointsByX() []*Point[T] {
+	points := make([]*Point[T], len(a.points))
+	if a.along == AxisX {
+		if !a.axis.sorted {
+			a.axis.runSort()
+		}
+		for i, ap := range a.axis.data {
+			points[i] = ap.p
+		}
+		return points
+	}
+
+	copy(points, a.points)
+	slices.SortFunc(points, func(p, q *Point[T]) int {
+		switch {
+		case p.X < q.X:
+			return -1
+		case p.X > q.X:
+			return 1
+		}
+		return 0
+	})
+
+	return points
+}
+
+// closestPair holds the closest pair found so far.
+type closestPair[T Float] struct {
+	p, q *Point[T]
+	best T
+	// buf is scratch space for merging and for the strip.
+	buf []*Point[T]
+}
+
+// consider records the pair if it's the closest yet.
+func (c *closestPair[T]) consider(p, q *Point[T]) {
+	if d := p.DistanceToSqr(q); d < c.best {
+		c.p, c.q, c.best = p, q, d
+	}
+}
+
+// search finds the closest pair among the points, which are sorted by X,
+// and leaves them sorted by Y.
+func (c *closestPair[T]) search(points []*Point[T]) {
+	if len(points) <= 3 {
+		for i := range points {
+			for j := i + 1; j < len(points); j++ {
+				c.consider(points[i], points[j])
+			}
+		}
+		slices.SortFunc(points, byY[T])
+		return
+	}
+
+	mid := len(points) / 2
+	split := points[mid].X
+	c.search(points[:mid])
+	c.search(points[mid:])
+
+	merged := c.buf[:0]
+	left, right := points[:mid], points[mid:]
+	for len(left) > 0 && len(right) > 0 {
+		if right[0].Y < left[0].Y {
+			merged, right = append(merged, right[0]), right[1:]
+		} else {
+			merged, left = append(merged, left[0]), left[1:]
+		}
+	}
+	merged = append(append(merged, left...), right...)
+	copy(points, merged)
+
+	// Only pairs across the split closer than the best so far are left,
+	// which lie in a narrow strip along it, and within the strip only
+	// points close by Y need comparing.
+	strip := c.buf[:0]
+	for _, p := range points {
+		if dx := p.X - split; dx*dx < c.best {
+			strip = append(strip, p)
+		}
+	}
+	for i, p := range strip {
+		for _, q := range strip[i+1:] {
+			if dy := q.Y - p.Y; dy*dy >= c.best {
+				break
+			}
+			c.consider(p, q)
+		}
+	}
+}
+
+// byY orders points by their Y coordinate.
+func byY[T Float](p, q *Point[T]) int {
+	switch {
+	case p.Y < q.Y:
+		return -1
+	case p.Y > q.Y:
+		return 1
+	}
+	return 0
+}
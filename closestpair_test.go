@@ -0,0 +1,54 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosestPair(t *testing.T) {
+	for _, along := range []Axis{AxisX, AxisY} {
+		idx := NewAxdexOnAxis[float32](0, along)
+		p, q, d := idx.ClosestPair()
+		assert.Nil(t, p)
+		assert.Nil(t, q)
+		assert.Equal(t, float32(0), d)
+
+		for _, r := range randomPoints(2000) {
+			idx.Insert(r)
+		}
+
+		best := float32(-1)
+		for i, a := range idx.Points() {
+			for _, b := range idx.Points()[i+1:] {
+				if d := a.DistanceToSqr(b); best < 0 || d < best {
+					best = d
+				}
+			}
+		}
+
+		p, q, d = idx.ClosestPair()
+		assert.NotEqual(t, p, q)
+		assert.Equal(t, best, p.DistanceToSqr(q))
+		assert.InDelta(t, best, d*d, 1e-9)
+		assert.Len(t, idx.Points(), 2000)
+	}
+
+	// Points sharing X are compared through the strip.
+	idx := NewAxdex[float64](0)
+	for _, y := range []float64{0, 5, 2, 9, 2.5, 7} {
+		idx.Insert(&Point[float64]{1, y})
+	}
+	p, q, d := idx.ClosestPair()
+	assert.Equal(t, 0.5, d)
+	assert.ElementsMatch(t, []float64{2, 2.5}, []float64{p.Y, q.Y})
+}
+
+func BenchmarkClosestPair(b *testing.B) {
+	idx := generateIndex(100000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		idx.ClosestPair()
+	}
+}
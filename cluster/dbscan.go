@@ -0,0 +1,96 @@
+// Package cluster groups the points of a microspace index into clusters,
+// using the index to find each point's neighbors rather than comparing
+// every pair of points.
+package cluster
+
+import "github.com/galaxyblack/microspace"
+
+// Noise is the label of points which don't belong to any cluster.
+const Noise = -1
+
+// unvisited is the label of points DBSCAN hasn't reached yet.
+const unvisited = -2
+
+// radiusIndex is an index which can visit the points within a radius
+// without ordering them, such as Axdex.
+type radiusIndex[T microspace.Float] interface {
+	WithinRadius(p *microspace.Point[T], r T, fn func(*microspace.Point[T]) bool)
+}
+
+// DBSCAN clusters the points in the index by density. Points with at
+// least `minPoints` points within `eps` of them, counting themselves, are
+// core points; core points within `eps` of each other share a cluster,
+// along with the other points within `eps` of them. It returns the label
+// of each point, in the order of the index's Points, which is the number
+// of its cluster from 0, or Noise, and the number of clusters.
+//
+// Indexes with a WithinRadius method, such as Axdex, are searched with it,
+// and others with NearestN.
+func DBSCAN[T microspace.Float](idx microspace.Index[T], eps T, minPoints int) (labels []int, clusters int) {
+	if eps <= 0 {
+		panic("Cannot cluster points within a distance of 0 or less.")
+	}
+
+	points := idx.Points()
+	slots := make(map[*microspace.Point[T]]int, len(points))
+	labels = make([]int, len(points))
+	for i, p := range points {
+		slots[p] = i
+		labels[i] = unvisited
+	}
+
+	var neighbors []int
+	region := func(p *microspace.Point[T]) []int {
+		neighbors = neighbors[:0]
+		if ri, ok := idx.(radiusIndex[T]); ok {
+			ri.WithinRadius(p, eps, func(q *microspace.Point[T]) bool {
+				neighbors = append(neighbors, slots[q])
+				return true
+			})
+		} else {
+			for _, q := range idx.NearestN(p, -1, eps) {
+				neighbors = append(neighbors, slots[q])
+			}
+		}
+		return neighbors
+	}
+
+	var queue []int
+	for i, p := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		if len(region(p)) < minPoints {
+			labels[i] = Noise
+			continue
+		}
+
+		// Grow the cluster out from the core point, through every core
+		// point reachable from it. Points first seen as noise are on its
+		// border.
+		cluster := clusters
+		clusters++
+		labels[i] = cluster
+		queue = append(queue[:0], neighbors...)
+		for len(queue) > 0 {
+			j := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+
+			switch labels[j] {
+			case Noise:
+				labels[j] = cluster
+				continue
+			case unvisited:
+				labels[j] = cluster
+			default:
+				continue
+			}
+
+			if len(region(points[j])) >= minPoints {
+				queue = append(queue, neighbors...)
+			}
+		}
+	}
+
+	return labels, clusters
+}
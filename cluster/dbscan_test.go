@@ -0,0 +1,65 @@
+package cluster
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/galaxyblack/microspace"
+	"github.com/stretchr/testify/assert"
+)
+
+// blobs returns `n` points scattered around each of the centers, followed
+// by the outliers.
+func blobs(centers []microspace.Point[float64], n int, spread float64, outliers ...microspace.Point[float64]) []*microspace.Point[float64] {
+	var points []*microspace.Point[float64]
+	for _, c := range centers {
+		for i := 0; i < n; i++ {
+			points = append(points, &microspace.Point[float64]{X: c.X + rand.NormFloat64()*spread, Y: c.Y + rand.NormFloat64()*spread})
+		}
+	}
+	for _, o := range outliers {
+		points = append(points, &microspace.Point[float64]{X: o.X, Y: o.Y})
+	}
+
+	return points
+}
+
+func TestDBSCAN(t *testing.T) {
+	points := blobs([]microspace.Point[float64]{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}}, 100, 0.3, microspace.Point[float64]{X: 5, Y: 5}, microspace.Point[float64]{X: -8, Y: -8})
+
+	axdex := microspace.NewAxdex[float64](0)
+	for _, p := range points {
+		axdex.Insert(p)
+	}
+
+	for _, idx := range []microspace.Index[float64]{axdex, microspace.NewKDTree(points)} {
+		labels, clusters := DBSCAN(idx, 1, 5)
+		assert.Equal(t, 3, clusters)
+		assert.Len(t, labels, len(points))
+
+		byPoint := map[*microspace.Point[float64]]int{}
+		for i, p := range idx.Points() {
+			byPoint[p] = labels[i]
+		}
+
+		// Each blob is one cluster, distinct from the others.
+		seen := map[int]bool{}
+		for blob := 0; blob < 3; blob++ {
+			label := byPoint[points[blob*100]]
+			assert.NotEqual(t, Noise, label)
+			assert.False(t, seen[label])
+			seen[label] = true
+			for _, p := range points[blob*100 : (blob+1)*100] {
+				assert.Equal(t, label, byPoint[p])
+			}
+		}
+
+		assert.Equal(t, Noise, byPoint[points[300]])
+		assert.Equal(t, Noise, byPoint[points[301]])
+	}
+
+	labels, clusters := DBSCAN[float64](microspace.NewAxdex[float64](0), 1, 5)
+	assert.Empty(t, labels)
+	assert.Equal(t, 0, clusters)
+	assert.Panics(t, func() { DBSCAN[float64](axdex, 0, 5) })
+}
@@ -0,0 +1,140 @@
+package cluster
+
+import (
+	"math/rand"
+
+	"github.com/galaxyblack/microspace"
+)
+
+// DefaultKMeansIterations is the most iterations KMeans runs when the
+// limit isn't set.
+const DefaultKMeansIterations = 100
+
+// KMeansOptions tunes KMeans. The zero value uses the defaults.
+type KMeansOptions struct {
+	// MaxIterations is the most assignment and update steps to run
+	// before giving up on the clusters settling, or
+	// DefaultKMeansIterations if it's 0.
+	MaxIterations int
+	// Rand is the source of randomness for seeding, or the global source
+	// if it's nil.
+	Rand *rand.Rand
+}
+
+// KMeans partitions the points in the index into `k` clusters around
+// centroids, seeded with KMeansPlusPlus. Each iteration assigns every
+// point to its nearest centroid, found by querying an index over the
+// centroids, and moves each centroid to the mean of its points, until no
+// point changes cluster. It returns the cluster of each point, in the
+// order of the index's Points, and the centroids. Centroids left without
+// points stay where they were.
+func KMeans[T microspace.Float](idx microspace.Index[T], k int, opts KMeansOptions) (labels []int, centroids []microspace.Point[T]) {
+	points := idx.Points()
+	centroids = KMeansPlusPlus(idx, k, opts.Rand)
+	labels = make([]int, len(points))
+	for i := range labels {
+		labels[i] = -1
+	}
+
+	iterations := opts.MaxIterations
+	if iterations <= 0 {
+		iterations = DefaultKMeansIterations
+	}
+
+	var (
+		nearest = make([]*microspace.Point[T], 1)
+		sums    = make([]microspace.Point[float64], len(centroids))
+		counts  = make([]int, len(centroids))
+	)
+	for iteration := 0; iteration < iterations; iteration++ {
+		byCentroid := microspace.NewAxdex[T](uint(len(centroids)))
+		clusterOf := make(map[*microspace.Point[T]]int, len(centroids))
+		for i := range centroids {
+			byCentroid.Insert(&centroids[i])
+			clusterOf[&centroids[i]] = i
+		}
+
+		changed := false
+		for i, p := range points {
+			byCentroid.NearestNInto(p, 0, nearest)
+			if c := clusterOf[nearest[0]]; c != labels[i] {
+				labels[i], changed = c, true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		clear(sums)
+		clear(counts)
+		for i, p := range points {
+			sums[labels[i]].X += float64(p.X)
+			sums[labels[i]].Y += float64(p.Y)
+			counts[labels[i]]++
+		}
+		for c, count := range counts {
+			if count > 0 {
+				centroids[c] = microspace.Point[T]{X: T(sums[c].X / float64(count)), Y: T(sums[c].Y / float64(count))}
+			}
+		}
+	}
+
+	return labels, centroids
+}
+
+// KMeansPlusPlus picks `k` initial centroids from the points in the index
+// for KMeans, or fewer if there are fewer points. The first is picked at
+// random, and each after it is picked with a probability proportional to
+// its squared distance from the nearest centroid picked so far, which
+// spreads the centroids out. The random source may be nil to use the
+// global source.
+func KMeansPlusPlus[T microspace.Float](idx microspace.Index[T], k int, r *rand.Rand) []microspace.Point[T] {
+	if k <= 0 {
+		panic("Cannot cluster points into fewer than one cluster.")
+	}
+
+	random, intn := rand.Float64, rand.Intn
+	if r != nil {
+		random, intn = r.Float64, r.Intn
+	}
+
+	points := idx.Points()
+	if len(points) == 0 {
+		return nil
+	}
+
+	centroids := []microspace.Point[T]{*points[intn(len(points))]}
+	dists := make([]float64, len(points))
+	total := 0.0
+	for i, p := range points {
+		dists[i] = float64(p.DistanceToSqr(&centroids[0]))
+		total += dists[i]
+	}
+
+	for len(centroids) < min(k, len(points)) {
+		// Once every point is on a centroid, the rest are duplicates.
+		next := intn(len(points))
+		if total > 0 {
+			target := random() * total
+			for i, d := range dists {
+				if d == 0 {
+					continue
+				}
+				if next = i; target < d {
+					break
+				}
+				target -= d
+			}
+		}
+
+		c := *points[next]
+		centroids = append(centroids, c)
+		total = 0
+		for i, p := range points {
+			dists[i] = min(dists[i], float64(p.DistanceToSqr(&c)))
+			total += dists[i]
+		}
+	}
+
+	return centroids
+}
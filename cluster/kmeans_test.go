@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/galaxyblack/microspace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKMeans(t *testing.T) {
+	centers := []microspace.Point[float64]{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 10}}
+	points := blobs(centers, 200, 0.5)
+	idx := microspace.NewAxdex[float64](0)
+	for _, p := range points {
+		idx.Insert(p)
+	}
+
+	labels, centroids := KMeans[float64](idx, 4, KMeansOptions{Rand: rand.New(rand.NewSource(1))})
+	assert.Len(t, labels, len(points))
+	assert.Len(t, centroids, 4)
+
+	// Every blob is its own cluster, centered near the blob's center.
+	byPoint := map[*microspace.Point[float64]]int{}
+	for i, p := range idx.Points() {
+		byPoint[p] = labels[i]
+	}
+	seen := map[int]bool{}
+	for blob, center := range centers {
+		label := byPoint[points[blob*200]]
+		assert.False(t, seen[label])
+		seen[label] = true
+		for _, p := range points[blob*200 : (blob+1)*200] {
+			assert.Equal(t, label, byPoint[p])
+		}
+		assert.Less(t, centroids[label].DistanceToSqr(&center), 0.1)
+	}
+
+	labels, centroids = KMeans[float64](idx, 4, KMeansOptions{MaxIterations: 1})
+	assert.Len(t, labels, len(points))
+	assert.Len(t, centroids, 4)
+}
+
+func TestKMeansPlusPlus(t *testing.T) {
+	idx := microspace.NewAxdex[float32](0)
+	for _, p := range []*microspace.Point[float32]{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 5, Y: 5}} {
+		idx.Insert(p)
+	}
+
+	// The distinct points are always picked before duplicates.
+	for i := 0; i < 20; i++ {
+		centroids := KMeansPlusPlus[float32](idx, 2, nil)
+		assert.ElementsMatch(t, []microspace.Point[float32]{{X: 0, Y: 0}, {X: 5, Y: 5}}, centroids)
+	}
+
+	assert.Len(t, KMeansPlusPlus[float32](idx, 5, nil), 3)
+	assert.Empty(t, KMeansPlusPlus[float32](microspace.NewAxdex[float32](0), 2, nil))
+	assert.Panics(t, func() { KMeansPlusPlus[float32](idx, 0, nil) })
+}
@@ -0,0 +1,110 @@
+// Command microspace-gen embeds a static point dataset in a Go source file
+// as a pre-sorted microspace index, so the index costs no sorting when the
+// program starts. It's meant to be run from go:generate:
+//
+//	//go:generate microspace-gen -in spawns.csv -out spawns_gen.go -pkg world -var Spawns
+//
+// The input is CSV with one "x,y" point per line.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+)
+
+func main() {
+	var (
+		in   = flag.String("in", "", "CSV file of x,y points to read")
+		out  = flag.String("out", "", "Go file to write, or stdout if empty")
+		pkg  = flag.String("pkg", "main", "package of the generated file")
+		name = flag.String("var", "Index", "name of the generated index variable")
+		typ  = flag.String("type", "float32", "coordinate type, float32 or float64")
+	)
+	flag.Parse()
+
+	bits := 32
+	if *typ == "float64" {
+		bits = 64
+	} else if *typ != "float32" {
+		log.Fatalf("unsupported coordinate type %q", *typ)
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	points, err := readPoints(f, bits)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := generate(w, *pkg, *name, *typ, points); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readPoints parses the CSV points, rounding coordinates to the bit size.
+func readPoints(r io.Reader, bits int) ([][2]float64, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([][2]float64, len(records))
+	for i, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("line %d: expected 2 fields, got %d", i+1, len(record))
+		}
+
+		for axis, field := range record {
+			if points[i][axis], err = strconv.ParseFloat(field, bits); err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+		}
+	}
+
+	return points, nil
+}
+
+// generate writes the Go source declaring the index over the points.
+func generate(w io.Writer, pkg, name, typ string, points [][2]float64) error {
+	sort.SliceStable(points, func(i, j int) bool { return points[i][0] < points[j][0] })
+
+	bits := 32
+	if typ == "float64" {
+		bits = 64
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "// Code generated by microspace-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "package %s\n\n", pkg)
+	fmt.Fprintf(b, "import \"github.com/galaxyblack/microspace\"\n\n")
+	fmt.Fprintf(b, "// %s is a pre-sorted index of %d points.\n", name, len(points))
+	fmt.Fprintf(b, "var %s = microspace.NewAxdexFromSorted([]*microspace.Point[%s]{\n", name, typ)
+	for _, p := range points {
+		fmt.Fprintf(b, "\t{X: %s, Y: %s},\n",
+			strconv.FormatFloat(p[0], 'g', -1, bits),
+			strconv.FormatFloat(p[1], 'g', -1, bits))
+	}
+	fmt.Fprintf(b, "})\n")
+
+	return b.Flush()
+}
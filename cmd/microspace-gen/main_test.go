@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	points, err := readPoints(strings.NewReader("3,1\n1.5,2\n-2,0.1\n"), 32)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, generate(&buf, "world", "Spawns", "float32", points))
+
+	formatted, err := format.Source(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, buf.String(), string(formatted))
+	assert.Contains(t, buf.String(), "var Spawns = microspace.NewAxdexFromSorted([]*microspace.Point[float32]{\n"+
+		"\t{X: -2, Y: 0.1},\n\t{X: 1.5, Y: 2},\n\t{X: 3, Y: 1},\n})\n")
+
+	_, err = readPoints(strings.NewReader("1,2,3\n"), 32)
+	assert.Error(t, err)
+}
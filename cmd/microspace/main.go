@@ -0,0 +1,301 @@
+// Command microspace builds, queries and benchmarks spatial indexes from
+// the command line, for validating datasets and comparing index
+// structures before writing any Go:
+//
+//	microspace build -in points.csv -out points.msx
+//	microspace nearest -in points.msx -at 1.5,2 -n 5
+//	microspace within -in points.msx -rect 0,0,10,10
+//	microspace within -in points.msx -at 1.5,2 -radius 3
+//	microspace bench -in points.csv -queries 10000 -n 10
+//
+// Inputs are read by extension: ".msx" files are indexes written by the
+// build command, ".csv" files hold one "x,y" point per line, and
+// ".geojson" and ".json" files hold a GeoJSON FeatureCollection.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galaxyblack/microspace"
+)
+
+// usage is printed for missing or unknown commands.
+const usage = "usage: microspace build|nearest|within|bench [flags]"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run runs the command given by the arguments, writing its output to w.
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	out := bufio.NewWriter(w)
+	var err error
+	switch args[0] {
+	case "build":
+		err = build(args[1:])
+	case "nearest":
+		err = nearest(args[1:], out)
+	case "within":
+		err = within(args[1:], out)
+	case "bench":
+		err = bench(args[1:], out)
+	default:
+		err = errors.New(usage)
+	}
+	if err != nil {
+		return err
+	}
+
+	return out.Flush()
+}
+
+// build writes the index over the input to a ".msx" file.
+func build(args []string) error {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	in := flags.String("in", "", "points to index")
+	out := flags.String("out", "", "index file to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("build: -out is required")
+	}
+
+	idx, err := load(*in)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if _, err := idx.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// nearest prints the nearest neighbors of a point, one "x,y,distance"
+// line each.
+func nearest(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("nearest", flag.ContinueOnError)
+	in := flags.String("in", "", "points or index to query")
+	at := flags.String("at", "", "query point as x,y")
+	n := flags.Int("n", 1, "number of neighbors, or -1 for all")
+	max := flags.Float64("max", 0, "maximum distance, or 0 for unlimited")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	p, err := parsePoint(*at)
+	if err != nil {
+		return err
+	}
+	idx, err := load(*in)
+	if err != nil {
+		return err
+	}
+
+	for _, nb := range microspace.NearestNWithDistance[float64](idx, &p, *n, *max) {
+		fmt.Fprintf(w, "%s,%s,%s\n", formatFloat(nb.Point.X), formatFloat(nb.Point.Y), formatFloat(math.Sqrt(nb.DistSqr)))
+	}
+
+	return nil
+}
+
+// within prints the points inside a rectangle or circle, one "x,y" line
+// each.
+func within(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("within", flag.ContinueOnError)
+	in := flags.String("in", "", "points or index to query")
+	rect := flags.String("rect", "", "rectangle as minx,miny,maxx,maxy")
+	at := flags.String("at", "", "circle center as x,y, with -radius")
+	radius := flags.Float64("radius", 0, "circle radius")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var points []*microspace.Point[float64]
+	switch {
+	case *rect != "":
+		coords, err := parseFloats(*rect, 4)
+		if err != nil {
+			return err
+		}
+		idx, err := load(*in)
+		if err != nil {
+			return err
+		}
+		points = idx.WithinRect(microspace.Point[float64]{X: coords[0], Y: coords[1]}, microspace.Point[float64]{X: coords[2], Y: coords[3]})
+	case *at != "":
+		p, err := parsePoint(*at)
+		if err != nil {
+			return err
+		}
+		idx, err := load(*in)
+		if err != nil {
+			return err
+		}
+		idx.WithinRadius(&p, *radius, func(q *microspace.Point[float64]) bool {
+			points = append(points, q)
+			return true
+		})
+	default:
+		return errors.New("within: one of -rect or -at is required")
+	}
+
+	for _, p := range points {
+		fmt.Fprintf(w, "%s,%s\n", formatFloat(p.X), formatFloat(p.Y))
+	}
+
+	return nil
+}
+
+// bench builds every index implementation over the input and times
+// building it and answering random queries, printing a table.
+func bench(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	in := flags.String("in", "", "points to index")
+	queries := flags.Int("queries", 1000, "number of random queries")
+	n := flags.Int("n", 10, "number of neighbors per query")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	idx, err := load(*in)
+	if err != nil {
+		return err
+	}
+	points := idx.Points()
+	if len(points) == 0 {
+		return errors.New("bench: no points to index")
+	}
+
+	bounds := microspace.Rect[float64]{Min: *points[0], Max: *points[0]}
+	for _, p := range points {
+		bounds.Min.X, bounds.Min.Y = min(bounds.Min.X, p.X), min(bounds.Min.Y, p.Y)
+		bounds.Max.X, bounds.Max.Y = max(bounds.Max.X, p.X), max(bounds.Max.Y, p.Y)
+	}
+	targets := make([]*microspace.Point[float64], *queries)
+	for i := range targets {
+		targets[i] = &microspace.Point[float64]{
+			X: bounds.Min.X + rand.Float64()*(bounds.Max.X-bounds.Min.X),
+			Y: bounds.Min.Y + rand.Float64()*(bounds.Max.Y-bounds.Min.Y),
+		}
+	}
+
+	builders := []struct {
+		name  string
+		build func() microspace.Index[float64]
+	}{
+		{"axdex", func() microspace.Index[float64] {
+			a := microspace.NewAxdex[float64](uint(len(points)))
+			for _, p := range points {
+				a.Insert(p)
+			}
+			a.NearestN(points[0], 1, 0)
+			return a
+		}},
+		{"kdtree", func() microspace.Index[float64] { return microspace.NewKDTree(points) }},
+		{"quadtree", func() microspace.Index[float64] {
+			q := microspace.NewQuadtree(bounds, 0, 0)
+			for _, p := range points {
+				q.Insert(p)
+			}
+			return q
+		}},
+		{"rtree", func() microspace.Index[float64] { return microspace.NewRTreeSTR(points, 0) }},
+		{"grid", func() microspace.Index[float64] { return microspace.NewGridIndexFor(points) }},
+	}
+
+	fmt.Fprintf(w, "%-10s %12s %12s\n", "index", "build", "per query")
+	for _, b := range builders {
+		start := time.Now()
+		index := b.build()
+		built := time.Since(start)
+
+		start = time.Now()
+		for _, p := range targets {
+			index.NearestN(p, *n, 0)
+		}
+		perQuery := time.Since(start) / time.Duration(max(len(targets), 1))
+
+		fmt.Fprintf(w, "%-10s %12s %12s\n", b.name, built, perQuery)
+	}
+
+	return nil
+}
+
+// load reads the points or index from the file, picking the format by
+// its extension.
+func load(path string) (*microspace.Axdex[float64], error) {
+	if path == "" {
+		return nil, errors.New("-in is required")
+	}
+	if strings.ToLower(filepath.Ext(path)) != ".msx" {
+		return microspace.LoadFile[float64](path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return microspace.ReadAxdex[float64](bufio.NewReader(f))
+}
+
+// parsePoint parses an "x,y" point.
+func parsePoint(s string) (microspace.Point[float64], error) {
+	coords, err := parseFloats(s, 2)
+	if err != nil {
+		return microspace.Point[float64]{}, err
+	}
+
+	return microspace.Point[float64]{X: coords[0], Y: coords[1]}, nil
+}
+
+// parseFloats parses `count` comma-separated numbers.
+func parseFloats(s string, count int) ([]float64, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != count {
+		return nil, fmt.Errorf("expected %d comma-separated numbers, got %q", count, s)
+	}
+
+	values := make([]float64, count)
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+// formatFloat formats the number as briefly as possible.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
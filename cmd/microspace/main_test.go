@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	csv := filepath.Join(dir, "points.csv")
+	msx := filepath.Join(dir, "points.msx")
+	assert.NoError(t, os.WriteFile(csv, []byte("0,0\n1,0\n3,0\n0,5\n"), 0o644))
+
+	var out bytes.Buffer
+	assert.NoError(t, run([]string{"build", "-in", csv, "-out", msx}, &out))
+	assert.Empty(t, out.String())
+
+	for _, in := range []string{csv, msx} {
+		out.Reset()
+		assert.NoError(t, run([]string{"nearest", "-in", in, "-at", "0.9,0", "-n", "2"}, &out))
+		assert.Equal(t, "1,0,0.09999999999999998\n0,0,0.9\n", out.String())
+
+		out.Reset()
+		assert.NoError(t, run([]string{"nearest", "-in", in, "-at", "0,0", "-n", "-1", "-max", "2"}, &out))
+		assert.Equal(t, "0,0,0\n1,0,1\n", out.String())
+	}
+
+	out.Reset()
+	assert.NoError(t, run([]string{"within", "-in", msx, "-rect", "-1,-1,2,6"}, &out))
+	assert.ElementsMatch(t, []string{"0,0", "1,0", "0,5"}, strings.Fields(out.String()))
+
+	out.Reset()
+	assert.NoError(t, run([]string{"within", "-in", msx, "-at", "3,0", "-radius", "2"}, &out))
+	assert.ElementsMatch(t, []string{"1,0", "3,0"}, strings.Fields(out.String()))
+
+	out.Reset()
+	assert.NoError(t, run([]string{"bench", "-in", msx, "-queries", "10", "-n", "2"}, &out))
+	for _, name := range []string{"axdex", "kdtree", "quadtree", "rtree", "grid"} {
+		assert.Contains(t, out.String(), name)
+	}
+
+	assert.Error(t, run(nil, &out))
+	assert.Error(t, run([]string{"unknown"}, &out))
+	assert.Error(t, run([]string{"nearest", "-in", msx, "-at", "1"}, &out))
+	assert.Error(t, run([]string{"within", "-in", msx}, &out))
+	assert.Error(t, run([]string{"build", "-in", csv}, &out))
+}
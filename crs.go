@@ -0,0 +1,118 @@
+package microspace
+
+import "math"
+
+// CRS is a coordinate reference system which can be converted to and
+// from the coordinate system an index is stored in.
+type CRS[T Float] interface {
+	// Forward converts a point in this CRS into the index's CRS.
+	Forward(p Point[T]) Point[T]
+	// Inverse converts a point in the index's CRS into this CRS.
+	Inverse(p Point[T]) Point[T]
+}
+
+// earthRadius is the radius of the sphere used by Web Mercator, in meters.
+const earthRadius = 6378137
+
+// WebMercator converts longitude and latitude in degrees, stored as X and
+// Y, into Web Mercator (EPSG:3857) coordinates in meters. Use it to query
+// an index of Web Mercator points with longitudes and latitudes, or wrap
+// it with Invert for the opposite.
+type WebMercator[T Float] struct{}
+
+// Forward implements CRS.Forward
+func (WebMercator[T]) Forward(p Point[T]) Point[T] {
+	lon := float64(p.X) * math.Pi / 180
+	lat := float64(p.Y) * math.Pi / 180
+	return Point[T]{
+		X: T(earthRadius * lon),
+		Y: T(earthRadius * math.Log(math.Tan(math.Pi/4+lat/2))),
+	}
+}
+
+// Inverse implements CRS.Inverse
+func (WebMercator[T]) Inverse(p Point[T]) Point[T] {
+	lon := float64(p.X) / earthRadius
+	lat := 2*math.Atan(math.Exp(float64(p.Y)/earthRadius)) - math.Pi/2
+	return Point[T]{X: T(lon * 180 / math.Pi), Y: T(lat * 180 / math.Pi)}
+}
+
+// Affine is a user-defined CRS related to the index's one by an affine
+// transform, which converts points into the index's CRS as:
+//
+//	x' = A*x + B*y + C
+//	y' = D*x + E*y + F
+//
+// The transform must be invertible.
+type Affine[T Float] struct{ A, B, C, D, E, F T }
+
+// Forward implements CRS.Forward
+func (a Affine[T]) Forward(p Point[T]) Point[T] {
+	return Point[T]{
+		X: a.A*p.X + a.B*p.Y + a.C,
+		Y: a.D*p.X + a.E*p.Y + a.F,
+	}
+}
+
+// Inverse implements CRS.Inverse
+func (a Affine[T]) Inverse(p Point[T]) Point[T] {
+	det := a.A*a.E - a.B*a.D
+	x, y := p.X-a.C, p.Y-a.F
+	return Point[T]{
+		X: (a.E*x - a.B*y) / det,
+		Y: (a.A*y - a.D*x) / det,
+	}
+}
+
+// Invert returns the CRS converting the opposite way to the provided one.
+func Invert[T Float](crs CRS[T]) CRS[T] {
+	return inverted[T]{crs}
+}
+
+// inverted swaps the directions of a CRS.
+type inverted[T Float] struct{ crs CRS[T] }
+
+// Forward implements CRS.Forward
+func (i inverted[T]) Forward(p Point[T]) Point[T] { return i.crs.Inverse(p) }
+
+// Inverse implements CRS.Inverse
+func (i inverted[T]) Inverse(p Point[T]) Point[T] { return i.crs.Forward(p) }
+
+// View presents an index in a different coordinate reference system.
+// Query points are converted into the index's CRS, and results are
+// converted back into copies in the view's CRS, so the wrapped index
+// must support query points which aren't in it. Search distances are
+// always measured in the index's CRS.
+type View[T Float] struct {
+	index Index[T]
+	crs   CRS[T]
+}
+
+// NewView returns a view of the index in the provided CRS.
+func NewView[T Float](index Index[T], crs CRS[T]) *View[T] {
+	return &View[T]{index: index, crs: crs}
+}
+
+var _ Index[float32] = new(View[float32])
+
+// NearestN implements Index.NearestN
+func (v *View[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	q := v.crs.Forward(*p)
+	return v.convert(v.index.NearestN(&q, n, max))
+}
+
+// Points implements Index.Points
+func (v *View[T]) Points() []*Point[T] {
+	return v.convert(v.index.Points())
+}
+
+// convert returns copies of the points in the view's CRS.
+func (v *View[T]) convert(points []*Point[T]) []*Point[T] {
+	out := make([]*Point[T], len(points))
+	for i, p := range points {
+		q := v.crs.Inverse(*p)
+		out[i] = &q
+	}
+
+	return out
+}
@@ -0,0 +1,40 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebMercator(t *testing.T) {
+	var crs WebMercator[float64]
+	p := crs.Forward(Point[float64]{X: -0.1278, Y: 51.5074})
+	assert.InDelta(t, -14226.63, p.X, 0.01)
+	assert.InDelta(t, 6711542.47, p.Y, 0.01)
+
+	back := crs.Inverse(p)
+	assert.InDelta(t, -0.1278, back.X, 1e-9)
+	assert.InDelta(t, 51.5074, back.Y, 1e-9)
+	assert.Equal(t, p, Invert[float64](crs).Inverse(back))
+}
+
+func TestAffine(t *testing.T) {
+	crs := Affine[float64]{A: 0, B: -2, C: 10, D: 2, E: 0, F: -5}
+	p := crs.Forward(Point[float64]{3, 4})
+	assert.Equal(t, Point[float64]{2, 1}, p)
+	assert.Equal(t, Point[float64]{3, 4}, crs.Inverse(p))
+}
+
+func TestView(t *testing.T) {
+	// A single huge bucket makes the LSH index exact.
+	idx := NewLSH[float64](1, 1, 1e9, 1)
+	for _, p := range []Point[float64]{{0, 0}, {10, 0}, {20, 0}} {
+		p := p
+		idx.Insert(&p)
+	}
+
+	view := NewView[float64](idx, Affine[float64]{A: 10, E: 10})
+	near := view.NearestN(&Point[float64]{1.8, 0}, 2, 100)
+	assert.Equal(t, []*Point[float64]{{2, 0}, {1, 0}}, near)
+	assert.Len(t, view.Points(), 3)
+}
@@ -0,0 +1,140 @@
+package microspace
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVRecord is a point read from a CSV file, with its optional id and
+// tag columns.
+type CSVRecord[T Float] struct {
+	Point Point[T]
+	ID    string
+	Tag   string
+}
+
+// CSVOptions configures how CSV and TSV files are read. Columns are
+// numbered from 1, so the zero value reads "x,y" lines, with no header,
+// id or tag.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if unset. Use '\t' for TSV.
+	Comma rune
+	// Header skips the first line of the file.
+	Header bool
+	// X and Y are the columns holding the coordinates, 1 and 2 if unset.
+	X, Y int
+	// ID and Tag are the columns holding each record's id and tag, or 0
+	// if the file has none.
+	ID, Tag int
+	// ChunkSize is the number of records parsed before being handed on,
+	// DefaultCSVChunkSize if unset.
+	ChunkSize int
+}
+
+// DefaultCSVChunkSize is the number of records per chunk used when
+// CSVOptions.ChunkSize isn't set.
+const DefaultCSVChunkSize = 4096
+
+// DefaultCSVOptions reads "x,y" lines, with no header, id or tag.
+var DefaultCSVOptions = CSVOptions{X: 1, Y: 2}
+
+// ErrCSVColumns is returned when CSVOptions name a column before the first
+// or read both coordinates from the same column.
+var ErrCSVColumns = errors.New("microspace: invalid CSV columns")
+
+// ReadCSV streams records from r, calling fn with each chunk of up to
+// ChunkSize records as they are parsed, so files of any size can be read
+// without holding them in memory. The chunk is reused between calls. Any
+// error returned by fn stops reading and is returned.
+func ReadCSV[T Float](r io.Reader, opts CSVOptions, fn func(chunk []CSVRecord[T]) error) error {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	cr.FieldsPerRecord = -1
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultCSVChunkSize
+	}
+	if opts.X == 0 {
+		opts.X = 1
+	}
+	if opts.Y == 0 {
+		opts.Y = 2
+	}
+	if opts.X < 0 || opts.Y < 0 || opts.ID < 0 || opts.Tag < 0 || opts.X == opts.Y {
+		return ErrCSVColumns
+	}
+
+	need := max(opts.X, opts.Y, opts.ID, opts.Tag)
+	chunk := make([]CSVRecord[T], 0, opts.ChunkSize)
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("microspace: reading CSV: %w", err)
+		}
+		if first && opts.Header {
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		if len(record) < need {
+			return fmt.Errorf("microspace: reading CSV: line %d: expected at least %d fields, got %d", line, need, len(record))
+		}
+
+		var rec CSVRecord[T]
+		x, err := strconv.ParseFloat(strings.TrimSpace(record[opts.X-1]), 64)
+		if err != nil {
+			return fmt.Errorf("microspace: reading CSV: line %d: %w", line, err)
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(record[opts.Y-1]), 64)
+		if err != nil {
+			return fmt.Errorf("microspace: reading CSV: line %d: %w", line, err)
+		}
+		rec.Point = Point[T]{X: T(x), Y: T(y)}
+		if opts.ID > 0 {
+			rec.ID = record[opts.ID-1]
+		}
+		if opts.Tag > 0 {
+			rec.Tag = record[opts.Tag-1]
+		}
+
+		if chunk = append(chunk, rec); len(chunk) == opts.ChunkSize {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+		}
+	}
+
+	if len(chunk) > 0 {
+		return fn(chunk)
+	}
+
+	return nil
+}
+
+// LoadCSV reads every record from r into a new index of the records.
+func LoadCSV[T Float](r io.Reader, opts CSVOptions) (*ItemIndex[*CSVRecord[T], T], error) {
+	idx := NewItemIndex(0, func(rec *CSVRecord[T]) (T, T) { return rec.Point.X, rec.Point.Y })
+	err := ReadCSV(r, opts, func(chunk []CSVRecord[T]) error {
+		records := make([]CSVRecord[T], len(chunk))
+		copy(records, chunk)
+		for i := range records {
+			idx.Insert(&records[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
@@ -0,0 +1,57 @@
+package microspace
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV(t *testing.T) {
+	input := "id\ttag\tlat\tlon\nbus-1\tbus\t2\t1\ncar-1\tcar\t 4 \t3\nbus-2\tbus\t6\t5\n"
+	opts := CSVOptions{Comma: '\t', Header: true, X: 4, Y: 3, ID: 1, Tag: 2, ChunkSize: 2}
+
+	var chunks [][]CSVRecord[float64]
+	err := ReadCSV(strings.NewReader(input), opts, func(chunk []CSVRecord[float64]) error {
+		chunks = append(chunks, append([]CSVRecord[float64](nil), chunk...))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]CSVRecord[float64]{
+		{{Point[float64]{1, 2}, "bus-1", "bus"}, {Point[float64]{3, 4}, "car-1", "car"}},
+		{{Point[float64]{5, 6}, "bus-2", "bus"}},
+	}, chunks)
+
+	stop := errors.New("stop")
+	err = ReadCSV(strings.NewReader(input), opts, func([]CSVRecord[float64]) error { return stop })
+	assert.ErrorIs(t, err, stop)
+
+	err = ReadCSV(strings.NewReader("1,2\n3\n"), DefaultCSVOptions, func([]CSVRecord[float64]) error { return nil })
+	assert.Contains(t, err.Error(), "line 2")
+	err = ReadCSV(strings.NewReader("1,x\n"), DefaultCSVOptions, func([]CSVRecord[float64]) error { return nil })
+	assert.Error(t, err)
+
+	// The zero value reads "x,y" lines, and columns must be distinct.
+	var records []CSVRecord[float64]
+	err = ReadCSV(strings.NewReader("1,2,3\n"), CSVOptions{}, func(chunk []CSVRecord[float64]) error {
+		records = append(records, chunk...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []CSVRecord[float64]{{Point: Point[float64]{1, 2}}}, records)
+	for _, bad := range []CSVOptions{{X: 3, Y: 3}, {X: 2}, {ID: -1}} {
+		err = ReadCSV(strings.NewReader("1,2,3\n"), bad, func([]CSVRecord[float64]) error { return nil })
+		assert.ErrorIs(t, err, ErrCSVColumns)
+	}
+}
+
+func TestLoadCSV(t *testing.T) {
+	idx, err := LoadCSV[float32](strings.NewReader("0,0\n1,1\n5,5\n"), CSVOptions{ChunkSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, idx.Len())
+
+	found := idx.NearestN(Point[float32]{4, 4}, 2, 0)
+	assert.Equal(t, Point[float32]{5, 5}, found[0].Point)
+	assert.Equal(t, Point[float32]{1, 1}, found[1].Point)
+}
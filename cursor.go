@@ -0,0 +1,46 @@
+package microspace
+
+import (
+	"iter"
+	"math"
+)
+
+// Cursor walks the points of an index outwards from a location, in order
+// of increasing distance, for as long as the caller keeps asking for more.
+// It's meant for loops which keep looking further out until they find a
+// suitable point. The index must not be modified while a cursor is open.
+type Cursor[T Float] struct {
+	next func() (*Point[T], T, bool)
+	stop func()
+}
+
+// CursorAt returns a cursor starting at p, which doesn't need to be in the
+// index. The cursor should be closed once it's no longer needed.
+func (a *Axdex[T]) CursorAt(p *Point[T]) *Cursor[T] {
+	next, stop := iter.Pull2(a.NearestIter(p))
+	return &Cursor[T]{next: next, stop: stop}
+}
+
+// NearestIter returns an iterator over every point in the index, with its
+// squared distance from p, in order of increasing distance. Points are
+// found lazily as the loop asks for them, so a loop looking for the
+// nearest point matching a predicate can simply break once it finds it.
+// The point doesn't need to be in the index, and the index must not be
+// modified while iterating.
+func (a *Axdex[T]) NearestIter(p *Point[T]) iter.Seq2[*Point[T], T] {
+	center := *p
+	return func(yield func(*Point[T], T) bool) {
+		a.browse(&center, T(math.Inf(1)), yield)
+	}
+}
+
+// Next returns the next closest point and its squared distance from the
+// cursor's start, or false once every point has been visited.
+func (c *Cursor[T]) Next() (p *Point[T], distSqr T, ok bool) {
+	return c.next()
+}
+
+// Close releases the cursor's resources.
+func (c *Cursor[T]) Close() {
+	c.stop()
+}
@@ -0,0 +1,60 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	idx := generateIndex(200)
+	start := &Point[float32]{0.5, 0.5}
+
+	c := idx.CursorAt(start)
+	defer c.Close()
+
+	seen := map[*Point[float32]]bool{}
+	last := float32(-1)
+	for {
+		p, d, ok := c.Next()
+		if !ok {
+			break
+		}
+
+		assert.False(t, seen[p])
+		assert.True(t, d >= last)
+		assert.Equal(t, p.DistanceToSqr(start), d)
+		seen[p] = true
+		last = d
+	}
+
+	assert.Len(t, seen, 200)
+}
+
+func TestNearestIter(t *testing.T) {
+	idx := generateIndex(200)
+	start := &Point[float32]{0.5, 0.5}
+
+	// Find the nearest point to the right of the start.
+	var found *Point[float32]
+	count := 0
+	for p, d := range idx.NearestIter(start) {
+		assert.Equal(t, p.DistanceToSqr(start), d)
+		count++
+		if p.X > 0.5 {
+			found = p
+			break
+		}
+	}
+	assert.NotNil(t, found)
+
+	for _, p := range idx.NearestNWith(start, count, 0, QueryOptions[float32]{})[:count-1] {
+		assert.True(t, p.X <= 0.5)
+	}
+
+	count = 0
+	for range idx.NearestIter(start) {
+		count++
+	}
+	assert.Equal(t, 200, count)
+}
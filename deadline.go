@@ -0,0 +1,45 @@
+package microspace
+
+import "time"
+
+// deadlineCheckEvery is how many points NearestNBefore examines between
+// checks of the clock, since reading it is costly next to a distance.
+const deadlineCheckEvery = 64
+
+// NearestNBefore is like NearestN, but gives up once the deadline passes,
+// returning the best neighbors found so far with `partial` set to true.
+// The partial results are always real points within `max`, though closer
+// neighbors may have been missed. The point doesn't need to be in the
+// index.
+func (a *Axdex[T]) NearestNBefore(deadline time.Time, p *Point[T], n int, max T) (results []*Point[T], partial bool) {
+	max = searchRadius(max)
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil, false
+	}
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	c := a.axis.cursor(a.axis.ValueFor(p))
+	list := newNeighborList[T](n)
+	for steps := 0; ; steps++ {
+		if steps%deadlineCheckEvery == deadlineCheckEvery-1 && time.Now().After(deadline) {
+			return list.Sorted(), true
+		}
+
+		i, gap, ok := c.Next()
+		if !ok || gap > max || (list.Full() && gap*gap >= list.Worst()) {
+			break
+		}
+
+		q := a.axis.data[i].p
+		if d := q.DistanceToSqr(p); d <= max*max {
+			list.Insert(q, d)
+		}
+	}
+
+	return list.Sorted(), false
+}
@@ -0,0 +1,24 @@
+package microspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNBefore(t *testing.T) {
+	idx := generateIndex(500)
+	p := idx.Points()[0]
+
+	results, partial := idx.NearestNBefore(time.Now().Add(time.Hour), p, 5, 0.25)
+	assert.False(t, partial)
+	assert.Equal(t, idx.NearestNAt(0, p, 5, 0.25), results)
+
+	results, partial = idx.NearestNBefore(time.Now().Add(-time.Second), p, -1, 2)
+	assert.True(t, partial)
+	assert.Len(t, results, deadlineCheckEvery-1)
+	for _, q := range results {
+		assert.True(t, q.DistanceToSqr(p) <= 4)
+	}
+}
@@ -0,0 +1,716 @@
+package microspace
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	// diskManifestName is the name of the manifest, which lists the live
+	// run files and the current log. Files it doesn't list are left over
+	// from a crash, and are removed when the index is opened.
+	diskManifestName = "MANIFEST"
+	// diskLogPattern is the glob matching the names of write-ahead logs.
+	diskLogPattern = "log-*"
+	// diskRunPattern is the glob matching the names of sorted run files.
+	diskRunPattern = "run-*"
+)
+
+// ErrCorruptManifest is returned when opening a disk index whose manifest
+// can't be read.
+var ErrCorruptManifest = errors.New("microspace: corrupt disk index manifest")
+
+// DiskIndex is a persistent, mutable spatial index built like an LSM tree.
+// Inserts go to a small in-memory layer which is backed by a write-ahead
+// log. Once that layer is full it's sorted by X and written out as an
+// immutable run file, and runs are merged together in the background as
+// they accumulate. Queries consult every layer, reading runs directly
+// from disk, so the index never needs to hold all of its points in memory.
+//
+// Flushes and merges only take effect once the manifest naming their
+// output is synced, which is also what retires the log or runs they
+// replace, so a crash at any point leaves either the old files or the new
+// ones live, never both.
+type DiskIndex[T Float] struct {
+	mu     sync.RWMutex
+	dir    string
+	log    *os.File
+	mem    []*Point[T]
+	runs   []*diskRun[T]
+	count  int
+	seq    int
+	closed bool
+
+	errMu sync.Mutex
+	err   error
+
+	memLimit int
+	mergeAt  int
+	merging  bool
+	wg       sync.WaitGroup
+}
+
+// OpenDiskIndex opens the disk index stored in `dir`, creating it if it
+// doesn't exist. Up to `memLimit` points are kept in memory before being
+// flushed to a run, and runs are merged once there are `mergeAt` of them.
+func OpenDiskIndex[T Float](dir string, memLimit, mergeAt int) (*DiskIndex[T], error) {
+	if memLimit < 1 {
+		memLimit = 1
+	}
+	if mergeAt < 2 {
+		mergeAt = 2
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	d := &DiskIndex[T]{dir: dir, memLimit: memLimit, mergeAt: mergeAt}
+
+	seq, logName, runNames, err := readDiskManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	d.seq = seq
+	if err := removeDiskOrphans(dir, logName, runNames); err != nil {
+		return nil, err
+	}
+
+	for _, name := range runNames {
+		r, err := openDiskRun[T](filepath.Join(dir, name))
+		if err != nil {
+			d.closeRuns()
+			return nil, err
+		}
+
+		d.runs = append(d.runs, r)
+		d.count += r.size
+	}
+
+	if logName == "" {
+		if d.log, err = d.createLog(); err == nil {
+			err = d.writeManifest(d.runs, d.log)
+		}
+		if err != nil {
+			d.Close()
+			return nil, err
+		}
+
+		return d, nil
+	}
+
+	// Replay whatever was inserted but not yet flushed last time.
+	d.log, err = os.OpenFile(filepath.Join(dir, logName), os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		d.closeRuns()
+		return nil, err
+	}
+
+	replay := &externalRun[T]{r: bufio.NewReader(d.log)}
+	for {
+		ok, err := replay.next()
+		if err == io.ErrUnexpectedEOF {
+			// A crash mid-insert left a partial record, which was
+			// never acknowledged, so it's dropped.
+			err = d.log.Truncate(int64(len(d.mem)) * externalPointSize)
+		}
+		if err != nil {
+			d.Close()
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		p := replay.head
+		d.mem = append(d.mem, &p)
+		d.count++
+	}
+
+	return d, nil
+}
+
+var _ Index[float32] = new(DiskIndex[float32])
+
+// Insert adds a copy of the point to the index. The point is durable once
+// Insert returns without an error, as the log is synced before it does.
+func (d *DiskIndex[T]) Insert(p Point[T]) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return os.ErrClosed
+	}
+
+	var buf [externalPointSize]byte
+	encodePoint(buf[:], p)
+	if _, err := d.log.Write(buf[:]); err != nil {
+		return err
+	}
+	if err := d.log.Sync(); err != nil {
+		return err
+	}
+
+	d.mem = append(d.mem, &p)
+	d.count++
+	if len(d.mem) >= d.memLimit {
+		return d.flush()
+	}
+
+	return nil
+}
+
+// Flush writes the in-memory layer out to a new run file.
+func (d *DiskIndex[T]) Flush() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return os.ErrClosed
+	}
+
+	return d.flush()
+}
+
+// flush writes the in-memory layer to a run and starts a new log, then
+// swaps both in with the manifest. If anything fails before that, the
+// index carries on with its old log and runs. It must be called with the
+// write lock held.
+func (d *DiskIndex[T]) flush() error {
+	if len(d.mem) == 0 {
+		return nil
+	}
+
+	sort.Slice(d.mem, func(i, j int) bool { return d.mem[i].X < d.mem[j].X })
+
+	d.seq++
+	name := filepath.Join(d.dir, fmt.Sprintf("run-%08d", d.seq))
+	err := writeDiskRun(name, func(fn func(Point[T]) error) error {
+		for _, p := range d.mem {
+			if err := fn(*p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r, err := openDiskRun[T](name)
+	if err != nil {
+		os.Remove(name)
+		return err
+	}
+
+	runs := append(d.runs[:len(d.runs):len(d.runs)], r)
+	log, err := d.createLog()
+	if err == nil {
+		if err = d.writeManifest(runs, log); err != nil {
+			log.Close()
+			os.Remove(log.Name())
+		}
+	}
+	if err != nil {
+		r.f.Close()
+		os.Remove(name)
+		return err
+	}
+
+	d.log.Close()
+	os.Remove(d.log.Name())
+	d.log = log
+	d.runs = runs
+	d.mem = nil
+
+	if len(d.runs) >= d.mergeAt && !d.merging {
+		d.merging = true
+		d.wg.Add(1)
+		go d.merge(append([]*diskRun[T](nil), d.runs...))
+	}
+
+	return nil
+}
+
+// merge combines the provided runs into a single new run file, then swaps
+// it in for them. It runs in the background without holding the lock, as
+// runs are immutable and are only removed once the swap is done.
+func (d *DiskIndex[T]) merge(runs []*diskRun[T]) {
+	defer d.wg.Done()
+
+	d.mu.Lock()
+	d.seq++
+	name := filepath.Join(d.dir, fmt.Sprintf("run-%08d", d.seq))
+	d.mu.Unlock()
+
+	err := writeDiskRun(name, func(fn func(Point[T]) error) error {
+		heads := &externalRuns[T]{}
+		for _, r := range runs {
+			e := &externalRun[T]{r: bufio.NewReader(io.NewSectionReader(r.f, 0, r.bytes()))}
+			ok, err := e.next()
+			if err != nil {
+				return err
+			}
+			if ok {
+				heads.runs = append(heads.runs, e)
+			}
+		}
+		heap.Init(heads)
+
+		for heads.Len() > 0 {
+			e := heads.runs[0]
+			if err := fn(e.head); err != nil {
+				return err
+			}
+
+			ok, err := e.next()
+			if err != nil {
+				return err
+			}
+			if ok {
+				heap.Fix(heads, 0)
+			} else {
+				heap.Pop(heads)
+			}
+		}
+
+		return nil
+	})
+
+	var merged *diskRun[T]
+	if err == nil {
+		merged, err = openDiskRun[T](name)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.merging = false
+
+	if err != nil {
+		os.Remove(name)
+		d.setErr(err)
+		return
+	}
+
+	// The merged runs are always the oldest, so they're a prefix of the
+	// current list; runs flushed during the merge stay after it. Once the
+	// manifest lists the merged run instead of them, they're gone.
+	next := append([]*diskRun[T]{merged}, d.runs[len(runs):]...)
+	if err := d.writeManifest(next, d.log); err != nil {
+		merged.f.Close()
+		os.Remove(name)
+		d.setErr(err)
+		return
+	}
+
+	d.runs = next
+	for _, r := range runs {
+		r.f.Close()
+		os.Remove(r.f.Name())
+	}
+}
+
+// Wait blocks until any background merge has finished.
+func (d *DiskIndex[T]) Wait() {
+	d.wg.Wait()
+}
+
+// Err returns the first error encountered while querying or merging in
+// the background, since those can't report errors directly.
+func (d *DiskIndex[T]) Err() error {
+	d.errMu.Lock()
+	defer d.errMu.Unlock()
+	return d.err
+}
+
+// setErr records the error if it's the first one seen.
+func (d *DiskIndex[T]) setErr(err error) {
+	d.errMu.Lock()
+	if d.err == nil {
+		d.err = err
+	}
+	d.errMu.Unlock()
+}
+
+// Close waits for background merges and closes the index's files. Points
+// still in the in-memory layer remain in the log and are replayed the
+// next time the index is opened.
+func (d *DiskIndex[T]) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.mu.Unlock()
+
+	d.wg.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var err error
+	if d.log != nil {
+		err = d.log.Close()
+	}
+	if cerr := d.closeRuns(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// createLog creates a new, empty log under the next sequence number.
+func (d *DiskIndex[T]) createLog() (*os.File, error) {
+	d.seq++
+	name := filepath.Join(d.dir, fmt.Sprintf("log-%08d", d.seq))
+	return os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL|os.O_APPEND, 0644)
+}
+
+// writeManifest replaces the manifest with one listing the runs and the
+// log. The new manifest is written under a temporary name and renamed into
+// place, and the directory is synced after, so once it returns without an
+// error the new files are live and the ones they replace can be removed.
+func (d *DiskIndex[T]) writeManifest(runs []*diskRun[T], log *os.File) error {
+	name := filepath.Join(d.dir, diskManifestName)
+	tmp := name + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "%d\n%s\n", d.seq, filepath.Base(log.Name()))
+	for _, r := range runs {
+		fmt.Fprintf(w, "%s\n", filepath.Base(r.f.Name()))
+	}
+	err = w.Flush()
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return syncDir(d.dir)
+}
+
+// readDiskManifest returns the last sequence number, the name of the log
+// and the names of the runs listed in the manifest in `dir`, or zero
+// values if there's no manifest yet.
+func readDiskManifest(dir string) (seq int, log string, runs []string, err error) {
+	f, err := os.Open(filepath.Join(dir, diskManifestName))
+	if os.IsNotExist(err) {
+		return 0, "", nil, nil
+	}
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, "", nil, err
+	}
+	if len(lines) < 2 {
+		return 0, "", nil, ErrCorruptManifest
+	}
+	if seq, err = strconv.Atoi(lines[0]); err != nil {
+		return 0, "", nil, ErrCorruptManifest
+	}
+
+	return seq, lines[1], lines[2:], nil
+}
+
+// removeDiskOrphans removes the logs and runs in `dir` that the manifest
+// doesn't list, along with any temporary files, which were all left
+// behind by a crash before the manifest was updated to include them, or
+// after it was updated to exclude them.
+func removeDiskOrphans(dir, log string, runs []string) error {
+	live := map[string]bool{log: true}
+	for _, name := range runs {
+		live[name] = true
+	}
+
+	for _, pattern := range []string{diskLogPattern, diskRunPattern, "*.tmp"} {
+		names, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if live[filepath.Base(name)] {
+				continue
+			}
+			if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// syncDir syncs the directory, so that files renamed into it or removed
+// from it stay that way after a crash.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// closeRuns closes all open run files.
+func (d *DiskIndex[T]) closeRuns() error {
+	var err error
+	for _, r := range d.runs {
+		if cerr := r.f.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Len returns the number of points in the index.
+func (d *DiskIndex[T]) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.count
+}
+
+// NearestN implements Index.NearestN. The point doesn't need to be in the
+// index, and the returned points are copies read back from the index.
+func (d *DiskIndex[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return d.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (d *DiskIndex[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return d.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` nearest neighbors of p
+// within `max`.
+func (d *DiskIndex[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if n == -1 || n > d.count {
+		n = d.count
+	}
+	if n <= 0 || d.closed {
+		return &neighborList[T]{}
+	}
+
+	results := newNeighborList[T](n)
+	for _, q := range d.mem {
+		if dist := q.DistanceToSqr(p); dist <= max*max {
+			results.Insert(q, dist)
+		}
+	}
+
+	for _, r := range d.runs {
+		if err := r.nearest(p, max, results); err != nil {
+			d.setErr(err)
+			break
+		}
+	}
+
+	return results
+}
+
+// Points implements Index.Points. It reads every point into memory, so
+// it's only suitable for indexes that fit.
+func (d *DiskIndex[T]) Points() []*Point[T] {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	points := append([]*Point[T](nil), d.mem...)
+	for _, r := range d.runs {
+		for i := 0; i < r.size; i++ {
+			p, err := r.at(i)
+			if err != nil {
+				d.setErr(err)
+				return points
+			}
+			points = append(points, &p)
+		}
+	}
+
+	return points
+}
+
+// diskRun is an immutable run file of points sorted by X, which is read
+// in place with random access.
+type diskRun[T Float] struct {
+	f    *os.File
+	size int
+}
+
+// openDiskRun opens the run file with the provided name.
+func openDiskRun[T Float](name string) (*diskRun[T], error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &diskRun[T]{f: f, size: int(info.Size() / externalPointSize)}, nil
+}
+
+// writeDiskRun writes the points produced by `each`, which must already be
+// sorted by X, to a run file. The file is written under a temporary name
+// and renamed into place so that a crash never leaves a partial run.
+func writeDiskRun[T Float](name string, each func(fn func(Point[T]) error) error) error {
+	tmp := name + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	var buf [externalPointSize]byte
+	err = each(func(p Point[T]) error {
+		encodePoint(buf[:], p)
+		_, err := w.Write(buf[:])
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+
+	return err
+}
+
+// bytes returns the size of the run's data in bytes.
+func (r *diskRun[T]) bytes() int64 {
+	return int64(r.size) * externalPointSize
+}
+
+// at reads the i-th point of the run.
+func (r *diskRun[T]) at(i int) (Point[T], error) {
+	var buf [externalPointSize]byte
+	if _, err := r.f.ReadAt(buf[:], int64(i)*externalPointSize); err != nil {
+		return Point[T]{}, err
+	}
+
+	return decodePoint[T](buf[:]), nil
+}
+
+// nearest adds the run's points within `max` of p to the results. Like
+// NearestNAt it expands outwards from p's position along the X axis,
+// always reading whichever side is closer, until neither side can hold
+// a viable point.
+func (r *diskRun[T]) nearest(p *Point[T], max T, results *neighborList[T]) error {
+	var err error
+	right := sort.Search(r.size, func(i int) bool {
+		if err != nil {
+			return true
+		}
+
+		var q Point[T]
+		q, err = r.at(i)
+		return q.X >= p.X
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		left              = right - 1
+		leftP, rightP     Point[T]
+		leftOK, rightOK   bool
+		leftErr, rightErr error
+	)
+	if left >= 0 {
+		leftP, leftErr = r.at(left)
+		leftOK = leftErr == nil
+	}
+	if right < r.size {
+		rightP, rightErr = r.at(right)
+		rightOK = rightErr == nil
+	}
+	if leftErr != nil {
+		return leftErr
+	}
+	if rightErr != nil {
+		return rightErr
+	}
+
+	for leftOK || rightOK {
+		var q Point[T]
+		var gap T
+		if !rightOK || (leftOK && p.X-leftP.X <= rightP.X-p.X) {
+			q, gap = leftP, p.X-leftP.X
+			if left--; left >= 0 {
+				leftP, err = r.at(left)
+			} else {
+				leftOK = false
+			}
+		} else {
+			q, gap = rightP, rightP.X-p.X
+			if right++; right < r.size {
+				rightP, err = r.at(right)
+			} else {
+				rightOK = false
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if gap > max || (results.Full() && gap*gap >= results.Worst()) {
+			break
+		}
+
+		if dist := q.DistanceToSqr(p); dist <= max*max {
+			results.Insert(&q, dist)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package microspace
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskIndexNearest(t *testing.T) {
+	d, err := OpenDiskIndex[float32](t.TempDir(), 16, 3)
+	assert.NoError(t, err)
+	defer d.Close()
+
+	points := []Point[float32]{}
+	for i := 0; i < 200; i++ {
+		p := Point[float32]{rand.Float32(), rand.Float32()}
+		points = append(points, p)
+		assert.NoError(t, d.Insert(p))
+	}
+	d.Wait()
+
+	assert.Equal(t, 200, d.Len())
+	assert.Len(t, d.Points(), 200)
+
+	for _, p := range points[:20] {
+		expected := []float32{}
+		for _, other := range points {
+			if dist := other.DistanceToSqr(&p); dist <= 0.2*0.2 {
+				expected = append(expected, dist)
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		if len(expected) > 5 {
+			expected = expected[:5]
+		}
+
+		results := d.NearestN(&p, 5, 0.2)
+		assert.Equal(t, len(expected), len(results))
+		for i, r := range results {
+			assert.Equal(t, expected[i], r.DistanceToSqr(&p))
+		}
+	}
+	assert.NoError(t, d.Err())
+}
+
+func TestDiskIndexReopen(t *testing.T) {
+	dir := t.TempDir()
+	d, err := OpenDiskIndex[float64](dir, 4, 2)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, d.Insert(Point[float64]{float64(i), 0}))
+	}
+	assert.NoError(t, d.Close())
+
+	runs, _ := filepath.Glob(filepath.Join(dir, diskRunPattern))
+	assert.Len(t, runs, 1)
+
+	// Simulate a crash in the middle of writing a log record.
+	logs, _ := filepath.Glob(filepath.Join(dir, diskLogPattern))
+	assert.Len(t, logs, 1)
+	log, err := os.OpenFile(logs[0], os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	log.Write([]byte{1, 2, 3})
+	log.Close()
+
+	d, err = OpenDiskIndex[float64](dir, 4, 2)
+	assert.NoError(t, err)
+	defer d.Close()
+
+	assert.Equal(t, 10, d.Len())
+	near := d.NearestN(&Point[float64]{8.9, 0}, 2, 5)
+	assert.Equal(t, []*Point[float64]{{9, 0}, {8, 0}}, near)
+}
+
+func TestDiskIndexReopenAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+	d, err := OpenDiskIndex[float64](dir, 4, 10)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, d.Insert(Point[float64]{float64(i), 0}))
+	}
+	assert.NoError(t, d.Close())
+
+	runs, _ := filepath.Glob(filepath.Join(dir, diskRunPattern))
+	assert.Len(t, runs, 2)
+
+	// Simulate crashes before the manifest picked up a merged run, and
+	// while writing a run and the manifest itself.
+	data, err := os.ReadFile(runs[0])
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "run-99999999"), append(data, data...), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "run-99999998.tmp"), data, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, diskManifestName+".tmp"), []byte("1\n"), 0644))
+
+	d, err = OpenDiskIndex[float64](dir, 4, 10)
+	assert.NoError(t, err)
+	defer d.Close()
+
+	assert.Equal(t, 10, d.Len())
+	assert.Len(t, d.Points(), 10)
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*9999999*"))
+	assert.Empty(t, leftovers)
+	_, err = os.Stat(filepath.Join(dir, diskManifestName+".tmp"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDiskIndexCorruptManifest(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, diskManifestName), []byte("x\n"), 0644))
+
+	_, err := OpenDiskIndex[float64](dir, 4, 2)
+	assert.Equal(t, ErrCorruptManifest, err)
+}
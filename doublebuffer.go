@@ -0,0 +1,80 @@
+package microspace
+
+import "sync"
+
+// DoubleBuffered holds two indexes for simulation pipelines: a read index
+// holding the state of the current tick, and a write index which is being
+// assembled for the next tick. Queries always go to the read index, so
+// systems see stable state while movement is applied concurrently, and
+// Swap publishes the write index at the tick boundary.
+type DoubleBuffered[T Float] struct {
+	mu   sync.RWMutex
+	read *Axdex[T]
+
+	writeMu sync.Mutex
+	write   *Axdex[T]
+}
+
+// NewDoubleBuffered returns a new double-buffered index whose write index
+// is created with the provided capacity. The read index starts out empty.
+func NewDoubleBuffered[T Float](capacity uint) *DoubleBuffered[T] {
+	read := NewAxdex[T](0)
+	read.axis.runSort()
+
+	return &DoubleBuffered[T]{read: read, write: NewAxdex[T](capacity)}
+}
+
+var _ Index[float32] = new(DoubleBuffered[float32])
+
+// Insert adds a point to the write index. It's safe to call concurrently
+// with queries, other inserts, and Swap.
+func (d *DoubleBuffered[T]) Insert(p *Point[T]) {
+	d.writeMu.Lock()
+	d.write.Insert(p)
+	d.writeMu.Unlock()
+}
+
+// Read returns the index for the current tick. It will not be modified
+// after being returned, so it's safe to keep using after a Swap.
+func (d *DoubleBuffered[T]) Read() *Axdex[T] {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.read
+}
+
+// Write returns the index being assembled for the next tick. Unlike
+// Insert, using it directly is not synchronized with Swap.
+func (d *DoubleBuffered[T]) Write() *Axdex[T] {
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+	return d.write
+}
+
+// Swap publishes the write index as the read index and starts a
+// new, empty write index sized after the one that was published.
+func (d *DoubleBuffered[T]) Swap() {
+	d.writeMu.Lock()
+	next := d.write
+	d.write = NewAxdex[T](uint(len(next.points)))
+	d.writeMu.Unlock()
+
+	// Sort before publishing so that concurrent readers never race on
+	// the lazy sort in their first query.
+	if !next.axis.sorted {
+		next.axis.runSort()
+	}
+
+	d.mu.Lock()
+	d.read = next
+	d.mu.Unlock()
+}
+
+// NearestN implements Index.NearestN against the read index.
+func (d *DoubleBuffered[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return d.Read().NearestN(p, n, max)
+}
+
+// Points implements Index.Points against the read index.
+func (d *DoubleBuffered[T]) Points() []*Point[T] {
+	return d.Read().Points()
+}
@@ -0,0 +1,59 @@
+package microspace
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoubleBufferedSwap(t *testing.T) {
+	db := NewDoubleBuffered[float32](2)
+	a, b := &Point[float32]{0, 0}, &Point[float32]{1, 1}
+	db.Insert(a)
+	db.Insert(b)
+	assert.Empty(t, db.Points())
+
+	db.Swap()
+	assert.Equal(t, []*Point[float32]{a, b}, db.Points())
+	assert.Equal(t, []*Point[float32]{a, b}, db.NearestN(a, 2, 5))
+
+	previous := db.Read()
+	db.Insert(&Point[float32]{5, 5})
+	db.Swap()
+	assert.Len(t, db.Points(), 1)
+	assert.Equal(t, []*Point[float32]{a, b}, previous.Points())
+}
+
+func TestDoubleBufferedConcurrent(t *testing.T) {
+	db := NewDoubleBuffered[float32](100)
+	for i := 0; i < 100; i++ {
+		db.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
+	}
+	db.Swap()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				points := db.Points()
+				if len(points) > 0 {
+					db.NearestN(points[i%len(points)], 3, 0.25)
+				}
+			}
+		}()
+	}
+
+	for tick := 0; tick < 10; tick++ {
+		for i := 0; i < 100; i++ {
+			db.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
+		}
+		db.Swap()
+	}
+
+	wg.Wait()
+	assert.Len(t, db.Points(), 100)
+}
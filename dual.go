@@ -0,0 +1,168 @@
+package microspace
+
+import (
+	"math"
+	"time"
+)
+
+// EnableDualAxis switches the index to dual-axis mode, where points are
+// kept sorted along both X and Y, and NearestN scans both axes at once.
+// A single sorted axis degrades when many points share similar values
+// along it, while the other axis then usually prunes quickly. Keeping the
+// second axis costs memory and makes inserts, updates and removes slower.
+func (a *Axdex[T]) EnableDualAxis() {
+	a.dual = true
+	a.crossAxis()
+}
+
+// axisCursor steps outwards along a sorted axis from a position on it.
+// Each step visits whichever of the next points to the left and right is
+// closer along the axis, so the gap to the points not yet visited only
+// grows, and a search can stop once it rules out any closer point.
+type axisCursor[T Float] struct {
+	// data holds the values along the axis, or else value returns the
+	// value at a position, for axes stored elsewhere.
+	data  axisPointList[T]
+	value func(int) T
+	// from and to are the values that gaps to the left and right are
+	// measured from, which differ for a cursor starting from a range.
+	from, to T
+	// left and right are the next positions on either side, visiting no
+	// further than lo on the left and hi, exclusive, on the right, and
+	// leftGap and rightGap are their gaps, infinite past either end.
+	left, right, lo, hi int
+	leftGap, rightGap   T
+}
+
+// cursor returns a cursor on the axis expanding outwards from the value.
+func (a *axis[T]) cursor(value T) axisCursor[T] {
+	right := a.Search(value)
+	return a.cursorBetween(value, value, 0, right-1, right, len(a.data))
+}
+
+// cursorBetween returns a cursor on the axis expanding outwards from the
+// range `from` to `to`, with left and right as the first positions to
+// visit on either side of it, within lo to hi.
+func (a *axis[T]) cursorBetween(from, to T, lo, left, right, hi int) axisCursor[T] {
+	c := axisCursor[T]{data: a.data, from: from, to: to, left: left, right: right, lo: lo, hi: hi}
+	c.measure()
+	return c
+}
+
+// measure computes the gaps to the next positions on either side.
+func (c *axisCursor[T]) measure() {
+	c.leftGap, c.rightGap = T(math.Inf(1)), T(math.Inf(1))
+	if c.left >= c.lo {
+		c.leftGap = c.from - c.at(c.left)
+	}
+	if c.right < c.hi {
+		c.rightGap = c.at(c.right) - c.to
+	}
+}
+
+// at returns the value along the axis at the position.
+func (c *axisCursor[T]) at(i int) T {
+	if c.value != nil {
+		return c.value(i)
+	}
+
+	return c.data[i].value
+}
+
+// Gap returns the distance along the axis to the next point, and false
+// once every point has been visited.
+func (c *axisCursor[T]) Gap() (T, bool) {
+	if c.left < c.lo && c.right >= c.hi {
+		return 0, false
+	}
+
+	return min(c.leftGap, c.rightGap), true
+}
+
+// Next steps to the next point, returning its position and its distance
+// along the axis, or false once every point has been visited.
+func (c *axisCursor[T]) Next() (i int, gap T, ok bool) {
+	switch {
+	case c.left >= c.lo && (c.leftGap <= c.rightGap || c.right >= c.hi):
+		i, gap = c.left, c.leftGap
+		if c.left--; c.left >= c.lo {
+			c.leftGap = c.from - c.at(c.left)
+		} else {
+			c.leftGap = T(math.Inf(1))
+		}
+	case c.right < c.hi:
+		i, gap = c.right, c.rightGap
+		if c.right++; c.right < c.hi {
+			c.rightGap = c.at(c.right) - c.to
+		} else {
+			c.rightGap = T(math.Inf(1))
+		}
+	default:
+		return 0, 0, false
+	}
+
+	return i, gap, true
+}
+
+// nearestDual is NearestN in dual-axis mode. It expands along both axes,
+// always stepping the one whose next point is further away, as it's the
+// closest to proving that no unvisited point can be a neighbor, and taking
+// turns on ties. The search ends as soon as either axis does. The point
+// doesn't need to be in the index.
+func (a *Axdex[T]) nearestDual(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return &neighborList[T]{}
+	}
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	var scanned int
+	if a.stats != nil {
+		start := time.Now()
+		defer func() { a.stats.record(time.Since(start), scanned) }()
+	}
+
+	cross := a.cross
+	if !cross.sorted {
+		cross.runSort()
+	}
+
+	axes := [2]*axis[T]{a.axis, cross}
+	cursors := [2]axisCursor[T]{a.axis.cursor(a.axis.ValueFor(p)), cross.cursor(cross.ValueFor(p))}
+	results := newNeighborList[T](n)
+	seen := map[*Point[T]]bool{}
+	for steps := 0; ; steps++ {
+		gap0, ok0 := cursors[0].Gap()
+		gap1, ok1 := cursors[1].Gap()
+		if !ok0 || !ok1 {
+			break
+		}
+
+		k, gap := 0, gap0
+		if gap1 > gap0 || (gap1 == gap0 && steps%2 == 1) {
+			k, gap = 1, gap1
+		}
+		if gap > max || (results.Full() && gap*gap >= results.Worst()) {
+			break
+		}
+
+		i, _, _ := cursors[k].Next()
+		q := axes[k].data[i].p
+		if seen[q] {
+			continue
+		}
+		seen[q] = true
+		scanned++
+
+		if d := q.DistanceToSqr(p); d <= max*max {
+			results.Insert(q, d)
+		}
+	}
+
+	return results
+}
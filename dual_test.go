@@ -0,0 +1,58 @@
+package microspace
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDualAxis(t *testing.T) {
+	// Most points share a handful of X values.
+	idx := NewAxdex[float32](1000)
+	for i := 0; i < 1000; i++ {
+		idx.Insert(&Point[float32]{float32(rand.Intn(5)), rand.Float32() * 100})
+	}
+	idx.EnableDualAxis()
+	idx.EnableStats()
+
+	assertExact(t, idx, idx.Points()[:50], 5, 3)
+	assertExact(t, idx, []*Point[float32]{{2.5, 50}, {-10, -10}}, 8, 100)
+	stats := idx.Stats()
+	assert.True(t, stats.Candidates.Quantile(0.5) < 100)
+
+	// The second axis is kept up to date as points change.
+	for _, p := range idx.Points()[:100] {
+		idx.Update(p, p.X, rand.Float32()*100)
+	}
+	for i := 0; i < 100; i++ {
+		idx.Insert(&Point[float32]{float32(rand.Intn(5)), rand.Float32() * 100})
+	}
+	for _, p := range idx.Points()[100:200] {
+		idx.Remove(p)
+	}
+	idx.ApplyWithin(&Rect[float32]{Max: Point[float32]{5, 50}}, func(p *Point[float32]) { p.Y += 50 })
+	assertExact(t, idx, idx.Points()[:50], 5, 3)
+}
+
+func TestAxisCursor(t *testing.T) {
+	idx := NewAxdex[float64](0)
+	for _, x := range []float64{1, 2, 4, 7, 11} {
+		idx.Insert(&Point[float64]{x, 0})
+	}
+	idx.axis.runSort()
+
+	// Positions come out in order of their distance along the axis.
+	c := idx.axis.cursor(5)
+	var gaps []float64
+	for {
+		i, gap, ok := c.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, gap, math.Abs(idx.axis.data[i].value-5))
+		gaps = append(gaps, gap)
+	}
+	assert.Equal(t, []float64{1, 2, 3, 4, 6}, gaps)
+}
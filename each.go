@@ -0,0 +1,103 @@
+package microspace
+
+import "container/heap"
+
+// NearestNEach calls fn with up to the `n` nearest neighbors of the point
+// and their squared distances, with a `max` search distance. Neighbors are
+// delivered in order of increasing distance as soon as they're confirmed,
+// without building a result slice, and the search stops as soon as fn
+// returns false. `n` may be set to -1 to visit all neighbors in the
+// distance. The point doesn't need to be in the index.
+func (a *Axdex[T]) NearestNEach(p *Point[T], n int, max T, fn func(*Point[T], T) bool) {
+	if n == 0 {
+		return
+	}
+
+	a.browse(p, max, func(q *Point[T], d T) bool {
+		if !fn(q, d) {
+			return false
+		}
+		n--
+		return n != 0
+	})
+}
+
+// browse calls yield with the points within `max` of p, in order of
+// increasing squared distance, until it returns false. Points are read
+// outwards along the axis into a queue, and a point is only yielded once
+// the axis gap to both unread sides is at least its distance, as nothing
+// closer can be left to find by then.
+func (a *Axdex[T]) browse(p *Point[T], max T, yield func(*Point[T], T) bool) {
+	max = searchRadius(max)
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	c := a.axis.cursor(a.axis.ValueFor(p))
+	pending := &pointQueue[T]{}
+	for {
+		i, gap, ok := c.Next()
+		if !ok || gap > max {
+			break
+		}
+
+		for pending.Len() > 0 && pending.items[0].dist <= gap*gap {
+			item := heap.Pop(pending).(pointItem[T])
+			if !yield(item.p, item.dist) {
+				return
+			}
+		}
+
+		q := a.axis.data[i].p
+		if d := q.DistanceToSqr(p); d <= max*max {
+			heap.Push(pending, pointItem[T]{p: q, dist: d})
+		}
+	}
+
+	for pending.Len() > 0 {
+		item := heap.Pop(pending).(pointItem[T])
+		if !yield(item.p, item.dist) {
+			return
+		}
+	}
+}
+
+// pointItem is a point queued along with its squared distance.
+type pointItem[T Float] struct {
+	p    *Point[T]
+	dist T
+}
+
+// pointQueue is a priority queue where closer points are ordered first.
+type pointQueue[T Float] struct {
+	items []pointItem[T]
+}
+
+// Len implements sort.Interface.Len
+func (q *pointQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// Less implements sort.Interface.Less
+func (q *pointQueue[T]) Less(a, b int) bool {
+	return q.items[a].dist < q.items[b].dist
+}
+
+// Swap implements sort.Interface.Swap
+func (q *pointQueue[T]) Swap(a, b int) {
+	q.items[a], q.items[b] = q.items[b], q.items[a]
+}
+
+// Push implements heap.Interface.Push
+func (q *pointQueue[T]) Push(x interface{}) {
+	q.items = append(q.items, x.(pointItem[T]))
+}
+
+// Pop implements heap.Interface.Pop
+func (q *pointQueue[T]) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+var _ heap.Interface = new(pointQueue[float32])
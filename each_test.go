@@ -0,0 +1,49 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNEach(t *testing.T) {
+	idx := NewAxdex[float32](300)
+	for i := 0; i < 300; i++ {
+		idx.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
+	}
+
+	for _, p := range idx.Points()[:30] {
+		expected := idx.NearestNAt(0, p, 6, 0.2)
+
+		var found []*Point[float32]
+		last := float32(-1)
+		idx.NearestNEach(p, 6, 0.2, func(q *Point[float32], d float32) bool {
+			assert.True(t, d >= last)
+			assert.Equal(t, q.DistanceToSqr(p), d)
+			last = d
+			found = append(found, q)
+			return true
+		})
+
+		assert.Equal(t, len(expected), len(found))
+		for i := range found {
+			assert.Equal(t, expected[i].DistanceToSqr(p), found[i].DistanceToSqr(p))
+		}
+	}
+}
+
+func TestNearestNEachStops(t *testing.T) {
+	idx := NewAxdex[float32](3)
+	a, b, c := &Point[float32]{0, 0}, &Point[float32]{1, 0}, &Point[float32]{2, 0}
+	idx.Insert(c)
+	idx.Insert(a)
+	idx.Insert(b)
+
+	var found []*Point[float32]
+	idx.NearestNEach(&Point[float32]{-1, 0}, -1, 10, func(q *Point[float32], _ float32) bool {
+		found = append(found, q)
+		return q != b
+	})
+	assert.Equal(t, []*Point[float32]{a, b}, found)
+}
@@ -0,0 +1,328 @@
+package microspace
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// externalPointSize is the encoded size of a point in a run file. Both
+// coordinates are stored as float64 so that float32 points round-trip
+// exactly.
+const externalPointSize = 16
+
+// encodePoint writes the point into the first externalPointSize bytes
+// of buf.
+func encodePoint[T Float](buf []byte, p Point[T]) {
+	binary.LittleEndian.PutUint64(buf[0:], math.Float64bits(float64(p.X)))
+	binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(float64(p.Y)))
+}
+
+// decodePoint reads a point written by encodePoint.
+func decodePoint[T Float](buf []byte) Point[T] {
+	return Point[T]{
+		X: T(math.Float64frombits(binary.LittleEndian.Uint64(buf[0:]))),
+		Y: T(math.Float64frombits(binary.LittleEndian.Uint64(buf[8:]))),
+	}
+}
+
+// externalFanIn is the most runs an ExternalBuilder reads at once. Any
+// more are first merged into longer runs, that many at a time, so the
+// number of open files stays bounded however large the dataset is.
+const externalFanIn = 64
+
+// ExternalBuilder sorts datasets too large to hold in memory. Points are
+// buffered up to a fixed run size, and each full run is sorted by X and
+// spilled to a temporary file. The runs are merged back together when
+// the points are read out in order, in passes of a bounded number of runs
+// at a time.
+type ExternalBuilder[T Float] struct {
+	dir     string
+	runSize int
+	fanIn   int
+	run     []Point[T]
+	count   int
+	// runs holds the names of the run files, which are only open while
+	// they're being read or written.
+	runs []string
+}
+
+// NewExternalBuilder returns a builder which keeps at most `runSize`
+// points in memory, spilling runs into temporary files in `dir`. An
+// empty dir uses the system's default temporary directory.
+func NewExternalBuilder[T Float](dir string, runSize int) *ExternalBuilder[T] {
+	if runSize < 1 {
+		runSize = 1
+	}
+
+	return &ExternalBuilder[T]{
+		dir:     dir,
+		runSize: runSize,
+		fanIn:   externalFanIn,
+		run:     make([]Point[T], 0, runSize),
+	}
+}
+
+// Insert adds a point to the builder, spilling the current run to disk
+// once it's full.
+func (b *ExternalBuilder[T]) Insert(p Point[T]) error {
+	b.run = append(b.run, p)
+	b.count++
+	if len(b.run) < b.runSize {
+		return nil
+	}
+
+	return b.spill()
+}
+
+// Len returns the number of points inserted into the builder.
+func (b *ExternalBuilder[T]) Len() int {
+	return b.count
+}
+
+// spill sorts the in-memory run and writes it out to a new temporary file.
+func (b *ExternalBuilder[T]) spill() error {
+	if len(b.run) == 0 {
+		return nil
+	}
+
+	sort.Slice(b.run, func(i, j int) bool { return b.run[i].X < b.run[j].X })
+	err := b.writeRun(func(fn func(Point[T]) error) error {
+		for _, p := range b.run {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	b.run = b.run[:0]
+	return nil
+}
+
+// writeRun writes the points produced by `each`, which must already be
+// sorted by X, to a new temporary run file.
+func (b *ExternalBuilder[T]) writeRun(each func(fn func(Point[T]) error) error) error {
+	f, err := os.CreateTemp(b.dir, "microspace-run-")
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	var buf [externalPointSize]byte
+	err = each(func(p Point[T]) error {
+		encodePoint(buf[:], p)
+		_, err := w.Write(buf[:])
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	b.runs = append(b.runs, f.Name())
+	return nil
+}
+
+// Each calls fn with every inserted point in order of increasing X,
+// stopping at the first error fn returns. Points are streamed from the
+// runs on disk, so fn may write them out to wherever the final index
+// should live. If there are more runs than can be read at once, they're
+// first merged into fewer, longer runs, which later calls reuse.
+func (b *ExternalBuilder[T]) Each(fn func(Point[T]) error) error {
+	if len(b.runs) == 0 {
+		sort.Slice(b.run, func(i, j int) bool { return b.run[i].X < b.run[j].X })
+		for _, p := range b.run {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := b.spill(); err != nil {
+		return err
+	}
+
+	for len(b.runs) > b.fanIn {
+		names := b.runs[:b.fanIn]
+		err := b.writeRun(func(fn func(Point[T]) error) error {
+			return mergeRuns[T](names, fn)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			os.Remove(name)
+		}
+		b.runs = b.runs[b.fanIn:]
+	}
+
+	return mergeRuns[T](b.runs, fn)
+}
+
+// mergeRuns calls fn with the points of the run files in order of
+// increasing X, stopping at the first error fn returns.
+func mergeRuns[T Float](names []string, fn func(Point[T]) error) error {
+	runs := &externalRuns[T]{}
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r := &externalRun[T]{r: bufio.NewReader(f)}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			runs.runs = append(runs.runs, r)
+		}
+	}
+	heap.Init(runs)
+
+	for runs.Len() > 0 {
+		r := runs.runs[0]
+		if err := fn(r.head); err != nil {
+			return err
+		}
+
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(runs, 0)
+		} else {
+			heap.Pop(runs)
+		}
+	}
+
+	return nil
+}
+
+// Build returns an in-memory index over the inserted points for which
+// `keep` returns true, letting a huge dataset be reduced down to the set
+// that's actually needed. A nil keep function keeps every point.
+func (b *ExternalBuilder[T]) Build(keep func(Point[T]) bool) (*Axdex[T], error) {
+	var points []*Point[T]
+	err := b.Each(func(p Point[T]) error {
+		if keep == nil || keep(p) {
+			points = append(points, &p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAxdexFromSorted(points), nil
+}
+
+// WriteTo writes every inserted point to w, in the format of
+// Axdex.WriteTo for an index sorted along X, without holding them in
+// memory. Written to a file, the points can be queried in place with
+// OpenMapped. It implements io.WriterTo.
+func (b *ExternalBuilder[T]) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	if _, err := cw.Write(persistHeader(AxisX, uint64(b.count))); err != nil {
+		return cw.n, err
+	}
+
+	buf := make([]byte, externalPointSize)
+	err := b.Each(func(p Point[T]) error {
+		encodePoint(buf, p)
+		_, err := cw.Write(buf)
+		return err
+	})
+	if err != nil {
+		return cw.n, err
+	}
+
+	return cw.n, bw.Flush()
+}
+
+// Close removes the builder's temporary files.
+func (b *ExternalBuilder[T]) Close() error {
+	var firstErr error
+	for _, name := range b.runs {
+		if err := os.Remove(name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	b.runs = nil
+	return firstErr
+}
+
+// externalRun reads back the points of a single sorted run file.
+type externalRun[T Float] struct {
+	r    *bufio.Reader
+	head Point[T]
+}
+
+// next reads the run's next point into head, returning false once the
+// run is exhausted.
+func (e *externalRun[T]) next() (bool, error) {
+	var buf [externalPointSize]byte
+	if _, err := io.ReadFull(e.r, buf[:]); err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	e.head = decodePoint[T](buf[:])
+	return true, nil
+}
+
+// externalRuns is a min-heap of runs ordered by the X of their heads.
+type externalRuns[T Float] struct {
+	runs []*externalRun[T]
+}
+
+// Len implements sort.Interface.Len
+func (e *externalRuns[T]) Len() int {
+	return len(e.runs)
+}
+
+// Less implements sort.Interface.Less
+func (e *externalRuns[T]) Less(a, b int) bool {
+	return e.runs[a].head.X < e.runs[b].head.X
+}
+
+// Swap implements sort.Interface.Swap
+func (e *externalRuns[T]) Swap(a, b int) {
+	e.runs[a], e.runs[b] = e.runs[b], e.runs[a]
+}
+
+// Push implements heap.Interface.Push
+func (e *externalRuns[T]) Push(x interface{}) {
+	e.runs = append(e.runs, x.(*externalRun[T]))
+}
+
+// Pop implements heap.Interface.Pop
+func (e *externalRuns[T]) Pop() interface{} {
+	last := e.runs[len(e.runs)-1]
+	e.runs = e.runs[:len(e.runs)-1]
+	return last
+}
+
+var _ heap.Interface = new(externalRuns[float32])
@@ -0,0 +1,131 @@
+package microspace
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalBuilder(t *testing.T) {
+	dir := t.TempDir()
+	b := NewExternalBuilder[float32](dir, 7)
+
+	points := []Point[float32]{}
+	for i := 0; i < 100; i++ {
+		p := Point[float32]{rand.Float32(), rand.Float32()}
+		points = append(points, p)
+		assert.NoError(t, b.Insert(p))
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].X < points[j].X })
+
+	var sorted []Point[float32]
+	assert.NoError(t, b.Each(func(p Point[float32]) error {
+		sorted = append(sorted, p)
+		return nil
+	}))
+	assert.Equal(t, points, sorted)
+
+	idx, err := b.Build(func(p Point[float32]) bool { return p.Y < 0.5 })
+	assert.NoError(t, err)
+	for i, ap := range idx.axis.data {
+		assert.True(t, ap.p.Y < 0.5)
+		if i > 0 {
+			assert.True(t, idx.axis.data[i-1].value <= ap.value)
+		}
+	}
+
+	assert.NoError(t, b.Close())
+	files, _ := os.ReadDir(dir)
+	assert.Empty(t, files)
+}
+
+func TestExternalBuilderInMemory(t *testing.T) {
+	b := NewExternalBuilder[float64]("", 10)
+	assert.NoError(t, b.Insert(Point[float64]{2, 0}))
+	assert.NoError(t, b.Insert(Point[float64]{1, 0}))
+
+	idx, err := b.Build(nil)
+	assert.NoError(t, err)
+	assert.Len(t, idx.Points(), 2)
+	assert.Equal(t, float64(1), idx.Points()[0].X)
+	assert.NoError(t, b.Close())
+}
+
+func TestExternalBuilderFanIn(t *testing.T) {
+	dir := t.TempDir()
+	b := NewExternalBuilder[float64](dir, 5)
+	b.fanIn = 3
+
+	points := []Point[float64]{}
+	for i := 0; i < 100; i++ {
+		p := Point[float64]{rand.Float64(), rand.Float64()}
+		points = append(points, p)
+		assert.NoError(t, b.Insert(p))
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].X < points[j].X })
+
+	// Every pass leaves fewer runs, and reading them again reuses them.
+	for i := 0; i < 2; i++ {
+		var sorted []Point[float64]
+		assert.NoError(t, b.Each(func(p Point[float64]) error {
+			sorted = append(sorted, p)
+			return nil
+		}))
+		assert.Equal(t, points, sorted)
+		assert.True(t, len(b.runs) <= 3)
+
+		files, _ := os.ReadDir(dir)
+		assert.Len(t, files, len(b.runs))
+	}
+
+	assert.NoError(t, b.Close())
+	files, _ := os.ReadDir(dir)
+	assert.Empty(t, files)
+}
+
+func TestExternalBuilderWriteTo(t *testing.T) {
+	dir := t.TempDir()
+	b := NewExternalBuilder[float32](dir, 7)
+	defer b.Close()
+
+	points := randomPoints(100)
+	for _, p := range points {
+		assert.NoError(t, b.Insert(*p))
+	}
+	assert.Equal(t, 100, b.Len())
+
+	path := filepath.Join(dir, "points.msax")
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	n, err := b.WriteTo(f)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(persistHeaderSize+100*externalPointSize), n)
+	assert.NoError(t, f.Close())
+
+	m, err := OpenMapped[float32](path)
+	assert.NoError(t, err)
+	defer m.Close()
+
+	assert.Equal(t, 100, m.Len())
+	for i := 1; i < m.Len(); i++ {
+		assert.True(t, m.At(i-1).X <= m.At(i).X)
+	}
+
+	idx := NewAxdex[float32](100)
+	for _, p := range points {
+		idx.Insert(p)
+	}
+	for _, p := range points[:10] {
+		expected := idx.NearestN(p, 3, 0)
+		found := m.NearestN(*p, 3, 0)
+		assert.Len(t, found, 3)
+		for i, j := range found {
+			q := m.At(j)
+			assert.Equal(t, expected[i].DistanceToSqr(p), q.DistanceToSqr(p))
+		}
+	}
+}
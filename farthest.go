@@ -0,0 +1,106 @@
+package microspace
+
+import (
+	"math"
+	"slices"
+)
+
+// FarthestPair returns the two points in the index furthest from each
+// other, and the distance between them, which is the diameter of the
+// point cloud, or nil points if there are fewer than two. The furthest
+// pair always lies on the convex hull of the points, which is walked with
+// rotating calipers.
+func (a *Axdex[T]) FarthestPair() (*Point[T], *Point[T], T) {
+	if len(a.points) < 2 {
+		return nil, nil, 0
+	}
+
+	hull := convexHull(a.points)
+	if len(hull) == 1 {
+		return a.points[0], a.points[1], 0
+	}
+
+	// For each edge of the hull, the vertex furthest from it is found by
+	// advancing around the hull while the triangle it makes with the
+	// edge grows, and is paired with both ends of the edge.
+	p, q, best := hull[0], hull[1], hull[0].DistanceToSqr(hull[1])
+	consider := func(u, v *Point[T]) {
+		if d := u.DistanceToSqr(v); d > best {
+			p, q, best = u, v, d
+		}
+	}
+	for i, j := 0, 1; i < len(hull); i++ {
+		u, v := hull[i], hull[(i+1)%len(hull)]
+		for cross(u, v, hull[(j+1)%len(hull)]) > cross(u, v, hull[j]) {
+			j = (j + 1) % len(hull)
+		}
+		consider(u, hull[j])
+		consider(v, hull[j])
+	}
+
+	return p, q, T(math.Sqrt(float64(best)))
+}
+
+// FarthestN returns up to the `n` points furthest from p, furthest first.
+// `n` may be set to -1 to order all points. The point doesn't need to be
+// in the index.
+func (a *Axdex[T]) FarthestN(p *Point[T], n int) []*Point[T] {
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil
+	}
+
+	// The list keeps the smallest distances, so they're negated.
+	results := newRankedList[*Point[T], T](n)
+	for _, q := range a.points {
+		results.Insert(q, -q.DistanceToSqr(p))
+	}
+
+	return results.Sorted()
+}
+
+// convexHull returns the vertices of the convex hull of the points, in
+// counter-clockwise order, using Andrew's monotone chain. Points on the
+// hull's edges are left out.
+func convexHull[T Float](points []*Point[T]) []*Point[T] {
+	sorted := slices.Clone(points)
+	slices.SortFunc(sorted, func(p, q *Point[T]) int {
+		switch {
+		case p.X < q.X:
+			return -1
+		case p.X > q.X:
+			return 1
+		}
+		return byY(p, q)
+	})
+
+	hull := make([]*Point[T], 0, 2*len(sorted))
+	for _, p := range sorted {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	for i, lower := len(sorted)-2, len(hull)+1; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	hull = hull[:len(hull)-1]
+	if len(hull) == 2 && *hull[0] == *hull[1] {
+		hull = hull[:1]
+	}
+
+	return hull
+}
+
+// cross returns the cross product of b-a and c-a, which is twice the
+// signed area of the triangle abc, positive if it turns left.
+func cross[T Float](a, b, c *Point[T]) T {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
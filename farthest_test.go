@@ -0,0 +1,67 @@
+package microspace
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFarthestPair(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	p, q, d := idx.FarthestPair()
+	assert.Nil(t, p)
+	assert.Nil(t, q)
+	assert.Equal(t, float32(0), d)
+
+	for _, r := range randomPoints(1000) {
+		idx.Insert(r)
+	}
+
+	best := float32(0)
+	for i, a := range idx.Points() {
+		for _, b := range idx.Points()[i+1:] {
+			best = max(best, a.DistanceToSqr(b))
+		}
+	}
+	p, q, d = idx.FarthestPair()
+	assert.Equal(t, best, p.DistanceToSqr(q))
+	assert.InDelta(t, best, d*d, 1e-5)
+
+	// Collinear and coincident points.
+	line := NewAxdex[float64](0)
+	points := []*Point[float64]{{1, 1}, {3, 3}, {0, 0}, {2, 2}}
+	for _, r := range points {
+		line.Insert(r)
+	}
+	u, v, _ := line.FarthestPair()
+	assert.ElementsMatch(t, []*Point[float64]{points[1], points[2]}, []*Point[float64]{u, v})
+
+	same := NewAxdex[float64](0)
+	same.Insert(&Point[float64]{1, 1})
+	same.Insert(&Point[float64]{1, 1})
+	u, v, dist := same.FarthestPair()
+	assert.NotNil(t, u)
+	assert.NotNil(t, v)
+	assert.Equal(t, 0.0, dist)
+}
+
+func TestFarthestN(t *testing.T) {
+	idx := generateIndex(500)
+	center := &Point[float32]{0.3, 0.6}
+
+	expected := append([]*Point[float32](nil), idx.Points()...)
+	sort.Slice(expected, func(i, j int) bool {
+		return expected[i].DistanceToSqr(center) > expected[j].DistanceToSqr(center)
+	})
+
+	found := idx.FarthestN(center, 10)
+	assert.Len(t, found, 10)
+	for i, p := range found {
+		assert.Equal(t, expected[i].DistanceToSqr(center), p.DistanceToSqr(center))
+	}
+
+	assert.Len(t, idx.FarthestN(center, -1), 500)
+	assert.Len(t, idx.FarthestN(center, 1000), 500)
+	assert.Empty(t, idx.FarthestN(center, 0))
+}
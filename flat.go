@@ -0,0 +1,171 @@
+package microspace
+
+import "sort"
+
+// Handle identifies a point in a FlatIndex, or a box in a Broadphase.
+// Handles are assigned in order of insertion and stay the same for as long
+// as the entry is in the index, so they can be used to look up the
+// caller's data for each entry.
+type Handle int
+
+// FlatIndex is a mutable index like Axdex, storing the coordinates of its
+// points in flat arrays sorted by X rather than as pointers to points.
+// Queries read the coordinates contiguously instead of following a
+// pointer for each point, which makes them considerably faster over large
+// numbers of points. Points are referred to by handles.
+type FlatIndex[T Float] struct {
+	xs, ys  []T
+	handles []Handle
+	// slots holds the position of each handle's point in the arrays, or
+	// -1 once it's been removed.
+	slots  []int
+	sorted bool
+}
+
+// NewFlatIndex returns a new, empty flat index with room for `capacity`
+// points.
+func NewFlatIndex[T Float](capacity int) *FlatIndex[T] {
+	return &FlatIndex[T]{
+		xs:      make([]T, 0, capacity),
+		ys:      make([]T, 0, capacity),
+		handles: make([]Handle, 0, capacity),
+		slots:   make([]int, 0, capacity),
+		sorted:  true,
+	}
+}
+
+// Insert adds a point at (x, y) to the index, returning its handle.
+func (f *FlatIndex[T]) Insert(x, y T) Handle {
+	h := Handle(len(f.slots))
+	f.slots = append(f.slots, len(f.xs))
+	f.xs, f.ys = append(f.xs, x), append(f.ys, y)
+	f.handles = append(f.handles, h)
+	f.sorted = false
+	return h
+}
+
+// Len returns the number of points in the index.
+func (f *FlatIndex[T]) Len() int {
+	return len(f.xs)
+}
+
+// Position returns the position of the point with the handle, and false
+// if there's no such point.
+func (f *FlatIndex[T]) Position(h Handle) (Point[T], bool) {
+	i := f.slot(h)
+	if i == -1 {
+		return Point[T]{}, false
+	}
+
+	return Point[T]{X: f.xs[i], Y: f.ys[i]}, true
+}
+
+// Remove removes the point with the handle, returning false if there's no
+// such point. The handle isn't reused.
+func (f *FlatIndex[T]) Remove(h Handle) bool {
+	i := f.slot(h)
+	if i == -1 {
+		return false
+	}
+
+	copy(f.xs[i:], f.xs[i+1:])
+	copy(f.ys[i:], f.ys[i+1:])
+	copy(f.handles[i:], f.handles[i+1:])
+	last := len(f.xs) - 1
+	f.xs, f.ys, f.handles = f.xs[:last], f.ys[:last], f.handles[:last]
+
+	f.slots[h] = -1
+	for ; i < last; i++ {
+		f.slots[f.handles[i]] = i
+	}
+
+	return true
+}
+
+// Update moves the point with the handle to (x, y), returning false if
+// there's no such point. Like Axdex.Update, the point is shifted to its
+// new place rather than sorting the whole index again.
+func (f *FlatIndex[T]) Update(h Handle, x, y T) bool {
+	i := f.slot(h)
+	if i == -1 {
+		return false
+	}
+
+	for ; i > 0 && f.xs[i-1] > x; i-- {
+		f.move(i-1, i)
+	}
+	for ; i < len(f.xs)-1 && f.xs[i+1] < x; i++ {
+		f.move(i+1, i)
+	}
+
+	f.xs[i], f.ys[i], f.handles[i] = x, y, h
+	f.slots[h] = i
+	return true
+}
+
+// NearestN returns the handles of up the `n` nearest points to p, with a
+// `max` search distance, ordered by increasing distance. `n` may be set
+// to -1 to search for all points in the distance, and `max` to 0 or less
+// to search without a limit on distance.
+func (f *FlatIndex[T]) NearestN(p Point[T], n int, max T) []Handle {
+	f.sort()
+
+	found := nearestSorted(f.xs, f.ys, p.X, p.Y, n, max)
+	handles := make([]Handle, len(found))
+	for k, i := range found {
+		handles[k] = f.handles[i]
+	}
+
+	return handles
+}
+
+// slot returns the position of the handle's point in the arrays, sorting
+// them first if needed, or -1 if there's no such point.
+func (f *FlatIndex[T]) slot(h Handle) int {
+	if h < 0 || int(h) >= len(f.slots) {
+		return -1
+	}
+
+	f.sort()
+	return f.slots[h]
+}
+
+// move copies the point at position `from` to position `to`.
+func (f *FlatIndex[T]) move(from, to int) {
+	f.xs[to], f.ys[to], f.handles[to] = f.xs[from], f.ys[from], f.handles[from]
+	f.slots[f.handles[to]] = to
+}
+
+// sort sorts the points by X if any were inserted since the last sort.
+func (f *FlatIndex[T]) sort() {
+	if f.sorted {
+		return
+	}
+
+	sort.Sort(flatPoints[T]{f})
+	for i, h := range f.handles {
+		f.slots[h] = i
+	}
+	f.sorted = true
+}
+
+// flatPoints sorts the points of a flat index by X.
+type flatPoints[T Float] struct{ f *FlatIndex[T] }
+
+// Len implements sort.Interface.Len
+func (p flatPoints[T]) Len() int {
+	return len(p.f.xs)
+}
+
+// Less implements sort.Interface.Less
+func (p flatPoints[T]) Less(i, j int) bool {
+	return p.f.xs[i] < p.f.xs[j]
+}
+
+// Swap implements sort.Interface.Swap
+func (p flatPoints[T]) Swap(i, j int) {
+	f := p.f
+	f.xs[i], f.xs[j] = f.xs[j], f.xs[i]
+	f.ys[i], f.ys[j] = f.ys[j], f.ys[i]
+	f.handles[i], f.handles[j] = f.handles[j], f.handles[i]
+}
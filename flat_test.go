@@ -0,0 +1,83 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatIndex(t *testing.T) {
+	f := NewFlatIndex[float32](0)
+	idx := NewAxdex[float32](0)
+	points := map[Handle]*Point[float32]{}
+	for _, p := range randomPoints(500) {
+		points[f.Insert(p.X, p.Y)] = p
+		idx.Insert(p)
+	}
+	assert.Equal(t, 500, f.Len())
+
+	check := func() {
+		for _, q := range randomPoints(20) {
+			expected := []float32{}
+			for _, p := range idx.NearestN(q, 5, 0.2) {
+				expected = append(expected, p.DistanceToSqr(q))
+			}
+
+			actual := []float32{}
+			for _, h := range f.NearestN(*q, 5, 0.2) {
+				pos, ok := f.Position(h)
+				assert.True(t, ok)
+				actual = append(actual, pos.DistanceToSqr(q))
+			}
+			assert.Equal(t, expected, actual)
+		}
+	}
+	check()
+
+	for h := Handle(0); h < 100; h++ {
+		x, y := rand.Float32(), rand.Float32()
+		assert.True(t, f.Update(h, x, y))
+		assert.True(t, idx.Update(points[h], x, y))
+	}
+	check()
+
+	for h := Handle(100); h < 200; h++ {
+		assert.True(t, f.Remove(h))
+		assert.True(t, idx.Remove(points[h]))
+	}
+	check()
+
+	assert.Equal(t, 400, f.Len())
+	assert.False(t, f.Remove(100))
+	assert.False(t, f.Update(150, 0, 0))
+	_, ok := f.Position(-1)
+	assert.False(t, ok)
+
+	pos, ok := f.Position(300)
+	assert.True(t, ok)
+	assert.Equal(t, *points[300], pos)
+}
+
+func BenchmarkFlatIndexNearest(b *testing.B) {
+	f := NewFlatIndex[float32](500000)
+	for _, p := range randomPoints(500000) {
+		f.Insert(p.X, p.Y)
+	}
+	f.sort()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		f.NearestN(Point[float32]{0.5, 0.5}, 10, 0)
+	}
+}
+
+func BenchmarkAxdexNearest500000(b *testing.B) {
+	t := generateIndex(500000)
+	t.axis.runSort()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t.NearestN(&Point[float32]{0.5, 0.5}, 10, 0)
+	}
+}
@@ -0,0 +1,164 @@
+package microspace
+
+import "sort"
+
+// Frozen is an immutable, compact index built from an Axdex with Freeze.
+// It stores coordinates in flat arrays sorted along the Axdex's axis, with
+// no per-point pointers or lookup maps, making it as small and as fast to
+// query as possible. Points are referred to by their position in the
+// index, which can be read back with At.
+type Frozen[T Float] struct {
+	along Axis
+	// primary holds the coordinates along the sorted axis, and secondary
+	// the coordinates along the other axis.
+	primary   []T
+	secondary []T
+}
+
+// Freeze returns a frozen copy of the index. The index is left unchanged.
+// Indexes on a custom axis are frozen along X, as the frozen index only
+// stores coordinates.
+func (a *Axdex[T]) Freeze() *Frozen[T] {
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	data := a.axis.data
+	if a.along == AxisCustom {
+		data = append(axisPointList[T](nil), data...)
+		for i := range data {
+			data[i].value = data[i].p.X
+		}
+		sort.Sort(data)
+	}
+
+	f := &Frozen[T]{
+		along:     a.along,
+		primary:   make([]T, len(data)),
+		secondary: make([]T, len(data)),
+	}
+	if f.along == AxisCustom {
+		f.along = AxisX
+	}
+	for i, ap := range data {
+		f.primary[i], f.secondary[i] = f.split(*ap.p)
+	}
+
+	return f
+}
+
+// split returns the point's coordinates along the sorted and other axis.
+func (f *Frozen[T]) split(p Point[T]) (primary, secondary T) {
+	if f.along == AxisY {
+		return p.Y, p.X
+	}
+
+	return p.X, p.Y
+}
+
+// Len returns the number of points in the index.
+func (f *Frozen[T]) Len() int {
+	return len(f.primary)
+}
+
+// At returns the i-th point in the index.
+func (f *Frozen[T]) At(i int) Point[T] {
+	if f.along == AxisY {
+		return Point[T]{X: f.secondary[i], Y: f.primary[i]}
+	}
+
+	return Point[T]{X: f.primary[i], Y: f.secondary[i]}
+}
+
+// NearestN returns the positions of up the `n` nearest points to p, with
+// a `max` search distance, ordered by increasing distance. `n` may be set
+// to -1 to search for all points in the distance. The point doesn't need
+// to be in the index.
+func (f *Frozen[T]) NearestN(p Point[T], n int, max T) []int {
+	pp, ps := f.split(p)
+	return nearestSorted(f.primary, f.secondary, pp, ps, n, max)
+}
+
+// nearestSorted returns the positions of up to the `n` nearest points to
+// (pp, ps) within `max`, ordered by increasing distance, among the points
+// with the coordinates (primary[i], secondary[i]) sorted by primary.
+func nearestSorted[T Float](primary, secondary []T, pp, ps T, n int, max T) []int {
+	max = searchRadius(max)
+	if n == -1 || n > len(primary) {
+		n = len(primary)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		size  = len(primary)
+		right = sort.Search(size, func(i int) bool { return primary[i] >= pp })
+		left  = right - 1
+
+		found = make([]int, 0, n)
+		dists = make([]T, 0, n)
+
+		// Distances are computed a batch at a time on either side, the
+		// left batch starting at leftFrom and the right at rightFrom.
+		leftDists, rightDists = [kernelBatch]T{}, [kernelBatch]T{}
+		leftFrom, rightFrom   = left + 1, right
+		rightTo               = right
+	)
+
+	// insert adds the point at position i and squared distance d to the
+	// results if it's within `max` and closer than the worst of them.
+	insert := func(i int, d T) {
+		full := len(found) == n
+		if d > max*max || (full && d >= dists[n-1]) {
+			return
+		}
+		if full {
+			found, dists = found[:n-1], dists[:n-1]
+		}
+
+		k := sort.Search(len(dists), func(k int) bool { return dists[k] > d })
+		found = append(found, 0)
+		copy(found[k+1:], found[k:])
+		found[k] = i
+		dists = append(dists, 0)
+		copy(dists[k+1:], dists[k:])
+		dists[k] = d
+	}
+
+	// Both sides are stepped outwards together until neither can hold a
+	// point closer than the worst result, which avoids branching on which
+	// side is closer at every step.
+	for left >= 0 || right < size {
+		if left >= 0 {
+			if gap := pp - primary[left]; gap > max || (len(found) == n && gap*gap >= dists[n-1]) {
+				left = -1
+			} else {
+				if left < leftFrom {
+					leftFrom = left + 1 - kernelBatch
+					if leftFrom < 0 {
+						leftFrom = 0
+					}
+					sqrDistances(pp, ps, primary[leftFrom:], secondary[leftFrom:], leftDists[:left+1-leftFrom])
+				}
+				insert(left, leftDists[left-leftFrom])
+				left--
+			}
+		}
+
+		if right < size {
+			if gap := primary[right] - pp; gap > max || (len(found) == n && gap*gap >= dists[n-1]) {
+				right = size
+			} else {
+				if right >= rightTo {
+					rightFrom, rightTo = right, min(right+kernelBatch, size)
+					sqrDistances(pp, ps, primary[rightFrom:], secondary[rightFrom:], rightDists[:rightTo-rightFrom])
+				}
+				insert(right, rightDists[right-rightFrom])
+				right++
+			}
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,41 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrozenNearest(t *testing.T) {
+	for _, along := range []Axis{AxisX, AxisY} {
+		idx := NewAxdexOnAxis[float32](300, along)
+		for i := 0; i < 300; i++ {
+			idx.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
+		}
+
+		f := idx.Freeze()
+		assert.Equal(t, 300, f.Len())
+
+		for _, p := range idx.Points()[:30] {
+			expected := idx.NearestNAt(0, p, 4, 0.2)
+			found := f.NearestN(*p, 4, 0.2)
+			assert.Equal(t, len(expected), len(found))
+			for i, pos := range found {
+				at := f.At(pos)
+				assert.Equal(t, expected[i].DistanceToSqr(p), at.DistanceToSqr(p))
+			}
+		}
+
+		assert.Len(t, f.NearestN(Point[float32]{0.5, 0.5}, -1, 2), 300)
+	}
+}
+
+func BenchmarkFrozenNearest(b *testing.B) {
+	f := generateIndex(500000).Freeze()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		f.NearestN(Point[float32]{0.5, 0.5}, 10, 0)
+	}
+}
@@ -0,0 +1,61 @@
+package microspace
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in meters, used by the
+// geographic metrics.
+const EarthRadius = 6371008.8
+
+// Haversine measures great-circle distance in meters between points whose
+// X is longitude and Y is latitude, in degrees. Its bound only holds along
+// latitude, so it must be used on an index sorted along Y, which
+// NewGeoAxdex takes care of.
+type Haversine[T Float] struct{}
+
+// Distance implements Metric.Distance
+func (Haversine[T]) Distance(a, b *Point[T]) T {
+	lat1, lat2 := radians(a.Y), radians(b.Y)
+	dlat, dlon := lat2-lat1, radians(b.X-a.X)
+
+	h := math.Pow(math.Sin(dlat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dlon/2), 2)
+	return T(2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1))))
+}
+
+// Bound implements Metric.Bound
+func (Haversine[T]) Bound(gap T) T {
+	return T(radians(gap) * EarthRadius)
+}
+
+// Equirectangular is a faster approximation of Haversine, treating the
+// Earth as flat around the mean latitude of the two points. It's accurate
+// over distances of up to a few hundred kilometers away from the poles.
+type Equirectangular[T Float] struct{}
+
+// Distance implements Metric.Distance
+func (Equirectangular[T]) Distance(a, b *Point[T]) T {
+	dlon := math.Mod(float64(b.X-a.X)+540, 360) - 180
+	x := radians(dlon) * math.Cos(radians((a.Y+b.Y)/2))
+	y := radians(b.Y - a.Y)
+	return T(EarthRadius * math.Sqrt(x*x+y*y))
+}
+
+// Bound implements Metric.Bound
+func (Equirectangular[T]) Bound(gap T) T {
+	return T(radians(gap) * EarthRadius)
+}
+
+// radians converts degrees to radians.
+func radians[T Float](degrees T) float64 {
+	return float64(degrees) * math.Pi / 180
+}
+
+// NewGeoAxdex returns an index over geographic points, with X as longitude
+// and Y as latitude in degrees, whose NearestN measures distances and
+// `max` in meters using the metric, such as Haversine. Points are sorted
+// by latitude, which unlike longitude bounds distances everywhere on the
+// globe, so queries near the antimeridian find neighbors on both sides.
+func NewGeoAxdex[T Float](capacity uint, metric Metric[T]) *Axdex[T] {
+	a := NewAxdexOnAxis[T](capacity, AxisY)
+	a.metric = metric
+	return a
+}
@@ -0,0 +1,34 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoMetrics(t *testing.T) {
+	london := &Point[float64]{-0.1278, 51.5074}
+	paris := &Point[float64]{2.3522, 48.8566}
+	assert.InDelta(t, 343500, Haversine[float64]{}.Distance(london, paris), 1000)
+	assert.InDelta(t, 343500, Equirectangular[float64]{}.Distance(london, paris), 2000)
+
+	// Across the antimeridian.
+	a, b := &Point[float64]{179.9, 0}, &Point[float64]{-179.9, 0}
+	assert.InDelta(t, 22239, Haversine[float64]{}.Distance(a, b), 10)
+	assert.InDelta(t, 22239, Equirectangular[float64]{}.Distance(a, b), 10)
+}
+
+func TestGeoAxdex(t *testing.T) {
+	east := &Point[float64]{179.95, 10}
+	west := &Point[float64]{-179.95, 10.01}
+	inland := &Point[float64]{170, 10}
+	north := &Point[float64]{179.95, 11}
+
+	idx := NewGeoAxdex[float64](4, Haversine[float64]{})
+	for _, p := range []*Point[float64]{east, west, inland, north} {
+		idx.Insert(p)
+	}
+
+	assert.Equal(t, []*Point[float64]{east, west}, idx.NearestN(east, 2, 50000))
+	assert.Equal(t, []*Point[float64]{west, east, north}, idx.NearestN(west, 3, 200000))
+}
@@ -0,0 +1,280 @@
+// Package geofence reports when moving objects enter, exit or cross
+// fences, which are circles, rectangles, polygons or any other
+// microspace.Region. Fences are kept in a microspace index, so each
+// position update only tests the fences near it.
+package geofence
+
+import (
+	"math"
+	"sync"
+
+	"github.com/galaxyblack/microspace"
+)
+
+// Kind is the kind of an event.
+type Kind int
+
+const (
+	// Enter is sent when an object moves into a fence.
+	Enter Kind = iota
+	// Exit is sent when an object moves out of a fence.
+	Exit
+	// Cross is sent when an object passes through a fence between two
+	// updates, starting and ending outside it.
+	Cross
+)
+
+// String returns the name of the kind, such as "ENTER".
+func (k Kind) String() string {
+	switch k {
+	case Enter:
+		return "ENTER"
+	case Exit:
+		return "EXIT"
+	case Cross:
+		return "CROSS"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Event is sent when an object enters, exits or crosses a fence.
+type Event[K comparable, T microspace.Float] struct {
+	Kind   Kind
+	Fence  string
+	Object K
+	// Position is the object's position in the update which caused the
+	// event.
+	Position microspace.Point[T]
+}
+
+// Fences tracks the positions of objects, identified by keys of type K,
+// against a set of named fences. It's safe to use concurrently.
+type Fences[K comparable, T microspace.Float] struct {
+	mu      sync.Mutex
+	fences  map[string]*fence[T]
+	index   *microspace.ItemIndex[*fence[T], T]
+	reach   T
+	objects map[K]*object[T]
+	events  chan Event[K, T]
+}
+
+// fence is a named region, indexed at the center of its bounds.
+type fence[T microspace.Float] struct {
+	name   string
+	region microspace.Region[T]
+	center microspace.Point[T]
+}
+
+// object is a tracked object's last position, and the fences it's in.
+type object[T microspace.Float] struct {
+	position microspace.Point[T]
+	inside   map[*fence[T]]bool
+}
+
+// New returns an empty set of fences, which sends events on a channel
+// with room for `buffer` events. Updates block while the channel is full,
+// so it must be drained.
+func New[K comparable, T microspace.Float](buffer int) *Fences[K, T] {
+	return &Fences[K, T]{
+		fences:  make(map[string]*fence[T]),
+		index:   microspace.NewItemIndex[*fence[T], T](0, func(f *fence[T]) (x, y T) { return f.center.X, f.center.Y }),
+		objects: make(map[K]*object[T]),
+		events:  make(chan Event[K, T], buffer),
+	}
+}
+
+// Events returns the channel events are sent on.
+func (f *Fences[K, T]) Events() <-chan Event[K, T] {
+	return f.events
+}
+
+// Add adds a fence with the name, replacing any fence with the same name.
+// Objects already inside the new fence enter it on their next update.
+func (f *Fences[K, T]) Add(name string, region microspace.Region[T]) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.remove(name)
+
+	bounds := region.Bounds()
+	fc := &fence[T]{
+		name:   name,
+		region: region,
+		center: microspace.Point[T]{X: (bounds.Min.X + bounds.Max.X) / 2, Y: (bounds.Min.Y + bounds.Max.Y) / 2},
+	}
+	f.fences[name] = fc
+	f.index.Insert(fc)
+
+	// Every fence is found by searching within the reach of its center.
+	// The reach only grows, since shrinking it would mean scanning every
+	// fence on removal.
+	if r := T(math.Sqrt(float64(fc.center.DistanceToSqr(&bounds.Min)))); r > f.reach {
+		f.reach = r
+	}
+}
+
+// Remove removes the named fence, returning false if there's no such
+// fence. No events are sent for objects inside it.
+func (f *Fences[K, T]) Remove(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.remove(name)
+}
+
+// remove removes the named fence.
+func (f *Fences[K, T]) remove(name string) bool {
+	fc, ok := f.fences[name]
+	if !ok {
+		return false
+	}
+
+	delete(f.fences, name)
+	f.index.Remove(fc)
+	for _, o := range f.objects {
+		delete(o.inside, fc)
+	}
+
+	return true
+}
+
+// Update moves the object to the position, sending an event for every
+// fence it entered, exited or crossed since its last update. An object's
+// first update only sends Enter events.
+func (f *Fences[K, T]) Update(id K, p microspace.Point[T]) {
+	f.mu.Lock()
+	o, seen := f.objects[id]
+	if !seen {
+		o = &object[T]{inside: make(map[*fence[T]]bool)}
+		f.objects[id] = o
+	}
+
+	var events []Event[K, T]
+	inside := make(map[*fence[T]]bool, len(o.inside))
+	for _, fc := range f.near(p, 0) {
+		if fc.region.Contains(&p) {
+			inside[fc] = true
+			if !o.inside[fc] {
+				events = append(events, Event[K, T]{Kind: Enter, Fence: fc.name, Object: id, Position: p})
+			}
+		}
+	}
+	for fc := range o.inside {
+		if !inside[fc] {
+			events = append(events, Event[K, T]{Kind: Exit, Fence: fc.name, Object: id, Position: p})
+		}
+	}
+
+	if seen && o.position != p {
+		mid := microspace.Point[T]{X: (o.position.X + p.X) / 2, Y: (o.position.Y + p.Y) / 2}
+		half := T(math.Sqrt(float64(mid.DistanceToSqr(&p))))
+		for _, fc := range f.near(mid, half) {
+			if !o.inside[fc] && !inside[fc] && crosses(fc.region, o.position, p) {
+				events = append(events, Event[K, T]{Kind: Cross, Fence: fc.name, Object: id, Position: p})
+			}
+		}
+	}
+
+	o.position, o.inside = p, inside
+	f.mu.Unlock()
+
+	for _, e := range events {
+		f.events <- e
+	}
+}
+
+// Forget stops tracking the object, without sending any events.
+func (f *Fences[K, T]) Forget(id K) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.objects, id)
+}
+
+// Close closes the events channel. The fences mustn't be updated after.
+func (f *Fences[K, T]) Close() {
+	close(f.events)
+}
+
+// near returns the fences which may hold points within `r` of p.
+func (f *Fences[K, T]) near(p microspace.Point[T], r T) []*fence[T] {
+	if f.index.Len() == 0 {
+		return nil
+	}
+
+	// A max of 0 is unlimited, so fences that are all single points
+	// are searched for just past them.
+	max := f.reach + r
+	if max == 0 {
+		max = T(math.SmallestNonzeroFloat32)
+	}
+
+	return f.index.NearestN(p, -1, max)
+}
+
+// crosses returns whether the segment from a to b, which both lie
+// outside the region, passes through it. Only circles, rectangles and
+// polygons can be crossed.
+func crosses[T microspace.Float](region microspace.Region[T], a, b microspace.Point[T]) bool {
+	switch r := region.(type) {
+	case *microspace.Circle[T]:
+		s := microspace.Segment[T]{A: a, B: b}
+		return s.DistanceToSqr(&r.Center) <= r.Radius*r.Radius
+	case *microspace.Rect[T]:
+		return crossesRect(*r, a, b)
+	case microspace.Polygon[T]:
+		for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+			if segmentsIntersect(a, b, r[j], r[i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// crossesRect returns whether the segment from a to b passes through the
+// rectangle, clipping it to each side in turn.
+func crossesRect[T microspace.Float](r microspace.Rect[T], a, b microspace.Point[T]) bool {
+	lo, hi := T(0), T(1)
+	clip := func(start, delta, from, to T) bool {
+		if delta == 0 {
+			return start >= from && start <= to
+		}
+
+		t0, t1 := (from-start)/delta, (to-start)/delta
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		lo, hi = max(lo, t0), min(hi, t1)
+		return lo <= hi
+	}
+
+	return clip(a.X, b.X-a.X, r.Min.X, r.Max.X) && clip(a.Y, b.Y-a.Y, r.Min.Y, r.Max.Y)
+}
+
+// segmentsIntersect returns whether the segments from a to b and from c
+// to d touch.
+func segmentsIntersect[T microspace.Float](a, b, c, d microspace.Point[T]) bool {
+	d1, d2 := orientation(c, d, a), orientation(c, d, b)
+	d3, d4 := orientation(a, b, c), orientation(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(c, d, a)) || (d2 == 0 && onSegment(c, d, b)) ||
+		(d3 == 0 && onSegment(a, b, c)) || (d4 == 0 && onSegment(a, b, d))
+}
+
+// orientation returns the cross product of b-a and c-a, which is positive
+// if c is left of the line from a to b, and zero if it's on the line.
+func orientation[T microspace.Float](a, b, c microspace.Point[T]) T {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// onSegment returns whether p, which is on the line through a and b, is
+// between them.
+func onSegment[T microspace.Float](a, b, p microspace.Point[T]) bool {
+	return p.X >= min(a.X, b.X) && p.X <= max(a.X, b.X) && p.Y >= min(a.Y, b.Y) && p.Y <= max(a.Y, b.Y)
+}
@@ -0,0 +1,74 @@
+package geofence
+
+import (
+	"testing"
+
+	"github.com/galaxyblack/microspace"
+	"github.com/stretchr/testify/assert"
+)
+
+// drain returns the events sent so far.
+func drain(f *Fences[string, float64]) []Event[string, float64] {
+	var events []Event[string, float64]
+	for {
+		select {
+		case e := <-f.Events():
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestFences(t *testing.T) {
+	f := New[string, float64](16)
+	f.Add("circle", &microspace.Circle[float64]{Center: microspace.Point[float64]{X: 0, Y: 0}, Radius: 1})
+	f.Add("rect", &microspace.Rect[float64]{Min: microspace.Point[float64]{X: 5, Y: -1}, Max: microspace.Point[float64]{X: 6, Y: 1}})
+	f.Add("poly", microspace.Polygon[float64]{{X: 10, Y: -1}, {X: 12, Y: -1}, {X: 11, Y: 1}})
+
+	at := func(x, y float64) microspace.Point[float64] { return microspace.Point[float64]{X: x, Y: y} }
+
+	f.Update("a", at(0.5, 0))
+	assert.Equal(t, []Event[string, float64]{{Kind: Enter, Fence: "circle", Object: "a", Position: at(0.5, 0)}}, drain(f))
+
+	f.Update("a", at(0.6, 0))
+	assert.Empty(t, drain(f))
+
+	f.Update("a", at(5.5, 0))
+	assert.ElementsMatch(t, []Event[string, float64]{
+		{Kind: Exit, Fence: "circle", Object: "a", Position: at(5.5, 0)},
+		{Kind: Enter, Fence: "rect", Object: "a", Position: at(5.5, 0)},
+	}, drain(f))
+
+	// Passing through every fence from outside them crosses them.
+	f.Update("b", at(-5, 0.5))
+	assert.Empty(t, drain(f))
+	f.Update("b", at(20, 0))
+	events := drain(f)
+	assert.Len(t, events, 3)
+	for _, e := range events {
+		assert.Equal(t, Cross, e.Kind)
+	}
+
+	// Missing the fences doesn't.
+	f.Update("b", at(-5, 3))
+	assert.Empty(t, drain(f))
+
+	f.Add("circle", &microspace.Circle[float64]{Center: at(5.5, 0), Radius: 0.1})
+	f.Update("a", at(5.5, 0.05))
+	assert.Equal(t, []Event[string, float64]{{Kind: Enter, Fence: "circle", Object: "a", Position: at(5.5, 0.05)}}, drain(f))
+
+	assert.True(t, f.Remove("rect"))
+	assert.False(t, f.Remove("rect"))
+	f.Update("a", at(5.5, 0.05))
+	assert.Empty(t, drain(f))
+
+	f.Forget("a")
+	f.Update("a", at(5.5, 0.05))
+	assert.Equal(t, []Event[string, float64]{{Kind: Enter, Fence: "circle", Object: "a", Position: at(5.5, 0.05)}}, drain(f))
+
+	f.Close()
+	_, ok := <-f.Events()
+	assert.False(t, ok)
+	assert.Equal(t, "CROSS", Cross.String())
+}
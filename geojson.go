@@ -0,0 +1,105 @@
+package microspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Feature is a GeoJSON Point feature: a point with the feature's id and
+// properties attached as its payload.
+type Feature[T Float] struct {
+	Point      Point[T]
+	ID         any
+	Properties map[string]any
+}
+
+// LoadGeoJSON reads a GeoJSON FeatureCollection, indexing its Point
+// features by their coordinates, with features of other geometry types
+// skipped. Queries return the features, carrying their properties.
+func LoadGeoJSON[T Float](r io.Reader) (*ItemIndex[*Feature[T], T], error) {
+	features, err := readGeoJSONFeatures[T](r)
+	if err != nil {
+		return nil, fmt.Errorf("microspace: loading GeoJSON: %w", err)
+	}
+
+	idx := NewItemIndex(uint(len(features)), func(f *Feature[T]) (T, T) { return f.Point.X, f.Point.Y })
+	for _, f := range features {
+		idx.Insert(f)
+	}
+
+	return idx, nil
+}
+
+// ExportGeoJSON writes the features, such as the results of a query, to w
+// as a GeoJSON FeatureCollection.
+func ExportGeoJSON[T Float](w io.Writer, features []*Feature[T]) error {
+	collection := geoJSONCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, len(features))}
+	for i, f := range features {
+		coords, err := json.Marshal([]float64{float64(f.Point.X), float64(f.Point.Y)})
+		if err != nil {
+			return err
+		}
+
+		collection.Features[i] = geoJSONFeature{
+			Type:       "Feature",
+			ID:         f.ID,
+			Properties: f.Properties,
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: coords},
+		}
+	}
+
+	return json.NewEncoder(w).Encode(collection)
+}
+
+// geoJSONCollection is the encoding of a GeoJSON FeatureCollection.
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature is the encoding of a GeoJSON Feature.
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         any             `json:"id,omitempty"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// geoJSONGeometry is the encoding of a GeoJSON geometry, whose
+// coordinates are decoded once its type is known.
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// readGeoJSONFeatures reads the Point features of a FeatureCollection.
+func readGeoJSONFeatures[T Float](r io.Reader) ([]*Feature[T], error) {
+	var collection geoJSONCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, err
+	}
+
+	var features []*Feature[T]
+	for _, feature := range collection.Features {
+		if feature.Geometry.Type != "Point" {
+			continue
+		}
+
+		var coords []float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &coords); err != nil {
+			return nil, err
+		}
+		if len(coords) < 2 {
+			return nil, fmt.Errorf("point with %d coordinates", len(coords))
+		}
+
+		features = append(features, &Feature[T]{
+			Point:      Point[T]{X: T(coords[0]), Y: T(coords[1])},
+			ID:         feature.ID,
+			Properties: feature.Properties,
+		})
+	}
+
+	return features, nil
+}
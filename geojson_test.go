@@ -0,0 +1,52 @@
+package microspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadGeoJSON(t *testing.T) {
+	input := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": "a", "geometry": {"type": "Point", "coordinates": [1, 2]}, "properties": {"name": "first"}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1]]}, "properties": {}},
+		{"type": "Feature", "id": 7, "geometry": {"type": "Point", "coordinates": [5, 5, 10]}, "properties": {"name": "second"}}
+	]}`
+
+	idx, err := LoadGeoJSON[float64](strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, idx.Len())
+
+	found := idx.NearestN(Point[float64]{0, 0}, 1, 0)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "a", found[0].ID)
+	assert.Equal(t, Point[float64]{1, 2}, found[0].Point)
+	assert.Equal(t, map[string]any{"name": "first"}, found[0].Properties)
+
+	_, err = LoadGeoJSON[float64](strings.NewReader(`{"features": [{"geometry": {"type": "Point", "coordinates": [1]}}]}`))
+	assert.Error(t, err)
+}
+
+func TestExportGeoJSON(t *testing.T) {
+	features := []*Feature[float32]{
+		{Point: Point[float32]{1.5, 2}, ID: "a", Properties: map[string]any{"speed": 3.0}},
+		{Point: Point[float32]{3, 4}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportGeoJSON(&buf, features))
+
+	var decoded map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "FeatureCollection", decoded["type"])
+
+	idx, err := LoadGeoJSON[float32](&buf)
+	assert.NoError(t, err)
+	items := idx.Items()
+	assert.Len(t, items, 2)
+	assert.Equal(t, *features[0], *items[0])
+	assert.Equal(t, features[1].Point, items[1].Point)
+}
@@ -0,0 +1,11 @@
+module github.com/galaxyblack/microspace
+
+go 1.23
+
+require (
+	github.com/dhconnelly/rtreego v1.2.0
+	github.com/kyroy/kdtree v0.0.0-20200419114247-70830f883f1d
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/grpc v1.67.1
+	google.golang.org/protobuf v1.35.1
+)
@@ -0,0 +1,254 @@
+package microspace
+
+import (
+	"math"
+	"slices"
+)
+
+// GridIndex is a spatial hash which buckets points into square cells of a
+// fixed size. For roughly uniform points with a well chosen cell size,
+// queries only look at a handful of cells right around the point, which
+// beats walking any tree.
+type GridIndex[T Float] struct {
+	cellSize T
+	cells    map[gridCell][]*Point[T]
+	points   []*Point[T]
+	moves    []gridMove[T]
+
+	// lo and hi are the smallest and largest cell coordinates in use.
+	lo, hi gridCell
+}
+
+// gridCell is the coordinate of a cell in the grid.
+type gridCell struct{ X, Y int64 }
+
+// gridMove is a move queued by UpdatePosition.
+type gridMove[T Float] struct {
+	p    *Point[T]
+	x, y T
+}
+
+// gridTargetPerCell is the average number of points per cell picked by
+// NewGridIndexFor.
+const gridTargetPerCell = 2
+
+// NewGridIndex returns a new grid index with cells of the provided size.
+// Queries are fastest when cells hold a few points each on average.
+func NewGridIndex[T Float](cellSize T) *GridIndex[T] {
+	if cellSize <= 0 {
+		panic("Cannot create a grid with cells that aren't a positive size.")
+	}
+
+	return &GridIndex[T]{cellSize: cellSize, cells: map[gridCell][]*Point[T]{}}
+}
+
+// NewGridIndexFor returns a grid index holding the points, with a cell
+// size picked from their density so that cells hold a couple of points
+// each on average.
+func NewGridIndexFor[T Float](points []*Point[T]) *GridIndex[T] {
+	var cellSize T = 1
+	if len(points) > 0 {
+		bounds := Rect[T]{Min: *points[0], Max: *points[0]}
+		for _, p := range points[1:] {
+			bounds = bounds.union(Rect[T]{Min: *p, Max: *p})
+		}
+
+		width, height := bounds.Max.X-bounds.Min.X, bounds.Max.Y-bounds.Min.Y
+		if area := width * height; area > 0 {
+			cellSize = T(math.Sqrt(float64(area) * gridTargetPerCell / float64(len(points))))
+		} else if side := max(width, height); side > 0 {
+			// The points all lie on a line.
+			cellSize = side * gridTargetPerCell / T(len(points))
+		}
+	}
+
+	g := NewGridIndex(cellSize)
+	for _, p := range points {
+		g.Insert(p)
+	}
+
+	return g
+}
+
+var _ Index[float32] = new(GridIndex[float32])
+
+// CellSize returns the size of the grid's cells.
+func (g *GridIndex[T]) CellSize() T {
+	return g.cellSize
+}
+
+// cellOf returns the cell the point lies in.
+func (g *GridIndex[T]) cellOf(p *Point[T]) gridCell {
+	return gridCell{
+		X: int64(math.Floor(float64(p.X / g.cellSize))),
+		Y: int64(math.Floor(float64(p.Y / g.cellSize))),
+	}
+}
+
+// Insert adds a point to the index.
+func (g *GridIndex[T]) Insert(p *Point[T]) {
+	c := g.cellOf(p)
+	if len(g.points) == 0 {
+		g.lo, g.hi = c, c
+	} else {
+		g.extend(c)
+	}
+
+	g.cells[c] = append(g.cells[c], p)
+	g.points = append(g.points, p)
+}
+
+// extend widens the range of cells in use to include c.
+func (g *GridIndex[T]) extend(c gridCell) {
+	g.lo = gridCell{X: min(g.lo.X, c.X), Y: min(g.lo.Y, c.Y)}
+	g.hi = gridCell{X: max(g.hi.X, c.X), Y: max(g.hi.Y, c.Y)}
+}
+
+// Remove removes the point from the index, returning false if it wasn't
+// in the index.
+func (g *GridIndex[T]) Remove(p *Point[T]) bool {
+	c := g.cellOf(p)
+	cell, ok := removePoint(g.cells[c], p)
+	if !ok {
+		return false
+	}
+
+	if len(cell) == 0 {
+		delete(g.cells, c)
+	} else {
+		g.cells[c] = cell
+	}
+	g.points, _ = removePoint(g.points, p)
+
+	return true
+}
+
+// UpdatePosition queues a move of the point to (x, y), which takes effect
+// at the next EndFrame. Until then the point keeps its old position, both
+// in the index and in its coordinates, so every query during a frame sees
+// the points where they were at the start of it.
+func (g *GridIndex[T]) UpdatePosition(p *Point[T], x, y T) {
+	g.moves = append(g.moves, gridMove[T]{p: p, x: x, y: y})
+}
+
+// EndFrame applies the moves queued since the last frame, in the order
+// they were queued. Each one only touches the point's old and new cells,
+// so the work is proportional to the number of moves rather than to the
+// number of points. Moves of points removed from the index since they
+// were queued are dropped.
+func (g *GridIndex[T]) EndFrame() {
+	for _, m := range g.moves {
+		from, to := g.cellOf(m.p), g.cellOf(&Point[T]{X: m.x, Y: m.y})
+		if from == to {
+			if slices.Contains(g.cells[from], m.p) {
+				m.p.X, m.p.Y = m.x, m.y
+			}
+			continue
+		}
+
+		cell, ok := removePoint(g.cells[from], m.p)
+		if !ok {
+			continue
+		}
+		if len(cell) == 0 {
+			delete(g.cells, from)
+		} else {
+			g.cells[from] = cell
+		}
+
+		m.p.X, m.p.Y = m.x, m.y
+		g.cells[to] = append(g.cells[to], m.p)
+		g.extend(to)
+	}
+
+	clear(g.moves)
+	g.moves = g.moves[:0]
+}
+
+// Points implements Index.Points
+func (g *GridIndex[T]) Points() []*Point[T] {
+	return g.points
+}
+
+// NearestN implements Index.NearestN. Cells are searched in square rings
+// of growing size around the point's cell, until no point beyond the
+// ring could be closer than the worst result. The point doesn't need to
+// be in the index.
+func (g *GridIndex[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return g.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (g *GridIndex[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return g.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` nearest neighbors of p
+// within `max`.
+func (g *GridIndex[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(g.points) {
+		n = len(g.points)
+	}
+	if n <= 0 || len(g.points) == 0 {
+		return &neighborList[T]{}
+	}
+
+	results := newNeighborList[T](n)
+	center := g.cellOf(p)
+	visit := func(x, y int64) {
+		for _, q := range g.cells[gridCell{X: x, Y: y}] {
+			if d := q.DistanceToSqr(p); d <= max*max {
+				results.Insert(q, d)
+			}
+		}
+	}
+
+	// Rings before the first to reach a cell in use are empty, however
+	// far the point is from the others, so the search starts there, and
+	// each ring only walks the cells within those in use.
+	for ring := g.firstRing(center); ; ring++ {
+		// Any point outside the rings searched so far is at least this
+		// far from p.
+		if reach := T(ring-1) * g.cellSize; ring > 0 && (reach > max || (results.Full() && reach*reach >= results.Worst())) {
+			break
+		}
+		if center.X-ring < g.lo.X && center.Y-ring < g.lo.Y && center.X+ring > g.hi.X && center.Y+ring > g.hi.Y {
+			break
+		}
+
+		fromX, toX := clampRing(center.X, ring, g.lo.X, g.hi.X)
+		fromY, toY := clampRing(center.Y, ring, g.lo.Y, g.hi.Y)
+		for x := fromX; x <= toX; x++ {
+			// Only the left and right columns of the ring are walked in
+			// full, the columns between only have their ends in it.
+			if x == center.X-ring || x == center.X+ring {
+				for y := fromY; y <= toY; y++ {
+					visit(x, y)
+				}
+				continue
+			}
+
+			if y := center.Y - ring; y == fromY {
+				visit(x, y)
+			}
+			if y := center.Y + ring; y == toY {
+				visit(x, y)
+			}
+		}
+	}
+
+	return results
+}
+
+// firstRing returns the first ring around the cell which reaches a cell
+// in use.
+func (g *GridIndex[T]) firstRing(c gridCell) int64 {
+	return max(g.lo.X-c.X, c.X-g.hi.X, g.lo.Y-c.Y, c.Y-g.hi.Y, 0)
+}
+
+// clampRing returns the range of coordinates `ring` cells either side of
+// c, limited to lo to hi.
+func clampRing(c, ring, lo, hi int64) (from, to int64) {
+	return max(c-ring, lo), min(c+ring, hi)
+}
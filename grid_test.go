@@ -0,0 +1,90 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridIndexNearest(t *testing.T) {
+	g := NewGridIndex[float32](0.05)
+	for _, p := range randomPoints(1000) {
+		g.Insert(p)
+	}
+	assert.Len(t, g.Points(), 1000)
+
+	assertExact(t, g, g.Points()[:50], 5, 0.1)
+	assertExact(t, g, randomPoints(50), 8, 1)
+	assertExact(t, g, []*Point[float32]{{-3, 0.5}, {2, 2}}, 3, 10)
+	assertExact(t, g, randomPoints(5), -1, 0.05)
+}
+
+func TestNewGridIndexFor(t *testing.T) {
+	points := randomPoints(10000)
+	g := NewGridIndexFor(points)
+	assert.InDelta(t, 0.014, g.CellSize(), 0.002)
+	assertExact(t, g, points[:50], 5, 0.1)
+
+	line := []*Point[float32]{{0, 0}, {1, 0}, {2, 0}, {3, 0}}
+	assert.Equal(t, float32(1.5), NewGridIndexFor(line).CellSize())
+	assert.Equal(t, float32(1), NewGridIndexFor[float32](nil).CellSize())
+	assert.Panics(t, func() { NewGridIndex[float32](0) })
+}
+
+func TestGridIndexRemove(t *testing.T) {
+	points := randomPoints(200)
+	g := NewGridIndexFor(points)
+	for _, p := range points[:100] {
+		assert.True(t, g.Remove(p))
+	}
+	assert.False(t, g.Remove(points[0]))
+	assert.Equal(t, points[100:], g.Points())
+	assertExact(t, g, points[:20], 3, 0.2)
+}
+
+func TestGridIndexNearestFarAway(t *testing.T) {
+	g := NewGridIndexFor(randomPoints(1000))
+	assertExact(t, g, []*Point[float32]{{200, 200}, {-150, 0.5}, {0.5, 1e4}}, 3, 1e5)
+}
+
+func BenchmarkGridIndexNearestFarAway(b *testing.B) {
+	g := NewGridIndexFor(randomPoints(1000))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.NearestN(&Point[float32]{200, 200}, 1, 0)
+	}
+}
+
+func TestGridIndexUpdatePosition(t *testing.T) {
+	points := randomPoints(200)
+	g := NewGridIndex[float32](0.05)
+	for _, p := range points {
+		g.Insert(p)
+	}
+
+	// Queued moves don't take effect until the end of the frame.
+	g.UpdatePosition(points[0], 5, 5)
+	g.UpdatePosition(points[1], points[1].X, points[1].Y+0.001)
+	g.UpdatePosition(points[2], -3, -3)
+	g.UpdatePosition(points[2], 3, 3)
+	g.UpdatePosition(points[3], 9, 9)
+	assert.True(t, g.Remove(points[3]))
+	assert.NotEqual(t, []*Point[float32]{points[0]}, g.NearestN(&Point[float32]{5, 5}, 1, 0.1))
+
+	y := points[1].Y + 0.001
+	g.EndFrame()
+	assert.Equal(t, Point[float32]{5, 5}, *points[0])
+	assert.Equal(t, y, points[1].Y)
+	assert.Equal(t, Point[float32]{3, 3}, *points[2])
+	assert.NotEqual(t, Point[float32]{9, 9}, *points[3])
+
+	assert.Equal(t, []*Point[float32]{points[0]}, g.NearestN(&Point[float32]{5, 5}, 1, 0.1))
+	assert.Equal(t, []*Point[float32]{points[2]}, g.NearestN(&Point[float32]{3, 3}, 1, 0.1))
+	assertExact(t, g, points[:20], 3, 0.2)
+	assertExact(t, g, []*Point[float32]{{4, 4}, {-1, -1}}, 3, 10)
+
+	// Nothing is left queued for the next frame.
+	g.EndFrame()
+	assert.Equal(t, Point[float32]{5, 5}, *points[0])
+}
@@ -0,0 +1,195 @@
+package grpcserver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/galaxyblack/microspace"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ContentSubtype is the content-subtype the service's codec is registered
+// under, so requests to it must have the content type
+// "application/grpc+microspace".
+const ContentSubtype = "microspace"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// message is a message of the service, encoded in the Protocol Buffers
+// wire format.
+type message interface {
+	marshal() []byte
+	unmarshal(data []byte) error
+}
+
+// codec implements encoding.Codec for the service's messages, which are
+// wire compatible with generated code. It isn't named "proto" like the
+// codec for generated code, which would replace that codec for every
+// service in the process.
+type codec struct{}
+
+// Marshal implements encoding.Codec.Marshal
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("grpcserver: cannot marshal %T", v)
+	}
+
+	return m.marshal(), nil
+}
+
+// Unmarshal implements encoding.Codec.Unmarshal
+func (codec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("grpcserver: cannot unmarshal %T", v)
+	}
+
+	return m.unmarshal(data)
+}
+
+// Name implements encoding.Codec.Name
+func (codec) Name() string {
+	return ContentSubtype
+}
+
+// point is a Point message.
+type point struct{ X, Y float64 }
+
+func (p *point) marshal() []byte {
+	return microspace.PointToProto(microspace.Point[float64]{X: p.X, Y: p.Y})
+}
+
+func (p *point) unmarshal(data []byte) error {
+	decoded, err := microspace.PointFromProto[float64](data)
+	p.X, p.Y = decoded.X, decoded.Y
+	return err
+}
+
+// nearestNRequest is a NearestNRequest message.
+type nearestNRequest struct {
+	point point
+	n     int32
+	max   float64
+}
+
+func (r *nearestNRequest) marshal() []byte {
+	buf := protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), r.point.marshal())
+	buf = protowire.AppendVarint(protowire.AppendTag(buf, 2, protowire.VarintType), uint64(int64(r.n)))
+	return appendDouble(buf, 3, r.max)
+}
+
+func (r *nearestNRequest) unmarshal(data []byte) error {
+	return parse(data, func(num protowire.Number, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			return r.point.unmarshal(b)
+		case 2:
+			r.n = int32(v)
+		case 3:
+			r.max = math.Float64frombits(v)
+		}
+		return nil
+	})
+}
+
+// withinRadiusRequest is a WithinRadiusRequest message.
+type withinRadiusRequest struct {
+	point  point
+	radius float64
+}
+
+func (r *withinRadiusRequest) marshal() []byte {
+	buf := protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), r.point.marshal())
+	return appendDouble(buf, 2, r.radius)
+}
+
+func (r *withinRadiusRequest) unmarshal(data []byte) error {
+	return parse(data, func(num protowire.Number, v uint64, b []byte) error {
+		switch num {
+		case 1:
+			return r.point.unmarshal(b)
+		case 2:
+			r.radius = math.Float64frombits(v)
+		}
+		return nil
+	})
+}
+
+// insertResponse is an InsertResponse message.
+type insertResponse struct {
+	count uint64
+}
+
+func (r *insertResponse) marshal() []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), r.count)
+}
+
+func (r *insertResponse) unmarshal(data []byte) error {
+	return parse(data, func(num protowire.Number, v uint64, b []byte) error {
+		if num == 1 {
+			r.count = v
+		}
+		return nil
+	})
+}
+
+// rawMessage is a message which was already encoded, such as Neighbors
+// from microspace.NeighborsToProto.
+type rawMessage []byte
+
+func (r rawMessage) marshal() []byte {
+	return r
+}
+
+func (r rawMessage) unmarshal(data []byte) error {
+	return errors.New("grpcserver: cannot unmarshal into an encoded message")
+}
+
+// appendDouble appends a double field.
+func appendDouble(buf []byte, num protowire.Number, v float64) []byte {
+	return protowire.AppendFixed64(protowire.AppendTag(buf, num, protowire.Fixed64Type), math.Float64bits(v))
+}
+
+// parse calls fn with every varint, double and length-delimited field of
+// the message: its number, and either its numeric value or its bytes.
+// Other fields are skipped.
+func parse(data []byte, fn func(num protowire.Number, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var v uint64
+		var b []byte
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			typ = -1
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if typ >= 0 {
+			if err := fn(num, v, b); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package grpcserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestCodec(t *testing.T) {
+	c := codec{}
+	assert.Equal(t, ContentSubtype, c.Name())
+	assert.Equal(t, c, encoding.GetCodec(ContentSubtype))
+
+	for _, m := range []message{
+		&point{X: 1.5, Y: -2},
+		&nearestNRequest{point: point{X: 1, Y: 2}, n: -1, max: 3.5},
+		&withinRadiusRequest{point: point{X: -1, Y: 0.5}, radius: 2},
+		&insertResponse{count: 300},
+	} {
+		data, err := c.Marshal(m)
+		assert.NoError(t, err)
+
+		var decoded message
+		switch m.(type) {
+		case *point:
+			decoded = new(point)
+		case *nearestNRequest:
+			decoded = new(nearestNRequest)
+		case *withinRadiusRequest:
+			decoded = new(withinRadiusRequest)
+		case *insertResponse:
+			decoded = new(insertResponse)
+		}
+		assert.NoError(t, c.Unmarshal(data, decoded))
+		assert.Equal(t, m, decoded)
+	}
+
+	// Unknown fields are skipped, as generated code does.
+	var r insertResponse
+	assert.NoError(t, c.Unmarshal([]byte{0x15, 1, 2, 3, 4, 0x08, 7}, &r))
+	assert.Equal(t, uint64(7), r.count)
+
+	assert.Error(t, c.Unmarshal([]byte{0x0a, 5, 1}, new(nearestNRequest)))
+	_, err := c.Marshal("point")
+	assert.Error(t, err)
+	assert.Error(t, c.Unmarshal(nil, new(int)))
+}
@@ -0,0 +1,186 @@
+// Package grpcserver serves queries on a microspace index over gRPC, as
+// the SpatialIndex service defined in microspace.proto. Clients in any
+// language can be generated from the schema.
+//
+// The messages are encoded by hand rather than by generated code, with a
+// codec registered under its own content-subtype, ContentSubtype, so it
+// doesn't replace the codec of other services on the same server. Clients
+// must send requests with that content-subtype, which Go clients do with
+// CallOption:
+//
+//	s := grpc.NewServer()
+//	grpcserver.New[float64](index).Register(s)
+//
+//	conn, err := grpc.NewClient(addr, grpc.WithDefaultCallOptions(grpcserver.CallOption()))
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/galaxyblack/microspace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the SpatialIndex service over an index. Requests are
+// handled one at a time, so the index doesn't need to be safe for
+// concurrent use.
+type Server[T microspace.Float] struct {
+	mu    sync.Mutex
+	index microspace.Index[T]
+}
+
+// New returns a server over the index. Insert is only served if the
+// index implements microspace.InsertIndex.
+func New[T microspace.Float](index microspace.Index[T]) *Server[T] {
+	return &Server[T]{index: index}
+}
+
+// Register registers the SpatialIndex service with the gRPC server.
+func (s *Server[T]) Register(r grpc.ServiceRegistrar) {
+	r.RegisterService(&serviceDesc, s)
+}
+
+// CallOption returns the option gRPC clients need to send their requests
+// with the service's content-subtype.
+func CallOption() grpc.CallOption {
+	return grpc.CallContentSubtype(ContentSubtype)
+}
+
+// nearestN implements SpatialIndex.NearestN
+func (s *Server[T]) nearestN(ctx context.Context, req *nearestNRequest) (message, error) {
+	if !finite(req.point.X, req.point.Y, req.max) {
+		return nil, status.Error(codes.InvalidArgument, "coordinates must be finite")
+	}
+	if req.n < -1 {
+		return nil, status.Error(codes.InvalidArgument, "n must be -1 or more")
+	}
+
+	p := microspace.Point[T]{X: T(req.point.X), Y: T(req.point.Y)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// n comes from the client, so it's never allowed past the number of
+	// points, which would otherwise size the results.
+	n := int(req.n)
+	if size := len(s.index.Points()); n == -1 || n > size {
+		n = size
+	}
+
+	return rawMessage(microspace.NeighborsToProto(microspace.NearestNWithDistance(s.index, &p, n, T(req.max)))), nil
+}
+
+// withinRadius implements SpatialIndex.WithinRadius
+func (s *Server[T]) withinRadius(ctx context.Context, req *withinRadiusRequest) (message, error) {
+	if !finite(req.point.X, req.point.Y, req.radius) {
+		return nil, status.Error(codes.InvalidArgument, "coordinates must be finite")
+	}
+	if req.radius <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "radius must be positive")
+	}
+
+	p := microspace.Point[T]{X: T(req.point.X), Y: T(req.point.Y)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return rawMessage(microspace.NeighborsToProto(microspace.NearestNWithDistance(s.index, &p, -1, T(req.radius)))), nil
+}
+
+// insert implements SpatialIndex.Insert, inserting each point as it's
+// received.
+func (s *Server[T]) insert(stream grpc.ServerStream) error {
+	ii, ok := s.index.(microspace.InsertIndex[T])
+	if !ok {
+		return status.Error(codes.Unimplemented, "index doesn't support inserts")
+	}
+
+	var count uint64
+	for {
+		var p point
+		if err := stream.RecvMsg(&p); errors.Is(err, io.EOF) {
+			return stream.SendMsg(&insertResponse{count: count})
+		} else if err != nil {
+			return err
+		}
+		if !finite(p.X, p.Y) {
+			return status.Error(codes.InvalidArgument, "coordinates must be finite")
+		}
+
+		s.mu.Lock()
+		ii.Insert(&microspace.Point[T]{X: T(p.X), Y: T(p.Y)})
+		s.mu.Unlock()
+		count++
+	}
+}
+
+// finite returns whether none of the values are infinite or NaN.
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// spatialIndexServer is the service implementation, which the generic
+// Server is behind for any type.
+type spatialIndexServer interface {
+	nearestN(context.Context, *nearestNRequest) (message, error)
+	withinRadius(context.Context, *withinRadiusRequest) (message, error)
+	insert(grpc.ServerStream) error
+}
+
+// serviceDesc describes the SpatialIndex service to gRPC.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "microspace.SpatialIndex",
+	HandlerType: (*spatialIndexServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NearestN",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				return handleUnary(srv, ctx, dec, interceptor, "NearestN", spatialIndexServer.nearestN)
+			},
+		},
+		{
+			MethodName: "WithinRadius",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				return handleUnary(srv, ctx, dec, interceptor, "WithinRadius", spatialIndexServer.withinRadius)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Insert",
+			Handler:       func(srv any, stream grpc.ServerStream) error { return srv.(spatialIndexServer).insert(stream) },
+			ClientStreams: true,
+		},
+	},
+	Metadata: "microspace.proto",
+}
+
+// handleUnary decodes the request of a unary method and calls it, through
+// the interceptor if there is one.
+func handleUnary[Req any, PReq interface {
+	*Req
+	message
+}](srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor, method string, fn func(spatialIndexServer, context.Context, PReq) (message, error)) (any, error) {
+	req := PReq(new(Req))
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return fn(srv.(spatialIndexServer), ctx, req)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/microspace.SpatialIndex/" + method}
+	return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return fn(srv.(spatialIndexServer), ctx, req.(PReq))
+	})
+}
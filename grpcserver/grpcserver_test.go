@@ -0,0 +1,105 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/galaxyblack/microspace"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// registrar records the registered service.
+type registrar struct {
+	desc *grpc.ServiceDesc
+	impl any
+}
+
+func (r *registrar) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	r.desc, r.impl = desc, impl
+}
+
+// insertStream is a client stream of points to insert.
+type insertStream struct {
+	grpc.ServerStream
+	points []point
+	sent   []any
+}
+
+func (s *insertStream) RecvMsg(m any) error {
+	if len(s.points) == 0 {
+		return io.EOF
+	}
+
+	*m.(*point), s.points = s.points[0], s.points[1:]
+	return nil
+}
+
+func (s *insertStream) SendMsg(m any) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// call calls the unary method with the request, as gRPC would.
+func call(r *registrar, method string, req message, interceptor grpc.UnaryServerInterceptor) ([]microspace.Neighbor[float64], error) {
+	data := req.marshal()
+	dec := func(v any) error { return codec{}.Unmarshal(data, v) }
+	for _, m := range r.desc.Methods {
+		if m.MethodName == method {
+			resp, err := m.Handler(r.impl, context.Background(), dec, interceptor)
+			if err != nil {
+				return nil, err
+			}
+			return microspace.NeighborsFromProto[float64](resp.(message).marshal())
+		}
+	}
+
+	panic("unknown method " + method)
+}
+
+func TestServer(t *testing.T) {
+	r := &registrar{}
+	New[float64](microspace.NewAxdex[float64](0)).Register(r)
+	assert.Equal(t, "microspace.SpatialIndex", r.desc.ServiceName)
+
+	stream := &insertStream{points: []point{{0, 0}, {1, 0}, {3, 0}, {0, 5}}}
+	assert.NoError(t, r.desc.Streams[0].Handler(r.impl, stream))
+	assert.Equal(t, []any{&insertResponse{count: 4}}, stream.sent)
+
+	neighbors, err := call(r, "NearestN", &nearestNRequest{point: point{X: 0.9}, n: 2}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, neighbors, 2)
+	assert.Equal(t, microspace.Point[float64]{X: 1}, *neighbors[0].Point)
+	assert.Equal(t, microspace.Point[float64]{X: 0}, *neighbors[1].Point)
+
+	neighbors, err = call(r, "NearestN", &nearestNRequest{n: math.MaxInt32}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, neighbors, 4)
+
+	_, err = call(r, "NearestN", &nearestNRequest{n: -2}, nil)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	var intercepted string
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		intercepted = info.FullMethod
+		return handler(ctx, req)
+	}
+	neighbors, err = call(r, "WithinRadius", &withinRadiusRequest{point: point{X: 3}, radius: 2}, interceptor)
+	assert.NoError(t, err)
+	assert.Equal(t, "/microspace.SpatialIndex/WithinRadius", intercepted)
+	assert.Len(t, neighbors, 2)
+	assert.Equal(t, 4.0, neighbors[1].DistSqr)
+
+	_, err = call(r, "WithinRadius", &withinRadiusRequest{radius: 0}, nil)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// A k-d tree is built once and can't be inserted into.
+	r = &registrar{}
+	New[float64](microspace.NewKDTree[float64](nil)).Register(r)
+	err = r.desc.Streams[0].Handler(r.impl, &insertStream{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,56 @@
+package microspace
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// RenderHeatmap rasterizes the density of the index's points within the
+// bounds into a `width` by `height` PNG written to w. Points are binned
+// into one cell per pixel, and each pixel is colored by its cell's count
+// relative to the densest cell, from the first color of the palette for
+// empty cells to the last for the densest. The top of the image is the
+// bounds' maximum Y.
+func RenderHeatmap[T Float](w io.Writer, index Index[T], bounds Rect[T], width, height int, palette color.Palette) error {
+	if width < 1 || height < 1 || len(palette) == 0 {
+		return errors.New("microspace: heatmap needs a positive size and a palette")
+	}
+
+	counts := make([]int, width*height)
+	most := 0
+	sx := float64(width) / float64(bounds.Max.X-bounds.Min.X)
+	sy := float64(height) / float64(bounds.Max.Y-bounds.Min.Y)
+	for _, p := range index.Points() {
+		if !bounds.Contains(p) {
+			continue
+		}
+
+		x := int(float64(p.X-bounds.Min.X) * sx)
+		y := height - 1 - int(float64(p.Y-bounds.Min.Y)*sy)
+		if x >= width {
+			x = width - 1
+		}
+		if y < 0 {
+			y = 0
+		}
+
+		i := y*width + x
+		if counts[i]++; counts[i] > most {
+			most = counts[i]
+		}
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	if most > 0 {
+		top := len(palette) - 1
+		for i, count := range counts {
+			// Round up so that any occupied cell stands out from empty ones.
+			img.Pix[i] = uint8((count*top + most - 1) / most)
+		}
+	}
+
+	return png.Encode(w, img)
+}
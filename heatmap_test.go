@@ -0,0 +1,35 @@
+package microspace
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderHeatmap(t *testing.T) {
+	idx := NewAxdex[float32](4)
+	for _, p := range []*Point[float32]{{0.1, 0.1}, {0.2, 0.2}, {0.9, 0.9}, {5, 5}} {
+		idx.Insert(p)
+	}
+
+	palette := color.Palette{color.Black, color.Gray{0x80}, color.White}
+	bounds := Rect[float32]{Max: Point[float32]{1, 1}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, RenderHeatmap[float32](&buf, idx, bounds, 2, 2, palette))
+
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, img.Bounds().Dx())
+
+	gray := func(x, y int) uint8 { return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y }
+	assert.Equal(t, uint8(0xff), gray(0, 1))
+	assert.Equal(t, uint8(0x80), gray(1, 0))
+	assert.Equal(t, uint8(0), gray(1, 1))
+	assert.Equal(t, uint8(0), gray(0, 0))
+
+	assert.Error(t, RenderHeatmap[float32](&buf, idx, bounds, 0, 2, palette))
+}
@@ -0,0 +1,63 @@
+package microspace
+
+import "sort"
+
+// hilbertOrder is the number of bits per coordinate used for Hilbert keys.
+const hilbertOrder = 16
+
+// HilbertSort sorts the points in place along a Hilbert curve covering the
+// bounds, so that points near each other in space end up near each other
+// in the slice. Indexes only hold pointers to points, so it's the caller's
+// storage that decides where points lie in memory: allocating it in this
+// order before inserting it into an index keeps spatial neighbors close
+// together in memory. Frozen and FlatIndex own their coordinates, but
+// already store them in the order their queries read them, along the
+// sorted axis, so there's nothing for them to gain from the curve. Points
+// outside the bounds are clamped onto its edges.
+func HilbertSort[T Float](points []*Point[T], bounds Rect[T]) {
+	keys := make(map[*Point[T]]uint64, len(points))
+	for _, p := range points {
+		keys[p] = hilbertKey(p, bounds)
+	}
+
+	sort.SliceStable(points, func(i, j int) bool { return keys[points[i]] < keys[points[j]] })
+}
+
+// hilbertKey returns the distance of the point along the Hilbert curve
+// covering the bounds.
+func hilbertKey[T Float](p *Point[T], bounds Rect[T]) uint64 {
+	const side = 1<<hilbertOrder - 1
+	x := uint32(clamp(scale(p.X, bounds.Min.X, bounds.Max.X), 0, 1) * side)
+	y := uint32(clamp(scale(p.Y, bounds.Min.Y, bounds.Max.Y), 0, 1) * side)
+
+	var d uint64
+	for s := uint32(1 << (hilbertOrder - 1)); s > 0; s /= 2 {
+		var rx, ry uint32
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+
+		// Rotate the quadrant so the curve stays continuous.
+		if ry == 0 {
+			if rx == 1 {
+				x, y = side-x, side-y
+			}
+			x, y = y, x
+		}
+	}
+
+	return d
+}
+
+// scale maps v from the range [lo, hi] onto [0, 1].
+func scale[T Float](v, lo, hi T) float64 {
+	if hi <= lo {
+		return 0
+	}
+
+	return float64(v-lo) / float64(hi-lo)
+}
@@ -0,0 +1,21 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHilbertSort(t *testing.T) {
+	var points []*Point[float32]
+	for _, c := range [][2]float32{{1, 1}, {0, 1}, {1, 0}, {0, 0}} {
+		points = append(points, &Point[float32]{c[0], c[1]})
+	}
+
+	HilbertSort(points, Rect[float32]{Max: Point[float32]{1, 1}})
+	var order []Point[float32]
+	for _, p := range points {
+		order = append(order, *p)
+	}
+	assert.Equal(t, []Point[float32]{{0, 0}, {0, 1}, {1, 1}, {1, 0}}, order)
+}
@@ -0,0 +1,12 @@
+package microspace
+
+// NearestNHinted is like NearestN, but starts with a guess at the radius
+// the neighbors lie within, typically the distance to the furthest result
+// of the same query in the previous frame. With a good guess only a small
+// window of the axis is examined. If fewer than `n` neighbors are found
+// within the guess it's doubled until they are or it reaches `max`, so a
+// bad guess costs time but never changes the results. The point doesn't
+// need to be in the index.
+func (a *Axdex[T]) NearestNHinted(p *Point[T], n int, max, radius T) []*Point[T] {
+	return a.NearestNWith(p, n, max, QueryOptions[T]{Window: radius})
+}
@@ -0,0 +1,49 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNHinted(t *testing.T) {
+	idx := NewAxdex[float32](100)
+	for i := 0; i < 100; i++ {
+		idx.Insert(&Point[float32]{float32(i), 0})
+	}
+
+	p := &Point[float32]{40.1, 0}
+	expected := idx.NearestNWith(p, 4, 1000, QueryOptions[float32]{})
+	assert.Len(t, expected, 4)
+
+	for _, radius := range []float32{0.01, 2, 3, 50, 5000} {
+		assert.Equal(t, expected, idx.NearestNHinted(p, 4, 1000, radius), "radius %v", radius)
+	}
+
+	// Results are still limited to max, however large the guess.
+	assert.Len(t, idx.NearestNHinted(p, 4, 1, 10), 2)
+}
+
+func TestNearestNHintedUnlimited(t *testing.T) {
+	idx := NewAxdex[float64](1000)
+	for i := 0; i < 1000; i++ {
+		idx.Insert(&Point[float64]{float64(i), 0})
+	}
+
+	// More neighbors than there are points, with no max to stop the
+	// window doubling.
+	assert.Len(t, idx.NearestNHinted(&Point[float64]{500, 0}, 1001, 0, 1), 1000)
+	assert.Len(t, idx.NearestNWith(&Point[float64]{1e6, 0}, 3, 0, QueryOptions[float64]{Window: 1e-6}), 3)
+}
+
+func BenchmarkNearestNHintedAll(b *testing.B) {
+	idx := NewAxdex[float64](1000)
+	for i := 0; i < 1000; i++ {
+		idx.Insert(&Point[float64]{float64(i), 0})
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		idx.NearestNHinted(&Point[float64]{500, 0}, 1001, 0, 1)
+	}
+}
@@ -0,0 +1,271 @@
+package microspace
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// HNSWConfig holds the parameters of an HNSW index.
+type HNSWConfig struct {
+	// M is the number of neighbors each point is linked to on every
+	// layer, or twice that on the bottom layer. Higher values improve
+	// recall at the cost of memory and insert time.
+	M int
+	// EfConstruction is the size of the candidate list used to find a
+	// new point's neighbors when it's inserted.
+	EfConstruction int
+	// EfSearch is the size of the candidate list used for queries. It's
+	// raised to `n` for queries asking for more neighbors than that.
+	EfSearch int
+	// Seed seeds the source used to pick each point's top layer.
+	Seed int64
+}
+
+// DefaultHNSWConfig is a reasonable starting configuration for HNSW.
+var DefaultHNSWConfig = HNSWConfig{M: 16, EfConstruction: 200, EfSearch: 50}
+
+// HNSW is an approximate nearest neighbor index using a hierarchical
+// navigable small world graph. Each point is linked to its nearest
+// neighbors on a random number of layers, with exponentially fewer
+// points on each higher layer. Queries greedily walk down from the
+// sparse top layer to the bottom one, only visiting a small part of the
+// graph along the way.
+type HNSW[T Float] struct {
+	config    HNSWConfig
+	levelMult float64
+	rand      *rand.Rand
+
+	nodes []*hnswNode[T]
+	entry *hnswNode[T]
+}
+
+// hnswNode is a point in the graph and its links on each layer it's on.
+type hnswNode[T Float] struct {
+	p       *Point[T]
+	friends [][]*hnswNode[T]
+}
+
+// NewHNSW returns a new HNSW index with the provided configuration.
+// Parameters that aren't set are taken from DefaultHNSWConfig.
+func NewHNSW[T Float](config HNSWConfig) *HNSW[T] {
+	if config.M < 2 {
+		config.M = DefaultHNSWConfig.M
+	}
+	if config.EfConstruction < 1 {
+		config.EfConstruction = DefaultHNSWConfig.EfConstruction
+	}
+	if config.EfSearch < 1 {
+		config.EfSearch = DefaultHNSWConfig.EfSearch
+	}
+
+	return &HNSW[T]{
+		config:    config,
+		levelMult: 1 / math.Log(float64(config.M)),
+		rand:      rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+var _ Index[float32] = new(HNSW[float32])
+
+// Insert adds a point to the index.
+func (h *HNSW[T]) Insert(p *Point[T]) {
+	level := int(-math.Log(1-h.rand.Float64()) * h.levelMult)
+	node := &hnswNode[T]{p: p, friends: make([][]*hnswNode[T], level+1)}
+	h.nodes = append(h.nodes, node)
+
+	if h.entry == nil {
+		h.entry = node
+		return
+	}
+
+	top := len(h.entry.friends) - 1
+	entries := []*hnswNode[T]{h.entry}
+	for l := top; l > level; l-- {
+		entries = h.searchLayer(p, entries, 1, l).Sorted()
+	}
+
+	start := level
+	if top < start {
+		start = top
+	}
+
+	for l := start; l >= 0; l-- {
+		found := h.searchLayer(p, entries, h.config.EfConstruction, l)
+		limit := h.maxFriends(l)
+
+		friends := found.Sorted()
+		if len(friends) > h.config.M {
+			friends = friends[:h.config.M]
+		}
+
+		node.friends[l] = append([]*hnswNode[T](nil), friends...)
+		for _, friend := range node.friends[l] {
+			friend.friends[l] = append(friend.friends[l], node)
+			if len(friend.friends[l]) > limit {
+				friend.prune(l, limit)
+			}
+		}
+
+		entries = found.Sorted()
+	}
+
+	if level > top {
+		h.entry = node
+	}
+}
+
+// Points implements Index.Points
+func (h *HNSW[T]) Points() []*Point[T] {
+	points := make([]*Point[T], len(h.nodes))
+	for i, node := range h.nodes {
+		points[i] = node.p
+	}
+
+	return points
+}
+
+// NearestN implements Index.NearestN. The results are approximate: they
+// never include points further than `max` away, but may miss some of the
+// true nearest neighbors. The point doesn't need to be in the index.
+func (h *HNSW[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return h.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance, with
+// the same approximate results as NearestN.
+func (h *HNSW[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return h.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` approximate nearest neighbors
+// of p within `max`.
+func (h *HNSW[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(h.nodes) {
+		n = len(h.nodes)
+	}
+	if n <= 0 || h.entry == nil {
+		return &neighborList[T]{}
+	}
+
+	entries := []*hnswNode[T]{h.entry}
+	for l := len(h.entry.friends) - 1; l > 0; l-- {
+		entries = h.searchLayer(p, entries, 1, l).Sorted()
+	}
+
+	ef := h.config.EfSearch
+	if n > ef {
+		ef = n
+	}
+
+	found := h.searchLayer(p, entries, ef, 0)
+	results := newNeighborList[T](n)
+	for i, node := range found.Sorted() {
+		if results.Full() || found.dists[i] > max*max {
+			break
+		}
+		results.Insert(node.p, found.dists[i])
+	}
+
+	return results
+}
+
+// maxFriends returns the most links a node may have on the layer.
+func (h *HNSW[T]) maxFriends(layer int) int {
+	if layer == 0 {
+		return 2 * h.config.M
+	}
+
+	return h.config.M
+}
+
+// searchLayer finds the `ef` nodes on the layer closest to p, starting
+// from the entry nodes and following links for as long as they lead
+// closer to p.
+func (h *HNSW[T]) searchLayer(p *Point[T], entries []*hnswNode[T], ef, layer int) *rankedList[*hnswNode[T], T] {
+	visited := map[*hnswNode[T]]bool{}
+	candidates := &hnswQueue[T]{}
+	found := newRankedList[*hnswNode[T], T](ef)
+
+	for _, e := range entries {
+		visited[e] = true
+		d := e.p.DistanceToSqr(p)
+		heap.Push(candidates, hnswItem[T]{node: e, dist: d})
+		found.Insert(e, d)
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswItem[T])
+		if found.Full() && c.dist > found.Worst() {
+			break
+		}
+
+		for _, friend := range c.node.friends[layer] {
+			if visited[friend] {
+				continue
+			}
+			visited[friend] = true
+
+			d := friend.p.DistanceToSqr(p)
+			if !found.Full() || d < found.Worst() {
+				heap.Push(candidates, hnswItem[T]{node: friend, dist: d})
+				found.Insert(friend, d)
+			}
+		}
+	}
+
+	return &found
+}
+
+// prune drops the node's furthest links on the layer until it has at
+// most `limit` of them.
+func (n *hnswNode[T]) prune(layer, limit int) {
+	friends := n.friends[layer]
+	sort.Slice(friends, func(i, j int) bool {
+		return friends[i].p.DistanceToSqr(n.p) < friends[j].p.DistanceToSqr(n.p)
+	})
+
+	n.friends[layer] = friends[:limit]
+}
+
+// hnswItem is a node queued for expansion during a search.
+type hnswItem[T Float] struct {
+	node *hnswNode[T]
+	dist T
+}
+
+// hnswQueue is a priority queue where closer nodes are ordered first.
+type hnswQueue[T Float] struct {
+	items []hnswItem[T]
+}
+
+// Len implements sort.Interface.Len
+func (q *hnswQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// Less implements sort.Interface.Less
+func (q *hnswQueue[T]) Less(a, b int) bool {
+	return q.items[a].dist < q.items[b].dist
+}
+
+// Swap implements sort.Interface.Swap
+func (q *hnswQueue[T]) Swap(a, b int) {
+	q.items[a], q.items[b] = q.items[b], q.items[a]
+}
+
+// Push implements heap.Interface.Push
+func (q *hnswQueue[T]) Push(x interface{}) {
+	q.items = append(q.items, x.(hnswItem[T]))
+}
+
+// Pop implements heap.Interface.Pop
+func (q *hnswQueue[T]) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+var _ heap.Interface = new(hnswQueue[float32])
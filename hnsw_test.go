@@ -0,0 +1,54 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHNSWNearest(t *testing.T) {
+	h := NewHNSW[float32](HNSWConfig{M: 8, EfConstruction: 64, EfSearch: 32, Seed: 1})
+	exact := NewAxdex[float32](1000)
+
+	points := []*Point[float32]{}
+	for i := 0; i < 1000; i++ {
+		p := &Point[float32]{rand.Float32(), rand.Float32()}
+		points = append(points, p)
+		h.Insert(p)
+		exact.Insert(p)
+	}
+	assert.Len(t, h.Points(), 1000)
+
+	found, total := 0, 0
+	for _, p := range points[:100] {
+		approx := h.NearestN(p, 5, 0.2)
+		assert.Equal(t, p, approx[0])
+		for _, q := range approx {
+			assert.True(t, q.DistanceToSqr(p) <= 0.2*0.2)
+		}
+
+		truth := map[*Point[float32]]bool{}
+		for _, q := range exact.NearestNAt(0, p, 5, 0.2) {
+			truth[q] = true
+		}
+		for _, q := range approx {
+			if truth[q] {
+				found++
+			}
+		}
+		total += len(truth)
+	}
+
+	assert.True(t, float64(found)/float64(total) > 0.95, "recall %d/%d", found, total)
+}
+
+func TestHNSWEmpty(t *testing.T) {
+	h := NewHNSW[float64](HNSWConfig{})
+	assert.Empty(t, h.NearestN(&Point[float64]{0, 0}, 3, 1))
+
+	p := &Point[float64]{1, 1}
+	h.Insert(p)
+	assert.Equal(t, []*Point[float64]{p}, h.NearestN(&Point[float64]{0, 0}, -1, 2))
+	assert.Empty(t, h.NearestN(&Point[float64]{0, 0}, -1, 1))
+}
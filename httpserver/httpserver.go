@@ -0,0 +1,287 @@
+// Package httpserver serves queries on a microspace index over HTTP, for
+// running a small location lookup service without writing handlers:
+//
+//	GET  /nearest?x=1&y=2&n=5&max=10
+//	GET  /within?minx=0&miny=0&maxx=10&maxy=10
+//	GET  /within?x=1&y=2&radius=3
+//	POST /insert  [{"x": 1, "y": 2}, ...]
+//
+// Query results are JSON lists of points, such as
+// [{"x": 1, "y": 2, "distance": 0.5}], and errors are JSON objects such
+// as {"error": "missing x"}.
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/galaxyblack/microspace"
+)
+
+// maxInsertBytes is the largest request body /insert accepts, so a single
+// request can't exhaust the server's memory.
+const maxInsertBytes = 8 << 20
+
+// Middleware wraps a handler, such as to log, authenticate or rate limit
+// requests.
+type Middleware func(http.Handler) http.Handler
+
+// Server is an http.Handler serving queries on an index. Requests are
+// handled one at a time, so the index doesn't need to be safe for
+// concurrent use.
+type Server[T microspace.Float] struct {
+	mu      sync.Mutex
+	index   microspace.Index[T]
+	handler http.Handler
+}
+
+// Point is a point in a request or response. The distance is only set
+// on query results.
+type Point struct {
+	X        float64  `json:"x"`
+	Y        float64  `json:"y"`
+	Distance *float64 `json:"distance,omitempty"`
+}
+
+// New returns a server over the index, with the middleware wrapped
+// around every endpoint, the first being outermost. /within only accepts
+// rectangles if the index implements microspace.RangeIndex, and /insert
+// is only served if it implements microspace.InsertIndex.
+func New[T microspace.Float](index microspace.Index[T], middleware ...Middleware) *Server[T] {
+	s := &Server[T]{index: index}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nearest", s.nearest)
+	mux.HandleFunc("/within", s.within)
+	mux.HandleFunc("/insert", s.insert)
+
+	s.handler = mux
+	for i := len(middleware) - 1; i >= 0; i-- {
+		s.handler = middleware[i](s.handler)
+	}
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP
+func (s *Server[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// nearest serves the nearest neighbors of the point x,y, with optional
+// `n` and `max` defaulting to 1 and unlimited.
+func (s *Server[T]) nearest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	q := query{r: r}
+	p := point[T](&q, "x", "y")
+	n := q.int("n", 1)
+	max := q.float("max", 0)
+	if q.err == nil && n < -1 {
+		q.err = errors.New("n must be -1 or more")
+	}
+	if q.err != nil {
+		writeError(w, http.StatusBadRequest, q.err)
+		return
+	}
+
+	// n comes from the client, so it's never allowed past the number of
+	// points, which would otherwise size the results.
+	s.mu.Lock()
+	if size := len(s.index.Points()); n == -1 || n > size {
+		n = size
+	}
+	neighbors := microspace.NearestNWithDistance(s.index, &p, n, T(max))
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, fromNeighbors(neighbors))
+}
+
+// within serves the points inside the rectangle minx,miny to maxx,maxy,
+// or within `radius` of the point x,y.
+func (s *Server[T]) within(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	q := query{r: r}
+	if !r.URL.Query().Has("radius") {
+		min, max := point[T](&q, "minx", "miny"), point[T](&q, "maxx", "maxy")
+		if q.err != nil {
+			writeError(w, http.StatusBadRequest, q.err)
+			return
+		}
+		ri, ok := s.index.(microspace.RangeIndex[T])
+		if !ok {
+			writeError(w, http.StatusNotImplemented, errors.New("index doesn't support rectangle queries"))
+			return
+		}
+
+		s.mu.Lock()
+		points := ri.WithinRect(min, max)
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, fromPoints(points))
+		return
+	}
+
+	p := point[T](&q, "x", "y")
+	radius := q.float("radius", 0)
+	if q.err == nil && radius <= 0 {
+		q.err = errors.New("radius must be positive")
+	}
+	if q.err != nil {
+		writeError(w, http.StatusBadRequest, q.err)
+		return
+	}
+
+	s.mu.Lock()
+	neighbors := microspace.NearestNWithDistance(s.index, &p, -1, T(radius))
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, fromNeighbors(neighbors))
+}
+
+// insert adds the list of points in the request body to the index.
+func (s *Server[T]) insert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	ii, ok := s.index.(microspace.InsertIndex[T])
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New("index doesn't support inserts"))
+		return
+	}
+
+	var points []Point
+	r.Body = http.MaxBytesReader(w, r.Body, maxInsertBytes)
+	if err := json.NewDecoder(r.Body).Decode(&points); err != nil {
+		status := http.StatusBadRequest
+		if _, ok := err.(*http.MaxBytesError); ok {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	// Coordinates are checked once converted, as values which fit in a
+	// float64 may still overflow a float32 index.
+	converted := make([]microspace.Point[T], len(points))
+	for i, p := range points {
+		converted[i] = microspace.Point[T]{X: T(p.X), Y: T(p.Y)}
+		if !finite(converted[i].X) || !finite(converted[i].Y) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid point %v,%v", p.X, p.Y))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	for _, p := range converted {
+		ii.Insert(&p)
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// query parses numbers from a request's query string, keeping the first
+// error.
+type query struct {
+	r   *http.Request
+	err error
+}
+
+// float returns the named number, or the default if it's missing.
+func (q *query) float(name string, def float64) float64 {
+	s := q.r.URL.Query().Get(name)
+	if s == "" || q.err != nil {
+		return def
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		q.err = fmt.Errorf("invalid %s %q", name, s)
+	}
+
+	return v
+}
+
+// int returns the named integer, or the default if it's missing.
+func (q *query) int(name string, def int) int {
+	s := q.r.URL.Query().Get(name)
+	if s == "" || q.err != nil {
+		return def
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		q.err = fmt.Errorf("invalid %s %q", name, s)
+	}
+
+	return v
+}
+
+// point returns the point made of the two named, required coordinates.
+func point[T microspace.Float](q *query, x, y string) microspace.Point[T] {
+	for _, name := range []string{x, y} {
+		if q.err == nil && !q.r.URL.Query().Has(name) {
+			q.err = fmt.Errorf("missing %s", name)
+		}
+	}
+
+	p := microspace.Point[T]{X: T(q.float(x, 0)), Y: T(q.float(y, 0))}
+	if q.err == nil && (!finite(p.X) || !finite(p.Y)) {
+		q.err = fmt.Errorf("invalid %s,%s", x, y)
+	}
+
+	return p
+}
+
+// finite returns true if v is neither NaN nor infinite.
+func finite[T microspace.Float](v T) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
+
+// fromPoints returns the points for a response.
+func fromPoints[T microspace.Float](points []*microspace.Point[T]) []Point {
+	results := make([]Point, len(points))
+	for i, p := range points {
+		results[i] = Point{X: float64(p.X), Y: float64(p.Y)}
+	}
+
+	return results
+}
+
+// fromNeighbors returns the neighbors for a response, with their
+// distances.
+func fromNeighbors[T microspace.Float](neighbors []microspace.Neighbor[T]) []Point {
+	results := make([]Point, len(neighbors))
+	for i, n := range neighbors {
+		d := math.Sqrt(float64(n.DistSqr))
+		results[i] = Point{X: float64(n.Point.X), Y: float64(n.Point.Y), Distance: &d}
+	}
+
+	return results
+}
+
+// writeJSON writes the value as the JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error as the JSON response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,100 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/galaxyblack/microspace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	s := New[float64](microspace.NewAxdex[float64](0), tag("outer"), tag("inner"))
+	do := func(method, url, body string) (int, string) {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return w.Code, w.Body.String()
+	}
+
+	code, _ := do(http.MethodPost, "/insert", `[{"x": 0, "y": 0}, {"x": 1, "y": 0}, {"x": 3, "y": 0}, {"x": 0, "y": 5}]`)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+
+	code, body := do(http.MethodGet, "/nearest?x=0.4&y=0.1&n=2", "")
+	assert.Equal(t, http.StatusOK, code)
+	var points []Point
+	assert.NoError(t, json.Unmarshal([]byte(body), &points))
+	assert.Len(t, points, 2)
+	assert.Equal(t, 0.0, points[0].X)
+	assert.Equal(t, 1.0, points[1].X)
+	assert.InDelta(t, 0.4123, *points[0].Distance, 1e-4)
+
+	// n is never allowed to size the results past the index.
+	points = nil
+	code, body = do(http.MethodGet, "/nearest?x=0&y=0&n=4000000000000", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.NoError(t, json.Unmarshal([]byte(body), &points))
+	assert.Len(t, points, 4)
+
+	points = nil
+	code, body = do(http.MethodGet, "/within?minx=-1&miny=-1&maxx=2&maxy=6", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.NoError(t, json.Unmarshal([]byte(body), &points))
+	assert.Len(t, points, 3)
+	assert.Nil(t, points[0].Distance)
+
+	code, body = do(http.MethodGet, "/within?x=3&y=0&radius=2", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `[{"x":3,"y":0,"distance":0},{"x":1,"y":0,"distance":2}]`+"\n", body)
+
+	code, body = do(http.MethodGet, "/nearest?y=1", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, `{"error":"missing x"}`+"\n", body)
+
+	for _, bad := range []struct{ method, url, body string }{
+		{http.MethodGet, "/nearest?x=1&y=a", ""},
+		{http.MethodGet, "/nearest?x=1&y=1&n=-2", ""},
+		{http.MethodGet, "/within?x=1&y=1&radius=0", ""},
+		{http.MethodGet, "/within?minx=1", ""},
+		{http.MethodPost, "/insert", `[{"x": 1`},
+	} {
+		code, _ = do(bad.method, bad.url, bad.body)
+		assert.Equal(t, http.StatusBadRequest, code, bad.url)
+	}
+
+	code, _ = do(http.MethodPost, "/nearest?x=1&y=1", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+
+	// A k-d tree is built once and can't be inserted into.
+	tree := New[float64](microspace.NewKDTree[float64](nil))
+	w := httptest.NewRecorder()
+	tree.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/insert", strings.NewReader("[]")))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	// Coordinates are checked after conversion to the index's type, and
+	// bodies are limited in size.
+	small := New[float32](microspace.NewAxdex[float32](0))
+	w = httptest.NewRecorder()
+	small.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/insert", strings.NewReader(`[{"x": 1e300, "y": 0}]`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	small.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nearest?x=1e300&y=0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	small.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/insert", strings.NewReader("["+strings.Repeat(" ", maxInsertBytes)+"]")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Empty(t, small.index.Points())
+}
@@ -0,0 +1,168 @@
+package microspace
+
+import (
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// ErrQuotaExceeded is returned when an insert would take a tenant over
+// one of its IndexSet quotas.
+var ErrQuotaExceeded = errors.New("microspace: tenant quota exceeded")
+
+// InsertIndex is an index that points can be inserted into.
+type InsertIndex[T Float] interface {
+	Index[T]
+	Insert(p *Point[T])
+}
+
+// IndexSetConfig holds the limits an IndexSet applies to each tenant.
+// Zero values are unlimited.
+type IndexSetConfig struct {
+	// MaxPoints is the most points each tenant's index may hold.
+	MaxPoints int
+	// MaxBytes is the most memory each tenant's index may use, as
+	// estimated from the number of points it holds.
+	MaxBytes int
+	// IdleTimeout is how long a tenant's index may go unused before
+	// EvictIdle removes it.
+	IdleTimeout time.Duration
+}
+
+// IndexSet hosts many independent indexes, one per tenant or world, in a
+// single process. Indexes are created lazily on their tenant's first
+// insert, are limited by per-tenant quotas, and can be evicted once idle.
+// It's safe to use concurrently, and each tenant's index is wrapped in
+// Synced so that it is too.
+type IndexSet[K comparable, T Float] struct {
+	config   IndexSetConfig
+	newIndex func() InsertIndex[T]
+	now      func() time.Time
+
+	mu      sync.Mutex
+	tenants map[K]*tenantIndex[T]
+}
+
+// tenantIndex is a tenant's index and its usage.
+type tenantIndex[T Float] struct {
+	index    InsertIndex[T]
+	points   int
+	lastUsed time.Time
+}
+
+// NewIndexSet returns a new, empty set which creates tenants' indexes with
+// `newIndex`.
+func NewIndexSet[K comparable, T Float](config IndexSetConfig, newIndex func() InsertIndex[T]) *IndexSet[K, T] {
+	return &IndexSet[K, T]{
+		config:   config,
+		newIndex: newIndex,
+		now:      time.Now,
+		tenants:  map[K]*tenantIndex[T]{},
+	}
+}
+
+// pointBytes estimates the memory used by each point in an Axdex: the
+// point itself, its entry on the sorted axis, the pointer to it in the
+// point list, and its key and slot in the axis's lookup map.
+func pointBytes[T Float]() int {
+	var ptr *Point[T]
+	return int(unsafe.Sizeof(Point[T]{}) + unsafe.Sizeof(axisPoint[T]{}) + 3*unsafe.Sizeof(ptr))
+}
+
+// Insert adds the point to the tenant's index, creating the index if the
+// tenant doesn't have one yet. It returns ErrQuotaExceeded, leaving the
+// index unchanged, if the tenant is at its quota, without creating an
+// index for a tenant which has none.
+func (s *IndexSet[K, T]) Insert(id K, p *Point[T]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tenants[id]
+	points := 1
+	if ok {
+		t.lastUsed = s.now()
+		points += t.points
+	}
+	if s.config.MaxPoints > 0 && points > s.config.MaxPoints {
+		return ErrQuotaExceeded
+	}
+	if s.config.MaxBytes > 0 && points*pointBytes[T]() > s.config.MaxBytes {
+		return ErrQuotaExceeded
+	}
+
+	if !ok {
+		t = &tenantIndex[T]{index: NewSynced[T](s.newIndex()), lastUsed: s.now()}
+		s.tenants[id] = t
+	}
+
+	t.index.Insert(p)
+	t.points++
+	return nil
+}
+
+// Get returns the tenant's index, if it has one, marking it as used. The
+// index is wrapped in Synced, so it's safe to use alongside the set.
+func (s *IndexSet[K, T]) Get(id K) (InsertIndex[T], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tenants[id]
+	if !ok {
+		return nil, false
+	}
+
+	t.lastUsed = s.now()
+	return t.index, true
+}
+
+// NearestN runs Index.NearestN against the tenant's index, returning no
+// results if the tenant has no index.
+func (s *IndexSet[K, T]) NearestN(id K, p *Point[T], n int, max T) []*Point[T] {
+	index, ok := s.Get(id)
+	if !ok {
+		return nil
+	}
+
+	return index.NearestN(p, n, max)
+}
+
+// Remove drops the tenant's index, returning false if it had none.
+func (s *IndexSet[K, T]) Remove(id K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.tenants[id]
+	delete(s.tenants, id)
+	return ok
+}
+
+// Len returns the number of tenants with an index.
+func (s *IndexSet[K, T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tenants)
+}
+
+// EvictIdle drops the indexes of tenants which haven't been used for the
+// configured idle timeout, returning how many were dropped. It should be
+// called periodically, and does nothing without an idle timeout.
+func (s *IndexSet[K, T]) EvictIdle() int {
+	if s.config.IdleTimeout <= 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	evicted := 0
+	cutoff := s.now().Add(-s.config.IdleTimeout)
+	for id, t := range s.tenants {
+		if t.lastUsed.Before(cutoff) {
+			delete(s.tenants, id)
+			evicted++
+		}
+	}
+
+	return evicted
+}
@@ -0,0 +1,85 @@
+package microspace
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexSet(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewIndexSet[string, float32](IndexSetConfig{MaxPoints: 2, IdleTimeout: time.Minute}, func() InsertIndex[float32] {
+		return NewAxdex[float32](2)
+	})
+	s.now = func() time.Time { return now }
+
+	a, b := &Point[float32]{0, 0}, &Point[float32]{1, 0}
+	assert.NoError(t, s.Insert("world-1", a))
+	assert.NoError(t, s.Insert("world-1", b))
+	assert.ErrorIs(t, s.Insert("world-1", &Point[float32]{2, 0}), ErrQuotaExceeded)
+	assert.NoError(t, s.Insert("world-2", &Point[float32]{5, 5}))
+	assert.Equal(t, 2, s.Len())
+
+	assert.Equal(t, []*Point[float32]{a, b}, s.NearestN("world-1", a, 2, 5))
+	assert.Empty(t, s.NearestN("world-3", a, 2, 5))
+
+	now = now.Add(50 * time.Second)
+	s.Get("world-1")
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, 1, s.EvictIdle())
+	_, ok := s.Get("world-2")
+	assert.False(t, ok)
+
+	assert.True(t, s.Remove("world-1"))
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestIndexSetMemoryQuota(t *testing.T) {
+	s := NewIndexSet[int, float64](IndexSetConfig{MaxBytes: 3 * pointBytes[float64]()}, func() InsertIndex[float64] {
+		return NewLSH[float64](1, 1, 1, 1)
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.Insert(1, &Point[float64]{}))
+	}
+	assert.ErrorIs(t, s.Insert(1, &Point[float64]{}), ErrQuotaExceeded)
+}
+
+func TestIndexSetQuotaFirstInsert(t *testing.T) {
+	s := NewIndexSet[int, float64](IndexSetConfig{MaxBytes: 1}, func() InsertIndex[float64] {
+		return NewAxdex[float64](0)
+	})
+
+	// A rejected first insert doesn't create the tenant's index.
+	assert.ErrorIs(t, s.Insert(1, &Point[float64]{}), ErrQuotaExceeded)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestIndexSetConcurrent(t *testing.T) {
+	s := NewIndexSet[int, float32](IndexSetConfig{}, func() InsertIndex[float32] {
+		return NewAxdex[float32](0)
+	})
+	assert.NoError(t, s.Insert(1, &Point[float32]{}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for _, p := range randomPoints(100) {
+				assert.NoError(t, s.Insert(1, p))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, p := range randomPoints(100) {
+				assert.NotEmpty(t, s.NearestN(1, p, 3, 0))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, s.NearestN(1, &Point[float32]{}, -1, 0), 401)
+}
@@ -0,0 +1,18 @@
+package microspace
+
+// NearestNInto is like NearestN, but fills the nearest neighbors into the
+// caller's buffer rather than allocating a new slice, and returns how many
+// it found. Up to len(out) neighbors are searched for. Reusing the buffer
+// between queries makes them allocation-free, except for indexes with a
+// metric, dual axes or a candidate cap, whose queries still allocate.
+func (a *Axdex[T]) NearestNInto(p *Point[T], max T, out []*Point[T]) int {
+	max = searchRadius(max)
+	if a.metric != nil || a.dual || a.maxCandidates > 0 {
+		return copy(out, a.NearestN(p, len(out), max))
+	}
+	if len(out) == 0 || len(a.points) == 0 {
+		return 0
+	}
+
+	return len(a.nearestInto(p, max, out))
+}
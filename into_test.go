@@ -0,0 +1,41 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNInto(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	for _, p := range randomPoints(500) {
+		idx.Insert(p)
+	}
+
+	out := make([]*Point[float32], 5)
+	for _, q := range randomPoints(20) {
+		count := idx.NearestNInto(q, 0.1, out)
+		assert.Equal(t, idx.NearestN(q, 5, 0.1), out[:count])
+	}
+
+	assert.Equal(t, 0, idx.NearestNInto(&Point[float32]{}, 0, nil))
+	assert.Equal(t, 0, NewAxdex[float32](0).NearestNInto(&Point[float32]{}, 0, out))
+
+	idx.SetMaxCandidates(1000)
+	q := &Point[float32]{0.5, 0.5}
+	count := idx.NearestNInto(q, 0, out)
+	assert.Equal(t, idx.NearestN(q, 5, 0), out[:count])
+}
+
+func TestNearestNIntoAllocations(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	for _, p := range randomPoints(1000) {
+		idx.Insert(p)
+	}
+	idx.EnableStats()
+
+	out := make([]*Point[float32], 10)
+	q := &Point[float32]{0.5, 0.5}
+	allocs := testing.AllocsPerRun(100, func() { idx.NearestNInto(q, 0, out) })
+	assert.Equal(t, float64(0), allocs)
+}
@@ -0,0 +1,104 @@
+package microspace
+
+// ItemIndex indexes arbitrary items, such as game entities, by a position
+// read from each item, so queries return the items themselves rather
+// than points. It's backed by an Axdex, and keeps the mapping between
+// items and their points.
+type ItemIndex[E comparable, T Float] struct {
+	index    *Axdex[T]
+	position func(E) (x, y T)
+	points   map[E]*Point[T]
+	items    map[*Point[T]]E
+}
+
+// NewItemIndex returns a new item index with room for `capacity` items,
+// which reads items' positions with the provided function.
+func NewItemIndex[E comparable, T Float](capacity uint, position func(E) (x, y T)) *ItemIndex[E, T] {
+	return &ItemIndex[E, T]{
+		index:    NewAxdex[T](capacity),
+		position: position,
+		points:   make(map[E]*Point[T], capacity),
+		items:    make(map[*Point[T]]E, capacity),
+	}
+}
+
+// Insert adds the item to the index at its current position. Inserting an
+// item which is already in the index updates its position instead.
+func (x *ItemIndex[E, T]) Insert(item E) {
+	if x.Update(item) {
+		return
+	}
+
+	px, py := x.position(item)
+	p := &Point[T]{X: px, Y: py}
+	x.index.Insert(p)
+	x.points[item] = p
+	x.items[p] = item
+}
+
+// Update moves the item in the index to its current position, returning
+// false if it isn't in the index.
+func (x *ItemIndex[E, T]) Update(item E) bool {
+	p, ok := x.points[item]
+	if !ok {
+		return false
+	}
+
+	px, py := x.position(item)
+	return x.index.Update(p, px, py)
+}
+
+// Remove removes the item from the index, returning false if it wasn't in
+// the index.
+func (x *ItemIndex[E, T]) Remove(item E) bool {
+	p, ok := x.points[item]
+	if !ok {
+		return false
+	}
+
+	x.index.Remove(p)
+	delete(x.points, item)
+	delete(x.items, p)
+	return true
+}
+
+// Len returns the number of items in the index.
+func (x *ItemIndex[E, T]) Len() int {
+	return len(x.points)
+}
+
+// Items returns all items in the index.
+func (x *ItemIndex[E, T]) Items() []E {
+	return x.toItems(x.index.Points())
+}
+
+// NearestN returns up to the `n` items nearest to p, with the same
+// semantics as Index.NearestN.
+func (x *ItemIndex[E, T]) NearestN(p Point[T], n int, max T) []E {
+	return x.toItems(x.index.NearestN(&p, n, max))
+}
+
+// NearestNTo is like NearestN, but searches around an item in the index,
+// which is included in the results.
+func (x *ItemIndex[E, T]) NearestNTo(item E, n int, max T) []E {
+	p, ok := x.points[item]
+	if !ok {
+		return nil
+	}
+
+	return x.toItems(x.index.NearestN(p, n, max))
+}
+
+// toItems returns the items at the points.
+func (x *ItemIndex[E, T]) toItems(points []*Point[T]) []E {
+	if len(points) == 0 {
+		return nil
+	}
+
+	items := make([]E, len(points))
+	for i, p := range points {
+		items[i] = x.items[p]
+	}
+
+	return items
+}
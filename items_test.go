@@ -0,0 +1,36 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEntity struct {
+	name string
+	x, y float64
+}
+
+func TestItemIndex(t *testing.T) {
+	a, b, c := &testEntity{"a", 0, 0}, &testEntity{"b", 1, 0}, &testEntity{"c", 5, 5}
+	idx := NewItemIndex(3, func(e *testEntity) (float64, float64) { return e.x, e.y })
+	idx.Insert(a)
+	idx.Insert(b)
+	idx.Insert(c)
+	assert.Equal(t, 3, idx.Len())
+	assert.ElementsMatch(t, []*testEntity{a, b, c}, idx.Items())
+
+	assert.Equal(t, []*testEntity{a, b}, idx.NearestN(Point[float64]{0.2, 0}, 2, 10))
+	assert.Equal(t, []*testEntity{c, b}, idx.NearestNTo(c, 2, 10))
+
+	c.x, c.y = 0.1, 0
+	assert.True(t, idx.Update(c))
+	assert.Equal(t, []*testEntity{a, c}, idx.NearestN(Point[float64]{-1, 0}, 2, 10))
+
+	assert.True(t, idx.Remove(a))
+	assert.False(t, idx.Remove(a))
+	assert.False(t, idx.Update(a))
+	assert.Empty(t, idx.NearestNTo(a, 2, 10))
+	assert.Equal(t, []*testEntity{c, b}, idx.NearestN(Point[float64]{-1, 0}, 2, 10))
+	assert.Equal(t, 2, idx.Len())
+}
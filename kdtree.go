@@ -0,0 +1,111 @@
+package microspace
+
+import "sort"
+
+// KDTree is a static k-d tree index. Unlike Axdex, which only sorts
+// points along one axis, it splits them alternately along X and Y, so it
+// keeps performing well when points are spread evenly across both axes.
+// It's built once from a slice of points and can't be modified after, so
+// it stays as balanced as when it was built: to change its points, build
+// a new tree.
+type KDTree[T Float] struct {
+	points []*Point[T]
+	// nodes holds the tree as nested slices: the median of each slice is
+	// the node, with its children in the slices either side of it. Nodes
+	// at even depths split along X, and at odd depths along Y.
+	nodes []*Point[T]
+}
+
+// NewKDTree returns a k-d tree built from the points.
+func NewKDTree[T Float](points []*Point[T]) *KDTree[T] {
+	t := &KDTree[T]{
+		points: points,
+		nodes:  append([]*Point[T](nil), points...),
+	}
+	buildKD(t.nodes, 0)
+
+	return t
+}
+
+var _ Index[float32] = new(KDTree[float32])
+
+// kdValue returns the point's coordinate along the axis split at depth.
+func kdValue[T Float](p *Point[T], depth int) T {
+	if depth%2 == 0 {
+		return p.X
+	}
+
+	return p.Y
+}
+
+// buildKD arranges the nodes into a subtree rooted at depth.
+func buildKD[T Float](nodes []*Point[T], depth int) {
+	if len(nodes) <= 1 {
+		return
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return kdValue(nodes[i], depth) < kdValue(nodes[j], depth)
+	})
+
+	mid := len(nodes) / 2
+	buildKD(nodes[:mid], depth+1)
+	buildKD(nodes[mid+1:], depth+1)
+}
+
+// Points implements Index.Points
+func (t *KDTree[T]) Points() []*Point[T] {
+	return t.points
+}
+
+// NearestN implements Index.NearestN. The point doesn't need to be in the
+// index.
+func (t *KDTree[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return t.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (t *KDTree[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return t.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` nearest neighbors of p
+// within `max`.
+func (t *KDTree[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(t.points) {
+		n = len(t.points)
+	}
+	if n <= 0 {
+		return &neighborList[T]{}
+	}
+
+	results := newNeighborList[T](n)
+	t.search(t.nodes, 0, p, max, results)
+	return results
+}
+
+// search adds the points in the subtree within `max` of p to the results,
+// skipping any side of a split that's further away than the worst result.
+func (t *KDTree[T]) search(nodes []*Point[T], depth int, p *Point[T], max T, results *neighborList[T]) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	if d := node.DistanceToSqr(p); d <= max*max {
+		results.Insert(node, d)
+	}
+
+	delta := kdValue(p, depth) - kdValue(node, depth)
+	near, far := nodes[:mid], nodes[mid+1:]
+	if delta > 0 {
+		near, far = far, near
+	}
+
+	t.search(near, depth+1, p, max, results)
+	if delta*delta <= max*max && (!results.Full() || delta*delta < results.Worst()) {
+		t.search(far, depth+1, p, max, results)
+	}
+}
@@ -0,0 +1,55 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertExact checks the index's results against a brute force search over
+// all of its points, comparing distances since ties may come in any order.
+func assertExact(t *testing.T, index Index[float32], queries []*Point[float32], n int, max float32) {
+	for _, p := range queries {
+		expected := []float32{}
+		for _, other := range index.Points() {
+			if d := other.DistanceToSqr(p); d <= max*max {
+				expected = append(expected, d)
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		if n >= 0 && len(expected) > n {
+			expected = expected[:n]
+		}
+
+		actual := []float32{}
+		for _, q := range index.NearestN(p, n, max) {
+			actual = append(actual, q.DistanceToSqr(p))
+		}
+		assert.Equal(t, expected, actual)
+	}
+}
+
+// randomPoints returns n points spread evenly over the unit square.
+func randomPoints(n int) []*Point[float32] {
+	points := make([]*Point[float32], n)
+	for i := range points {
+		points[i] = &Point[float32]{rand.Float32(), rand.Float32()}
+	}
+
+	return points
+}
+
+func TestKDTreeNearest(t *testing.T) {
+	points := randomPoints(1000)
+	tree := NewKDTree(points)
+	assert.Equal(t, points, tree.Points())
+
+	assertExact(t, tree, points[:50], 5, 0.1)
+	assertExact(t, tree, randomPoints(50), 8, 1)
+	assertExact(t, tree, randomPoints(5), -1, 0.05)
+
+	empty := NewKDTree[float32](nil)
+	assert.Empty(t, empty.NearestN(&Point[float32]{0, 0}, 3, 1))
+}
@@ -0,0 +1,33 @@
+package microspace
+
+// kernelBatch is the number of points whose distances are computed at once
+// by queries over contiguous coordinates.
+const kernelBatch = 8
+
+// sqrDistances writes the squared distances from (px, py) to the points
+// with coordinates (xs[i], ys[i]) into out. The loop is unrolled four
+// points at a time, so bounds are checked once per four points and the
+// four computations don't depend on each other. It's plain scalar Go, not
+// vector instructions. Only Frozen and FlatIndex use it, through
+// nearestSorted, as they store their coordinates contiguously; Axdex
+// reaches each coordinate through a pointer to the point, so it computes
+// distances one candidate at a time.
+func sqrDistances[T Float](px, py T, xs, ys, out []T) {
+	n := len(out)
+	xs, ys = xs[:n], ys[:n]
+
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		x, y, o := xs[i:i+4:i+4], ys[i:i+4:i+4], out[i:i+4:i+4]
+		dx0, dx1, dx2, dx3 := px-x[0], px-x[1], px-x[2], px-x[3]
+		dy0, dy1, dy2, dy3 := py-y[0], py-y[1], py-y[2], py-y[3]
+		o[0] = dx0*dx0 + dy0*dy0
+		o[1] = dx1*dx1 + dy1*dy1
+		o[2] = dx2*dx2 + dy2*dy2
+		o[3] = dx3*dx3 + dy3*dy3
+	}
+	for ; i < n; i++ {
+		dx, dy := px-xs[i], py-ys[i]
+		out[i] = dx*dx + dy*dy
+	}
+}
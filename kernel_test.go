@@ -0,0 +1,24 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqrDistances(t *testing.T) {
+	for n := 0; n <= 9; n++ {
+		points := randomPoints(n)
+		xs, ys := make([]float32, n), make([]float32, n)
+		for i, p := range points {
+			xs[i], ys[i] = p.X, p.Y
+		}
+
+		center := &Point[float32]{0.5, 0.25}
+		out := make([]float32, n)
+		sqrDistances(center.X, center.Y, xs, ys, out)
+		for i, p := range points {
+			assert.Equal(t, center.DistanceToSqr(p), out[i])
+		}
+	}
+}
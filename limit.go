@@ -0,0 +1,38 @@
+package microspace
+
+import "time"
+
+// SetMaxCandidates caps the number of candidate points NearestN may scan,
+// bounding its worst case on adversarial distributions, such as thousands
+// of points sharing nearly the same coordinate along the axis. Once the
+// cap is hit NearestN returns the best results found so far. A limit of
+// zero removes the cap.
+func (a *Axdex[T]) SetMaxCandidates(limit int) {
+	a.maxCandidates = limit
+}
+
+// NearestNCapped is like NearestN with the cap set by SetMaxCandidates,
+// but also reports whether the cap was hit, meaning closer neighbors may
+// have been missed. Without a cap it's never truncated. The point doesn't
+// need to be in the index.
+func (a *Axdex[T]) NearestNCapped(p *Point[T], n int, max T) (results []*Point[T], truncated bool) {
+	list, truncated := a.nearestCapped(p, n, max)
+	return list.Sorted(), truncated
+}
+
+// nearestCapped is NearestNCapped, returning the list of results.
+func (a *Axdex[T]) nearestCapped(p *Point[T], n int, max T) (results *neighborList[T], truncated bool) {
+	limit := -1
+	if a.maxCandidates > 0 {
+		limit = a.maxCandidates
+	}
+
+	var scanned int
+	if a.stats != nil {
+		start := time.Now()
+		defer func() { a.stats.record(time.Since(start), scanned) }()
+	}
+
+	results, scanned, truncated = a.nearestScan(p, n, max, limit)
+	return results, truncated
+}
@@ -0,0 +1,31 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxCandidates(t *testing.T) {
+	idx := NewAxdex[float32](1000)
+	for i := 0; i < 1000; i++ {
+		idx.Insert(&Point[float32]{0, float32(i)})
+	}
+	p := &Point[float32]{0, 500.2}
+	expected := []*Point[float32]{idx.Points()[500], idx.Points()[501]}
+
+	results, truncated := idx.NearestNCapped(p, 2, 5)
+	assert.False(t, truncated)
+	assert.Equal(t, expected, results)
+
+	idx.SetMaxCandidates(10)
+	results, truncated = idx.NearestNCapped(p, 2, 5)
+	assert.True(t, truncated)
+	assert.Empty(t, results) // every candidate scanned is further than max
+	assert.Equal(t, results, idx.NearestN(p, 2, 5))
+
+	idx.SetMaxCandidates(0)
+	results, truncated = idx.NearestNCapped(p, 2, 5)
+	assert.False(t, truncated)
+	assert.Equal(t, expected, results)
+}
@@ -0,0 +1,54 @@
+package microspace
+
+import "math"
+
+// LOF returns the local outlier factor of every point in the index, from
+// the densities of their `k` nearest neighbors. A factor near 1 means a
+// point is about as dense as its neighbors, and factors well above 1 mark
+// outliers in sparser space than their neighbors. Neighborhoods are the
+// exact `k` nearest neighbors from KNNGraph, so points tied with the k-th
+// neighbor aren't included. Points sharing their position with all their
+// neighbors are infinitely dense, and have a factor of 1 among others as
+// dense.
+func (a *Axdex[T]) LOF(k int) map[*Point[T]]T {
+	graph := a.KNNGraph(k)
+	slot := make(map[*Point[T]]int, len(a.points))
+	kDist := make([]float64, len(a.points))
+	for i, p := range a.points {
+		slot[p] = i
+		if n := len(graph[i]); n > 0 {
+			kDist[i] = math.Sqrt(float64(graph[i][n-1].DistSqr))
+		}
+	}
+
+	// The reach distance to a neighbor is no less than the neighbor's own
+	// k-distance, which smooths out fluctuations among close points.
+	density := make([]float64, len(a.points))
+	for i := range a.points {
+		sum := 0.0
+		for _, n := range graph[i] {
+			sum += max(kDist[slot[n.Point]], math.Sqrt(float64(n.DistSqr)))
+		}
+		density[i] = float64(len(graph[i])) / sum
+	}
+
+	factors := make(map[*Point[T]]T, len(a.points))
+	for i, p := range a.points {
+		if len(graph[i]) == 0 {
+			factors[p] = 1
+			continue
+		}
+
+		sum := 0.0
+		for _, n := range graph[i] {
+			if ratio := density[slot[n.Point]] / density[i]; !math.IsNaN(ratio) {
+				sum += ratio
+			} else {
+				sum++
+			}
+		}
+		factors[p] = T(sum / float64(len(graph[i])))
+	}
+
+	return factors
+}
@@ -0,0 +1,44 @@
+package microspace
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLOF(t *testing.T) {
+	idx := NewAxdex[float64](0)
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			idx.Insert(&Point[float64]{float64(x), float64(y)})
+		}
+	}
+	outlier := &Point[float64]{20, 20}
+	idx.Insert(outlier)
+
+	factors := idx.LOF(4)
+	assert.Len(t, factors, 101)
+	assert.Greater(t, factors[outlier], 5.0)
+	assert.False(t, math.IsNaN(factors[outlier]))
+	for p, f := range factors {
+		if p != outlier && p.X > 0 && p.X < 9 && p.Y > 0 && p.Y < 9 {
+			assert.InDelta(t, 1, f, 0.2)
+		}
+	}
+
+	// Duplicates are infinitely dense, so they're never outliers.
+	same := NewAxdex[float32](0)
+	for i := 0; i < 5; i++ {
+		same.Insert(&Point[float32]{1, 1})
+	}
+	for _, f := range same.LOF(2) {
+		assert.Equal(t, float32(1), f)
+	}
+
+	single := NewAxdex[float32](0)
+	single.Insert(&Point[float32]{})
+	for _, f := range single.LOF(3) {
+		assert.Equal(t, float32(1), f)
+	}
+}
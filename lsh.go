@@ -0,0 +1,124 @@
+package microspace
+
+import (
+	"math"
+	"math/rand"
+)
+
+// LSH is an approximate nearest neighbor index using locality-sensitive
+// hashing. Each of its tables projects points onto a few random lines,
+// cuts those lines into buckets of a fixed width and hashes the bucket
+// numbers together, so that nearby points usually share a bucket. Queries
+// only look at the points sharing a bucket with the query point.
+//
+// Recall is tuned with the number of tables, where more tables find more
+// of the true neighbors, and the number of projections per table, where
+// more projections make buckets smaller and queries faster. The bucket
+// width should be on the order of the distances being queried.
+type LSH[T Float] struct {
+	tables []*lshTable[T]
+	points []*Point[T]
+}
+
+// lshTable is a single hash table with its own set of random projections.
+type lshTable[T Float] struct {
+	dirs    []Point[T]
+	offsets []T
+	width   T
+	buckets map[uint64][]*Point[T]
+}
+
+// NewLSH returns a new LSH index with `tables` hash tables, each hashing
+// `projections` random projections cut into buckets `width` wide. The
+// projections are drawn from a source seeded with `seed`.
+func NewLSH[T Float](tables, projections int, width T, seed int64) *LSH[T] {
+	r := rand.New(rand.NewSource(seed))
+	l := &LSH[T]{tables: make([]*lshTable[T], tables)}
+	for i := range l.tables {
+		t := &lshTable[T]{
+			dirs:    make([]Point[T], projections),
+			offsets: make([]T, projections),
+			width:   width,
+			buckets: map[uint64][]*Point[T]{},
+		}
+		for k := range t.dirs {
+			angle := r.Float64() * 2 * math.Pi
+			t.dirs[k] = Point[T]{X: T(math.Cos(angle)), Y: T(math.Sin(angle))}
+			t.offsets[k] = T(r.Float64()) * width
+		}
+
+		l.tables[i] = t
+	}
+
+	return l
+}
+
+var _ Index[float32] = new(LSH[float32])
+
+// Insert adds a point to the index.
+func (l *LSH[T]) Insert(p *Point[T]) {
+	l.points = append(l.points, p)
+	for _, t := range l.tables {
+		key := t.hash(p)
+		t.buckets[key] = append(t.buckets[key], p)
+	}
+}
+
+// Points implements Index.Points
+func (l *LSH[T]) Points() []*Point[T] {
+	return l.points
+}
+
+// NearestN implements Index.NearestN. The results are approximate: they
+// never include points further than `max` away, but may miss some of the
+// true nearest neighbors. The point doesn't need to be in the index.
+func (l *LSH[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return l.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (l *LSH[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return l.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` nearest neighbors of p
+// within `max`.
+func (l *LSH[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(l.points) {
+		n = len(l.points)
+	}
+	if n <= 0 {
+		return &neighborList[T]{}
+	}
+
+	results := newNeighborList[T](n)
+	seen := map[*Point[T]]bool{}
+	for _, t := range l.tables {
+		for _, q := range t.buckets[t.hash(p)] {
+			if seen[q] {
+				continue
+			}
+			seen[q] = true
+
+			if d := q.DistanceToSqr(p); d <= max*max {
+				results.Insert(q, d)
+			}
+		}
+	}
+
+	return results
+}
+
+// hash returns the bucket key of the point in the table.
+func (t *lshTable[T]) hash(p *Point[T]) uint64 {
+	// FNV-1a over the bucket numbers along each projection.
+	h := uint64(14695981039346656037)
+	for k, dir := range t.dirs {
+		bucket := math.Floor(float64((p.X*dir.X + p.Y*dir.Y + t.offsets[k]) / t.width))
+		h ^= uint64(int64(bucket))
+		h *= 1099511628211
+	}
+
+	return h
+}
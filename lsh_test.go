@@ -0,0 +1,44 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLSHNearest(t *testing.T) {
+	l := NewLSH[float32](8, 2, 0.2, 1)
+	exact := NewAxdex[float32](500)
+
+	points := []*Point[float32]{}
+	for i := 0; i < 500; i++ {
+		p := &Point[float32]{rand.Float32(), rand.Float32()}
+		points = append(points, p)
+		l.Insert(p)
+		exact.Insert(p)
+	}
+	assert.Len(t, l.Points(), 500)
+
+	found, total := 0, 0
+	for _, p := range points[:50] {
+		approx := l.NearestN(p, 3, 0.1)
+		assert.Equal(t, p, approx[0])
+		for _, q := range approx {
+			assert.True(t, q.DistanceToSqr(p) <= 0.1*0.1)
+		}
+
+		truth := map[*Point[float32]]bool{}
+		for _, q := range exact.NearestNAt(0, p, 3, 0.1) {
+			truth[q] = true
+		}
+		for _, q := range approx {
+			if truth[q] {
+				found++
+			}
+		}
+		total += len(truth)
+	}
+
+	assert.True(t, float64(found)/float64(total) > 0.8, "recall %d/%d", found, total)
+}
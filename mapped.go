@@ -0,0 +1,133 @@
+package microspace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// Mapped is a read-only index over a file written by Axdex.WriteTo, which
+// is memory-mapped rather than loaded, so opening it is instant and its
+// points are paged in from the file by the operating system as queries
+// touch them instead of being held on the heap. Like Frozen, points are
+// referred to by their position in the index. On platforms without mmap
+// the file is read into memory instead.
+type Mapped[T Float] struct {
+	along Axis
+	count int
+	// data holds the points, starting after the header.
+	data  []byte
+	unmap func() error
+}
+
+// OpenMapped memory-maps the index file at path, which must have been
+// written by Axdex.WriteTo. The index must be closed once it's no longer
+// used, after which it mustn't be queried.
+func OpenMapped[T Float](path string) (*Mapped[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < int64(persistHeaderSize) {
+		return nil, ErrBadFormat
+	}
+
+	data, unmap, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+
+	along, count, err := parsePersistHeader(data[:persistHeaderSize])
+	if err == nil && uint64(len(data)-persistHeaderSize) != count*externalPointSize {
+		err = fmt.Errorf("%w: expected %d points in %d bytes", ErrBadFormat, count, len(data))
+	}
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+
+	return &Mapped[T]{
+		along: along,
+		count: int(count),
+		data:  data[persistHeaderSize:],
+		unmap: unmap,
+	}, nil
+}
+
+// Close unmaps the file.
+func (m *Mapped[T]) Close() error {
+	m.data = nil
+	return m.unmap()
+}
+
+// Len returns the number of points in the index.
+func (m *Mapped[T]) Len() int {
+	return m.count
+}
+
+// At returns the i-th point in the index.
+func (m *Mapped[T]) At(i int) Point[T] {
+	return decodePoint[T](m.data[i*externalPointSize:])
+}
+
+// value returns the coordinate of the i-th point along the sorted axis.
+func (m *Mapped[T]) value(i int) T {
+	offset := i * externalPointSize
+	if m.along == AxisY {
+		offset += 8
+	}
+
+	return T(math.Float64frombits(binary.LittleEndian.Uint64(m.data[offset:])))
+}
+
+// NearestN returns the positions of up the `n` nearest points to p, with
+// a `max` search distance, ordered by increasing distance. `n` may be set
+// to -1 to search for all points in the distance. The point doesn't need
+// to be in the index.
+func (m *Mapped[T]) NearestN(p Point[T], n int, max T) []int {
+	max = searchRadius(max)
+	if n == -1 || n > m.count {
+		n = m.count
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	pv := p.X
+	if m.along == AxisY {
+		pv = p.Y
+	}
+
+	results := newRankedList[int, T](n)
+	c := m.cursor(pv)
+	for {
+		i, gap, ok := c.Next()
+		if !ok || gap > max || (results.Full() && gap*gap >= results.Worst()) {
+			break
+		}
+
+		q := m.At(i)
+		if d := q.DistanceToSqr(&p); d <= max*max {
+			results.Insert(i, d)
+		}
+	}
+
+	return results.Sorted()
+}
+
+// cursor returns a cursor along the sorted axis expanding outwards from
+// the value.
+func (m *Mapped[T]) cursor(value T) axisCursor[T] {
+	right := sort.Search(m.count, func(i int) bool { return m.value(i) >= value })
+	c := axisCursor[T]{value: m.value, from: value, to: value, left: right - 1, right: right, hi: m.count}
+	c.measure()
+	return c
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package microspace
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first `size` bytes of the file into memory, on
+// platforms where it can't be memory-mapped.
+func mapFile(f *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,59 @@
+package microspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenMapped(t *testing.T) {
+	for _, along := range []Axis{AxisX, AxisY} {
+		idx := NewAxdexOnAxis[float32](0, along)
+		for _, p := range randomPoints(300) {
+			idx.Insert(p)
+		}
+
+		path := filepath.Join(t.TempDir(), "index.bin")
+		f, err := os.Create(path)
+		assert.NoError(t, err)
+		_, err = idx.WriteTo(f)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+
+		m, err := OpenMapped[float32](path)
+		assert.NoError(t, err)
+		assert.Equal(t, 300, m.Len())
+
+		for _, q := range randomPoints(10) {
+			expected, actual := []Point[float32]{}, []Point[float32]{}
+			for _, p := range idx.NearestN(q, 5, 0.3) {
+				expected = append(expected, *p)
+			}
+			for _, i := range m.NearestN(*q, 5, 0.3) {
+				actual = append(actual, m.At(i))
+			}
+			assert.Equal(t, expected, actual)
+		}
+		assert.Len(t, m.NearestN(Point[float32]{0.5, 0.5}, -1, 2), 300)
+
+		assert.NoError(t, m.Close())
+	}
+}
+
+func TestOpenMappedErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := OpenMapped[float32](filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+
+	short := filepath.Join(dir, "short")
+	assert.NoError(t, os.WriteFile(short, []byte("MSAX"), 0644))
+	_, err = OpenMapped[float32](short)
+	assert.ErrorIs(t, err, ErrBadFormat)
+
+	truncated := filepath.Join(dir, "truncated")
+	assert.NoError(t, os.WriteFile(truncated, []byte("MSAX\x01\x00\x02\x00\x00\x00\x00\x00\x00\x00"), 0644))
+	_, err = OpenMapped[float32](truncated)
+	assert.ErrorIs(t, err, ErrBadFormat)
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package microspace
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps the first `size` bytes of the file read-only,
+// returning the mapped bytes and a function to unmap them.
+func mapFile(f *os.File, size int) ([]byte, func() error, error) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,99 @@
+package microspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the point
+// as two little endian float64 coordinates.
+func (p *Point[T]) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, externalPointSize)
+	encodePoint(buf, *p)
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *Point[T]) UnmarshalBinary(data []byte) error {
+	if len(data) != externalPointSize {
+		return fmt.Errorf("%w: point of %d bytes", ErrBadFormat, len(data))
+	}
+
+	*p = decodePoint[T](data)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the index
+// in the format written by WriteTo, which also lets it be encoded with
+// encoding/gob.
+func (a *Axdex[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := a.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the
+// points in the index like ReadFrom.
+func (a *Axdex[T]) UnmarshalBinary(data []byte) error {
+	_, err := a.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// axdexJSON is the JSON encoding of an Axdex.
+type axdexJSON struct {
+	Axis   string       `json:"axis"`
+	Points [][2]float64 `json:"points"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the index as an object
+// with the axis it's sorted along, "x" or "y", and its points as [x, y]
+// pairs in the order of Points.
+func (a *Axdex[T]) MarshalJSON() ([]byte, error) {
+	enc := axdexJSON{Axis: "x", Points: make([][2]float64, len(a.points))}
+	switch a.along {
+	case AxisY:
+		enc.Axis = "y"
+	case AxisCustom:
+		return nil, errors.New("microspace: cannot encode an index on a custom axis")
+	}
+	for i, p := range a.points {
+		enc.Points[i] = [2]float64{float64(p.X), float64(p.Y)}
+	}
+
+	return json.Marshal(enc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the points in the
+// index like ReadFrom.
+func (a *Axdex[T]) UnmarshalJSON(data []byte) error {
+	var dec axdexJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	var along Axis
+	switch dec.Axis {
+	case "x", "":
+		along = AxisX
+	case "y":
+		along = AxisY
+	default:
+		return fmt.Errorf("%w: unknown axis %q", ErrBadFormat, dec.Axis)
+	}
+
+	loaded := NewAxdexOnAxis[T](uint(len(dec.Points)), along)
+	points := make([]Point[T], len(dec.Points))
+	for i, xy := range dec.Points {
+		points[i] = Point[T]{X: T(xy[0]), Y: T(xy[1])}
+		loaded.Insert(&points[i])
+	}
+	loaded.axis.runSort()
+
+	a.replace(loaded)
+	return nil
+}
@@ -0,0 +1,52 @@
+package microspace
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalPoint(t *testing.T) {
+	p := &Point[float32]{1.5, -2}
+	data, err := p.MarshalBinary()
+	assert.NoError(t, err)
+
+	var decoded Point[float32]
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, *p, decoded)
+	assert.ErrorIs(t, decoded.UnmarshalBinary(data[:3]), ErrBadFormat)
+}
+
+func TestMarshalAxdex(t *testing.T) {
+	idx := NewAxdexOnAxis[float64](0, AxisY)
+	for _, p := range []*Point[float64]{{1, 5}, {2, 2}, {3, 9}} {
+		idx.Insert(p)
+	}
+
+	data, err := json.Marshal(idx)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"axis":"y","points":[[1,5],[2,2],[3,9]]}`, string(data))
+
+	var decoded Axdex[float64]
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, AxisY, decoded.along)
+	assert.Equal(t, []*Point[float64]{{2, 2}, {1, 5}}, decoded.NearestN(&Point[float64]{2, 3}, 2, 0))
+
+	reused := NewAxdex[float64](0)
+	reused.EnableDualAxis()
+	reused.Insert(&Point[float64]{100, 100})
+	assert.NoError(t, json.Unmarshal(data, reused))
+	assert.Len(t, reused.Points(), 3)
+	assert.Equal(t, []*Point[float64]{{3, 9}}, reused.NearestN(&Point[float64]{3, 8}, 1, 0))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"axis": "z"}`), &decoded))
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(idx))
+	var fromGob Axdex[float64]
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&fromGob))
+	assert.Equal(t, []*Point[float64]{{2, 2}, {1, 5}, {3, 9}}, fromGob.Points())
+}
@@ -0,0 +1,109 @@
+package microspace
+
+import "math"
+
+// Metric measures distances between points for queries. The sorted axis
+// only prunes correctly if no two points are closer than their gap along
+// the axis allows, which Bound reports.
+type Metric[T Float] interface {
+	// Distance returns the distance between the points.
+	Distance(a, b *Point[T]) T
+	// Bound returns the smallest distance two points can be apart if
+	// their coordinates along the index's axis differ by `gap`.
+	Bound(gap T) T
+}
+
+// Euclidean measures straight-line distance. It's what queries use when
+// no metric is set.
+type Euclidean[T Float] struct{}
+
+// Distance implements Metric.Distance
+func (Euclidean[T]) Distance(a, b *Point[T]) T {
+	return T(math.Sqrt(float64(a.DistanceToSqr(b))))
+}
+
+// Bound implements Metric.Bound
+func (Euclidean[T]) Bound(gap T) T {
+	return gap
+}
+
+// Manhattan measures distance as the sum of the differences along each
+// axis, the distance moved on a grid with four-way movement.
+type Manhattan[T Float] struct{}
+
+// Distance implements Metric.Distance
+func (Manhattan[T]) Distance(a, b *Point[T]) T {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+// Bound implements Metric.Bound
+func (Manhattan[T]) Bound(gap T) T {
+	return gap
+}
+
+// Chebyshev measures distance as the largest difference along either
+// axis, the distance moved on a grid with eight-way movement.
+type Chebyshev[T Float] struct{}
+
+// Distance implements Metric.Distance
+func (Chebyshev[T]) Distance(a, b *Point[T]) T {
+	return max(abs(a.X-b.X), abs(a.Y-b.Y))
+}
+
+// Bound implements Metric.Bound
+func (Chebyshev[T]) Bound(gap T) T {
+	return gap
+}
+
+// abs returns the absolute value of v.
+func abs[T Float](v T) T {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// NewAxdexWithMetric is like NewAxdex, but NearestN measures distances,
+// including the `max` distance, with the metric. Other queries keep using
+// Euclidean distance.
+func NewAxdexWithMetric[T Float](capacity uint, metric Metric[T]) *Axdex[T] {
+	a := NewAxdex[T](capacity)
+	a.metric = metric
+	return a
+}
+
+// nearestMetric is NearestN for indexes with a metric set, expanding
+// outwards along the axis until the metric's bound for the gap along the
+// axis rules out any closer point. The point doesn't need to be in the
+// index. The list holds the metric's distances.
+func (a *Axdex[T]) nearestMetric(p *Point[T], n int, max T) *neighborList[T] {
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return &neighborList[T]{}
+	}
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	c := a.axis.cursor(a.axis.ValueFor(p))
+	results := newNeighborList[T](n)
+	for {
+		i, gap, ok := c.Next()
+		if !ok {
+			break
+		}
+		if bound := a.metric.Bound(gap); bound > max || (results.Full() && bound >= results.Worst()) {
+			break
+		}
+
+		q := a.axis.data[i].p
+		if d := a.metric.Distance(q, p); d <= max {
+			results.Insert(q, d)
+		}
+	}
+
+	return results
+}
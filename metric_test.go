@@ -0,0 +1,34 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	a, b := &Point[float32]{0, 0}, &Point[float32]{3, 4}
+	assert.Equal(t, float32(5), Euclidean[float32]{}.Distance(a, b))
+	assert.Equal(t, float32(7), Manhattan[float32]{}.Distance(a, b))
+	assert.Equal(t, float32(4), Chebyshev[float32]{}.Distance(a, b))
+}
+
+func TestNearestNMetric(t *testing.T) {
+	diagonal := &Point[float32]{2, 2}
+	straight := &Point[float32]{0, 3}
+	far := &Point[float32]{10, 0}
+	points := []*Point[float32]{diagonal, straight, far}
+	origin := &Point[float32]{0, 0}
+
+	manhattan := NewAxdexWithMetric[float32](3, Manhattan[float32]{})
+	chebyshev := NewAxdexWithMetric[float32](3, Chebyshev[float32]{})
+	for _, p := range points {
+		manhattan.Insert(p)
+		chebyshev.Insert(p)
+	}
+
+	assert.Equal(t, []*Point[float32]{straight, diagonal}, manhattan.NearestN(origin, 2, 0))
+	assert.Equal(t, []*Point[float32]{diagonal, straight}, chebyshev.NearestN(origin, 2, 0))
+	assert.Equal(t, []*Point[float32]{straight}, manhattan.NearestN(origin, -1, 3.5))
+	assert.Equal(t, []*Point[float32]{diagonal, straight}, chebyshev.NearestN(origin, -1, 3))
+}
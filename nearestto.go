@@ -0,0 +1,79 @@
+package microspace
+
+// NearestToRect returns up to the `n` points nearest to the rectangle,
+// ranked by their distance to its closest edge. Points inside the
+// rectangle are at distance zero and come first. `n` may be set to -1 to
+// rank all points.
+func (a *Axdex[T]) NearestToRect(r Rect[T], n int) []*Point[T] {
+	return a.nearestTo(r, n, r.DistanceToSqr)
+}
+
+// NearestToSegment returns up to the `n` points nearest to the line
+// segment between `from` and `to`, ranked by their distance to its
+// closest point. `n` may be set to -1 to rank all points.
+func (a *Axdex[T]) NearestToSegment(from, to Point[T], n int) []*Point[T] {
+	bounds := Rect[T]{Min: from, Max: to}
+	if bounds.Min.X > bounds.Max.X {
+		bounds.Min.X, bounds.Max.X = bounds.Max.X, bounds.Min.X
+	}
+	if bounds.Min.Y > bounds.Max.Y {
+		bounds.Min.Y, bounds.Max.Y = bounds.Max.Y, bounds.Min.Y
+	}
+
+	dx, dy := to.X-from.X, to.Y-from.Y
+	length := dx*dx + dy*dy
+	return a.nearestTo(bounds, n, func(p *Point[T]) T {
+		// Project p onto the segment, clamping to its ends.
+		var t T
+		if length > 0 {
+			t = ((p.X-from.X)*dx + (p.Y-from.Y)*dy) / length
+			if t < 0 {
+				t = 0
+			} else if t > 1 {
+				t = 1
+			}
+		}
+
+		closest := Point[T]{X: from.X + t*dx, Y: from.Y + t*dy}
+		return p.DistanceToSqr(&closest)
+	})
+}
+
+// nearestTo returns up to the `n` points with the smallest squared
+// distance to a shape, as given by distSqr, where the shape lies within
+// the bounds. Every point within the bounds along the axis is a
+// candidate, after which the search expands outwards from either side of
+// them like NearestNAt does.
+func (a *Axdex[T]) nearestTo(bounds Rect[T], n int, distSqr func(*Point[T]) T) []*Point[T] {
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil
+	}
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	lo, hi := a.axis.rangeOf(bounds)
+	list := newNeighborList[T](n)
+	start := a.axis.Search(lo)
+	end := start
+	for ; end < len(a.axis.data) && a.axis.data[end].value <= hi; end++ {
+		q := a.axis.data[end].p
+		list.Insert(q, distSqr(q))
+	}
+
+	c := a.axis.cursorBetween(lo, hi, 0, start-1, end, len(a.axis.data))
+	for {
+		i, gap, ok := c.Next()
+		if !ok || (list.Full() && gap*gap >= list.Worst()) {
+			break
+		}
+
+		q := a.axis.data[i].p
+		list.Insert(q, distSqr(q))
+	}
+
+	return list.Sorted()
+}
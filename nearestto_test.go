@@ -0,0 +1,61 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestToRect(t *testing.T) {
+	inside := &Point[float32]{5, 5}
+	above := &Point[float32]{5, 7}
+	corner := &Point[float32]{8, 8}
+	far := &Point[float32]{-20, 0}
+
+	idx := NewAxdex[float32](4)
+	for _, p := range []*Point[float32]{far, corner, above, inside} {
+		idx.Insert(p)
+	}
+
+	r := Rect[float32]{Min: Point[float32]{4, 4}, Max: Point[float32]{6, 6}}
+	assert.Equal(t, []*Point[float32]{inside, above, corner}, idx.NearestToRect(r, 3))
+	assert.Equal(t, []*Point[float32]{inside, above, corner, far}, idx.NearestToRect(r, -1))
+}
+
+func TestNearestToSegment(t *testing.T) {
+	idx := generateIndex(500)
+	from, to := Point[float32]{0.9, 0.1}, Point[float32]{0.2, 0.6}
+	results := idx.NearestToSegment(from, to, 10)
+	assert.Len(t, results, 10)
+
+	// No point left out may be closer to the segment than the results.
+	found := map[*Point[float32]]bool{}
+	for _, p := range results {
+		found[p] = true
+	}
+	last := results[len(results)-1]
+	for _, p := range idx.Points() {
+		if !found[p] {
+			assert.True(t, segmentDistance(from, to, last) <= segmentDistance(from, to, p)+1e-6)
+		}
+	}
+
+	// A degenerate segment is just a point.
+	p := idx.Points()[0]
+	assert.Equal(t, p, idx.NearestToSegment(*p, *p, 1)[0])
+}
+
+// segmentDistance returns the distance from p to the segment by sampling
+// it finely.
+func segmentDistance(from, to Point[float32], p *Point[float32]) float32 {
+	best := float32(-1)
+	for i := 0; i <= 10000; i++ {
+		t := float32(i) / 10000
+		q := Point[float32]{from.X + t*(to.X-from.X), from.Y + t*(to.Y-from.Y)}
+		if d := q.DistanceToSqr(p); best < 0 || d < best {
+			best = d
+		}
+	}
+
+	return best
+}
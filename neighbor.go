@@ -0,0 +1,100 @@
+package microspace
+
+// Neighbor is a point found by a query, along with its squared distance
+// from the query point, or its distance under the Metric the query ranked
+// by.
+type Neighbor[T Float] struct {
+	Point   *Point[T]
+	DistSqr T
+}
+
+// NeighborIndex is an Index which can return the distances to the
+// neighbors it finds, which it has already computed.
+type NeighborIndex[T Float] interface {
+	Index[T]
+	// NearestNWithDistance is like NearestN, but returns each point
+	// along with its squared distance from p.
+	NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T]
+}
+
+var (
+	_ NeighborIndex[float32] = new(Axdex[float32])
+	_ NeighborIndex[float32] = new(KDTree[float32])
+	_ NeighborIndex[float32] = new(Quadtree[float32])
+	_ NeighborIndex[float32] = new(RTree[float32])
+	_ NeighborIndex[float32] = new(GridIndex[float32])
+	_ NeighborIndex[float32] = new(LSH[float32])
+	_ NeighborIndex[float32] = new(HNSW[float32])
+	_ NeighborIndex[float32] = new(DiskIndex[float32])
+)
+
+// NearestNWithDistance runs NearestN against any index, returning each
+// point along with its squared distance from p. Indexes implementing
+// NeighborIndex return the distances they computed, and the distances
+// are computed afresh for other indexes.
+func NearestNWithDistance[T Float](index Index[T], p *Point[T], n int, max T) []Neighbor[T] {
+	if ni, ok := index.(NeighborIndex[T]); ok {
+		return ni.NearestNWithDistance(p, n, max)
+	}
+
+	return withDistances(p, index.NearestN(p, n, max))
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance. With
+// a Metric set the distances are the metric's, as used to rank the points.
+func (a *Axdex[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	max = searchRadius(max)
+	if a.metric != nil {
+		return a.nearestMetric(p, n, max).Neighbors()
+	}
+	if a.dual {
+		return a.nearestDual(p, n, max).Neighbors()
+	}
+	if a.maxCandidates > 0 {
+		results, _ := a.nearestCapped(p, n, max)
+		return results.Neighbors()
+	}
+
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil
+	}
+
+	points, dists := make([]*Point[T], n), make([]T, n)
+	neighbors := make([]Neighbor[T], a.nearestWithin(p, max, points, dists))
+	for i := range neighbors {
+		neighbors[i] = Neighbor[T]{Point: points[i], DistSqr: dists[i]}
+	}
+
+	return neighbors
+}
+
+// withDistances pairs each of the points with its squared distance from p.
+func withDistances[T Float](p *Point[T], points []*Point[T]) []Neighbor[T] {
+	if len(points) == 0 {
+		return nil
+	}
+
+	neighbors := make([]Neighbor[T], len(points))
+	for i, q := range points {
+		neighbors[i] = Neighbor[T]{Point: q, DistSqr: q.DistanceToSqr(p)}
+	}
+
+	return neighbors
+}
+
+// Neighbors returns the points in the list along with their distances.
+func (l *neighborList[T]) Neighbors() []Neighbor[T] {
+	if l.size == 0 {
+		return nil
+	}
+
+	neighbors := make([]Neighbor[T], l.size)
+	for i, p := range l.Sorted() {
+		neighbors[i] = Neighbor[T]{Point: p, DistSqr: l.dists[i]}
+	}
+
+	return neighbors
+}
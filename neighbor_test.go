@@ -0,0 +1,67 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNWithDistance(t *testing.T) {
+	points := randomPoints(500)
+	axdex := NewAxdex[float32](500)
+	for _, p := range points {
+		axdex.Insert(p)
+	}
+	double := NewDoubleBuffered[float32](500)
+	for _, p := range points {
+		double.Insert(p)
+	}
+	double.Swap()
+
+	indexes := []Index[float32]{axdex, NewKDTree(points), NewRTreeSTR(points, 0), NewGridIndexFor(points), double}
+	for _, p := range points[:20] {
+		for _, idx := range indexes {
+			neighbors := NearestNWithDistance(idx, p, 4, 0.2)
+			expected := idx.NearestN(p, 4, 0.2)
+			assert.Len(t, neighbors, len(expected))
+			for i, nb := range neighbors {
+				assert.Equal(t, expected[i], nb.Point)
+				assert.Equal(t, nb.Point.DistanceToSqr(p), nb.DistSqr)
+			}
+		}
+	}
+
+	assert.Empty(t, NewKDTree[float32](nil).NearestNWithDistance(points[0], 3, 1))
+}
+
+func TestAxdexNearestNWithDistanceModes(t *testing.T) {
+	points := randomPoints(500)
+	manhattan := NewAxdexWithMetric[float32](500, Manhattan[float32]{})
+	dual := NewAxdex[float32](500)
+	capped := NewAxdex[float32](500)
+	for _, p := range points {
+		manhattan.Insert(p)
+		dual.Insert(p)
+		capped.Insert(p)
+	}
+	dual.EnableDualAxis()
+	capped.SetMaxCandidates(50)
+
+	for _, p := range points[:20] {
+		for _, idx := range []*Axdex[float32]{manhattan, dual, capped} {
+			neighbors := idx.NearestNWithDistance(p, 4, 0.2)
+			expected := idx.NearestN(p, 4, 0.2)
+			assert.Len(t, neighbors, len(expected))
+			for i, nb := range neighbors {
+				assert.Equal(t, expected[i], nb.Point)
+				if idx == manhattan {
+					assert.Equal(t, Manhattan[float32]{}.Distance(nb.Point, p), nb.DistSqr)
+				} else {
+					assert.Equal(t, nb.Point.DistanceToSqr(p), nb.DistSqr)
+				}
+			}
+		}
+	}
+
+	assert.Empty(t, NewAxdex[float32](0).NearestNWithDistance(points[0], 3, 1))
+}
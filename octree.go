@@ -0,0 +1,171 @@
+package microspace
+
+// Octree is the three-dimensional counterpart to Quadtree, recursively
+// splitting space into eight octants, splitting any octant holding more
+// than a leaf's capacity of points.
+type Octree[T Float] struct {
+	maxDepth     int
+	leafCapacity int
+
+	root   *octNode[T]
+	points []*Point3[T]
+}
+
+// octNode is an octant of the tree, which either holds points as a leaf
+// or has been split into eight children.
+type octNode[T Float] struct {
+	// center is where the octant splits, and half is half its size.
+	center, half Point3[T]
+	// extent bounds the points actually held under the node, which may
+	// stray outside the octant for points outside the tree's bounds.
+	extent Box[T]
+	count  int
+
+	points   []*Point3[T]
+	children *[8]*octNode[T]
+}
+
+// NewOctree returns a new octree covering the bounds. Leaves are split
+// once they hold more than `leafCapacity` points, unless they're already
+// `maxDepth` levels deep. Values below 1 are replaced by the Quadtree
+// defaults. Points outside the bounds may still be inserted, but are kept
+// in the octants on the edge of the bounds which makes queries on them
+// slower.
+func NewOctree[T Float](bounds Box[T], maxDepth, leafCapacity int) *Octree[T] {
+	if maxDepth < 1 {
+		maxDepth = DefaultQuadtreeMaxDepth
+	}
+	if leafCapacity < 1 {
+		leafCapacity = DefaultQuadtreeLeafCapacity
+	}
+
+	return &Octree[T]{
+		maxDepth:     maxDepth,
+		leafCapacity: leafCapacity,
+		root: &octNode[T]{
+			center: Point3[T]{
+				X: (bounds.Min.X + bounds.Max.X) / 2,
+				Y: (bounds.Min.Y + bounds.Max.Y) / 2,
+				Z: (bounds.Min.Z + bounds.Max.Z) / 2,
+			},
+			half: Point3[T]{
+				X: (bounds.Max.X - bounds.Min.X) / 2,
+				Y: (bounds.Max.Y - bounds.Min.Y) / 2,
+				Z: (bounds.Max.Z - bounds.Min.Z) / 2,
+			},
+		},
+	}
+}
+
+var _ Index3[float32] = new(Octree[float32])
+
+// Insert adds a point to the index.
+func (o *Octree[T]) Insert(p *Point3[T]) {
+	o.points = append(o.points, p)
+	o.root.insert(p, 0, o.maxDepth, o.leafCapacity)
+}
+
+// Points implements Index3.Points
+func (o *Octree[T]) Points() []*Point3[T] {
+	return o.points
+}
+
+// insert adds the point to the node at the depth, splitting it if needed.
+func (n *octNode[T]) insert(p *Point3[T], depth, maxDepth, leafCapacity int) {
+	if n.count == 0 {
+		n.extent = Box[T]{Min: *p, Max: *p}
+	} else {
+		n.extent.Min = Point3[T]{X: min(n.extent.Min.X, p.X), Y: min(n.extent.Min.Y, p.Y), Z: min(n.extent.Min.Z, p.Z)}
+		n.extent.Max = Point3[T]{X: max(n.extent.Max.X, p.X), Y: max(n.extent.Max.Y, p.Y), Z: max(n.extent.Max.Z, p.Z)}
+	}
+	n.count++
+
+	if n.children != nil {
+		n.children[n.octant(p)].insert(p, depth+1, maxDepth, leafCapacity)
+		return
+	}
+
+	n.points = append(n.points, p)
+	if len(n.points) <= leafCapacity || depth >= maxDepth {
+		return
+	}
+
+	n.children = &[8]*octNode[T]{}
+	quarter := Point3[T]{X: n.half.X / 2, Y: n.half.Y / 2, Z: n.half.Z / 2}
+	for i := range n.children {
+		center := Point3[T]{X: n.center.X - quarter.X, Y: n.center.Y - quarter.Y, Z: n.center.Z - quarter.Z}
+		if i&1 != 0 {
+			center.X += n.half.X
+		}
+		if i&2 != 0 {
+			center.Y += n.half.Y
+		}
+		if i&4 != 0 {
+			center.Z += n.half.Z
+		}
+		n.children[i] = &octNode[T]{center: center, half: quarter}
+	}
+
+	for _, pt := range n.points {
+		n.children[n.octant(pt)].insert(pt, depth+1, maxDepth, leafCapacity)
+	}
+	n.points = nil
+}
+
+// octant returns the index of the child the point belongs in.
+func (n *octNode[T]) octant(p *Point3[T]) int {
+	var i int
+	if p.X >= n.center.X {
+		i |= 1
+	}
+	if p.Y >= n.center.Y {
+		i |= 2
+	}
+	if p.Z >= n.center.Z {
+		i |= 4
+	}
+
+	return i
+}
+
+// NearestN implements Index3.NearestN. The point doesn't need to be in
+// the index.
+func (o *Octree[T]) NearestN(p *Point3[T], n int, max T) []*Point3[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(o.points) {
+		n = len(o.points)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	results := newRankedList[*Point3[T], T](n)
+	o.root.search(p, max, &results)
+	return results.Sorted()
+}
+
+// search adds the points under the node within `max` of p to the results,
+// visiting the child holding p first and skipping any that can't hold a
+// point closer than the worst result.
+func (n *octNode[T]) search(p *Point3[T], max T, results *rankedList[*Point3[T], T]) {
+	if n.count == 0 {
+		return
+	}
+	if d := n.extent.DistanceToSqr(p); d > max*max || (results.Full() && d >= results.Worst()) {
+		return
+	}
+
+	if n.children == nil {
+		for _, pt := range n.points {
+			if d := pt.DistanceToSqr(p); d <= max*max {
+				results.Insert(pt, d)
+			}
+		}
+		return
+	}
+
+	first := n.octant(p)
+	for i := range n.children {
+		n.children[first^i].search(p, max, results)
+	}
+}
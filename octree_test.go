@@ -0,0 +1,49 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOctreeNearest(t *testing.T) {
+	o := NewOctree[float64](Box[float64]{Max: Point3[float64]{1, 1, 1}}, 8, 4)
+	for i := 0; i < 2000; i++ {
+		o.Insert(&Point3[float64]{rand.Float64(), rand.Float64(), rand.Float64()})
+	}
+	o.Insert(&Point3[float64]{2, -1, 0.5})
+	assert.Len(t, o.Points(), 2001)
+
+	queries := append(o.Points()[:20:20], &Point3[float64]{0.5, 0.5, 0.5}, &Point3[float64]{2, -1, 0})
+	for _, p := range queries {
+		expected := []float64{}
+		for _, other := range o.Points() {
+			if d := other.DistanceToSqr(p); d <= 0.3*0.3 {
+				expected = append(expected, d)
+			}
+		}
+		sort.Float64s(expected)
+		if len(expected) > 6 {
+			expected = expected[:6]
+		}
+
+		actual := []float64{}
+		for _, q := range o.NearestN(p, 6, 0.3) {
+			actual = append(actual, q.DistanceToSqr(p))
+		}
+		assert.Equal(t, expected, actual)
+	}
+
+	assert.Empty(t, NewOctree[float64](Box[float64]{}, 0, 0).NearestN(&Point3[float64]{}, 3, 1))
+}
+
+func TestBox(t *testing.T) {
+	b := Box[float32]{Min: Point3[float32]{0, 0, 0}, Max: Point3[float32]{1, 1, 1}}
+	assert.True(t, b.Contains(&Point3[float32]{0.5, 1, 0}))
+	assert.False(t, b.Contains(&Point3[float32]{0.5, 1, 2}))
+	assert.Equal(t, float32(0), b.DistanceToSqr(&Point3[float32]{0.5, 0.5, 0.5}))
+	assert.Equal(t, float32(9), b.DistanceToSqr(&Point3[float32]{3, 2, -2}))
+	assert.Equal(t, "(3.0000, 2.0000, -2.0000)", (&Point3[float32]{3, 2, -2}).String())
+}
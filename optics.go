@@ -8,39 +8,39 @@ import (
 
 // opticPQ is a priority queue where points with a lower reach distance
 // are ordered first.
-type opticPQ struct {
-	points []*opticPoint
+type opticPQ[T Float] struct {
+	points []*opticPoint[T]
 }
 
 // Len implements sort.Interface.Len
-func (o *opticPQ) Len() int {
+func (o *opticPQ[T]) Len() int {
 	return len(o.points)
 }
 
 // Less implements sort.Interface.Less
-func (o *opticPQ) Less(a, b int) bool {
+func (o *opticPQ[T]) Less(a, b int) bool {
 	return o.points[a].reachDist < o.points[b].reachDist
 }
 
 // Swap implements sort.Interface.Swap
-func (o *opticPQ) Swap(a, b int) {
+func (o *opticPQ[T]) Swap(a, b int) {
 	o.points[a], o.points[b] = o.points[b], o.points[a]
 }
 
 // Push implements sort.Interface.Push
-func (o *opticPQ) Push(x interface{}) {
-	o.points = append(o.points, x.(*opticPoint))
+func (o *opticPQ[T]) Push(x interface{}) {
+	o.points = append(o.points, x.(*opticPoint[T]))
 }
 
 // Pop implements sort.Interface.Pop
-func (o *opticPQ) Pop() interface{} {
-	var first *opticPoint
+func (o *opticPQ[T]) Pop() interface{} {
+	var first *opticPoint[T]
 	first, o.points = o.points[0], o.points[1:]
 	return first
 }
 
 // Returns the index of the point in the optic queue.
-func (o *opticPQ) IndexOf(p *opticPoint) int {
+func (o *opticPQ[T]) IndexOf(p *opticPoint[T]) int {
 	idx := sort.Search(len(o.points), func(i int) bool {
 		return o.points[i].reachDist < p.reachDist
 	})
@@ -51,47 +51,47 @@ func (o *opticPQ) IndexOf(p *opticPoint) int {
 	return idx
 }
 
-var _ heap.Interface = new(opticPQ)
+var _ heap.Interface = new(opticPQ[float32])
 
-type opticPoint struct {
-	point     *Point
+type opticPoint[T Float] struct {
+	point     *Point[T]
 	processed bool
-	reachDist float32
+	reachDist T
 }
 
-type optics struct {
-	index      Index
-	points     []*opticPoint
-	clusters   []*Cluster
-	pointIndex map[*Point]int
+type optics[T Float] struct {
+	index      Index[T]
+	points     []*opticPoint[T]
+	clusters   []*Cluster[T]
+	pointIndex map[*Point[T]]int
 
-	epsilon   float32
+	epsilon   T
 	minPoints int
 }
 
-type Cluster struct {
-	Points []*Point
+type Cluster[T Float] struct {
+	Points []*Point[T]
 }
 
-func (c *Cluster) add(point *Point) {
+func (c *Cluster[T]) add(point *Point[T]) {
 	c.Points = append(c.Points, point)
 }
 
 // OPTICS executes a cluster analysis on the spatial index, where
 // `epsilon` is the max search distance and `minPoints` is the smallest
 // number of individual points needed to qualify as a cluster.
-func OPTICS(idx Index, epsilon float32, minPoints int) []*Cluster {
-	opts := &optics{
+func OPTICS[T Float](idx Index[T], epsilon T, minPoints int) []*Cluster[T] {
+	opts := &optics[T]{
 		index:      idx,
 		epsilon:    epsilon,
 		minPoints:  minPoints,
-		pointIndex: make(map[*Point]int),
+		pointIndex: make(map[*Point[T]]int),
 	}
 
 	points := idx.Points()
-	opts.points = make([]*opticPoint, len(points))
+	opts.points = make([]*opticPoint[T], len(points))
 	for i, point := range points {
-		opts.points[i] = &opticPoint{point: point, reachDist: -1}
+		opts.points[i] = &opticPoint[T]{point: point, reachDist: -1}
 		opts.pointIndex[point] = i
 	}
 
@@ -99,13 +99,13 @@ func OPTICS(idx Index, epsilon float32, minPoints int) []*Cluster {
 	return opts.clusters
 }
 
-func (o *optics) Run(epsilon float32, minPoints int) {
+func (o *optics[T]) Run(epsilon T, minPoints int) {
 	for _, op := range o.points {
 		if op.processed {
 			continue
 		}
 
-		cluster := &Cluster{}
+		cluster := &Cluster[T]{}
 		cluster.add(op.point)
 		o.clusters = append(o.clusters, cluster)
 		op.processed = true
@@ -117,17 +117,17 @@ func (o *optics) Run(epsilon float32, minPoints int) {
 
 		neighbors := o.index.NearestN(op.point, -1, o.epsilon)
 		fmt.Printf("N(%s) => %s\n", op.point, neighbors)
-		queue := &opticPQ{}
+		queue := &opticPQ[T]{}
 		o.updateQueue(op.point, cdsq, neighbors, queue)
 		o.expandCluster(cluster, queue)
 	}
 }
 
-func (o *optics) getRecordForPoint(p *Point) *opticPoint {
+func (o *optics[T]) getRecordForPoint(p *Point[T]) *opticPoint[T] {
 	return o.points[o.pointIndex[p]]
 }
 
-func (o *optics) updateQueue(p *Point, cdsq float32, neighbors []*Point, queue *opticPQ) {
+func (o *optics[T]) updateQueue(p *Point[T], cdsq T, neighbors []*Point[T], queue *opticPQ[T]) {
 	for _, neighbor := range neighbors {
 		op := o.getRecordForPoint(neighbor)
 		if op.processed {
@@ -150,7 +150,7 @@ func (o *optics) updateQueue(p *Point, cdsq float32, neighbors []*Point, queue *
 	}
 }
 
-func (o *optics) expandCluster(cluster *Cluster, queue *opticPQ) {
+func (o *optics[T]) expandCluster(cluster *Cluster[T], queue *opticPQ[T]) {
 	for _, op := range queue.points {
 		if op.processed {
 			continue
@@ -174,7 +174,7 @@ func (o *optics) expandCluster(cluster *Cluster, queue *opticPQ) {
 // squaredDistanceToCore returns the square of a point's distance to the
 // core of the nearest cluster. It returns -1 if a cluster can't be found
 // within the epsilon radius.
-func (o *optics) squaredDistanceToCore(p *Point) float32 {
+func (o *optics[T]) squaredDistanceToCore(p *Point[T]) T {
 	n := o.index.NearestN(p, o.minPoints, o.epsilon)
 	if len(n) == o.minPoints {
 		return p.DistanceToSqr(n[len(n)-1])
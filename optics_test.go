@@ -27,10 +27,10 @@ func TestOPTICS(t *testing.T) {
 	}
 
 	for _, tc := range tt {
-		points := []*Point{}
-		index := NewAxdex(uint(len(tc.data)))
+		points := []*Point[float32]{}
+		index := NewAxdex[float32](uint(len(tc.data)))
 		for _, coord := range tc.data {
-			point := &Point{X: coord[0], Y: coord[1]}
+			point := &Point[float32]{X: coord[0], Y: coord[1]}
 			index.Insert(point)
 			points = append(points, point)
 		}
@@ -38,7 +38,7 @@ func TestOPTICS(t *testing.T) {
 		clusters := OPTICS(index, 4, 3)
 		assert.Equal(t, len(clusters), len(tc.clusters))
 		for i, expectation := range tc.clusters {
-			expected := []*Point{}
+			expected := []*Point[float32]{}
 			for _, idx := range expectation {
 				expected = append(expected, points[idx])
 			}
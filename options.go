@@ -0,0 +1,107 @@
+package microspace
+
+import "math"
+
+// QueryOptions holds per-query hints for NearestNWith, for tuning queries
+// on distributions the default search handles poorly. The zero value
+// behaves like NearestN.
+type QueryOptions[T Float] struct {
+	// CrossAxis scans the points along the other axis from the one the
+	// index is sorted by, for example when most points share the same X
+	// coordinate, or along Y for a custom axis. The index must keep that
+	// axis, through EnableCrossAxis or EnableDualAxis.
+	CrossAxis bool
+	// Window, if positive, first searches only within this distance of
+	// the point, doubling it until `n` neighbors are found, it reaches
+	// `max` or no points are left beyond it. This helps when `max` is much larger than the distance the
+	// neighbors are usually found at.
+	Window T
+	// MaxCandidates, if positive, limits the number of candidate points
+	// scanned. The results are the best found before hitting the limit.
+	MaxCandidates int
+}
+
+// NearestNWith is like NearestN, but tuned by the provided options. The
+// point doesn't need to be in the index.
+func (a *Axdex[T]) NearestNWith(p *Point[T], n int, max T, opts QueryOptions[T]) []*Point[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil
+	}
+
+	ax := a.axis
+	if opts.CrossAxis {
+		if a.cross == nil {
+			panic("Cannot query along the cross axis of an index without one.")
+		}
+		ax = a.cross
+	}
+
+	limit := -1
+	if opts.MaxCandidates > 0 {
+		limit = opts.MaxCandidates
+	}
+
+	radius := max
+	if opts.Window > 0 && opts.Window < max {
+		radius = opts.Window
+	}
+
+	for {
+		// Once no point was left out for being beyond the window, a wider
+		// window can't find any more, and it's widened at least enough
+		// to take in the closest point left out.
+		results, scanned, truncated, beyond := ax.nearest(p, n, radius, limit)
+		if results.Len() == n || radius >= max || truncated || beyond > max || math.IsInf(float64(beyond), 1) {
+			return results.Sorted()
+		}
+
+		if limit >= 0 {
+			limit -= scanned
+		}
+		if radius *= 2; radius < beyond {
+			radius = beyond
+		}
+		if radius > max {
+			radius = max
+		}
+	}
+}
+
+// EnableCrossAxis keeps the points sorted along the other axis from the
+// one the index is sorted by too, for NearestNWith with CrossAxis set.
+// Keeping the second axis costs memory and makes inserts, updates and
+// removes slower.
+func (a *Axdex[T]) EnableCrossAxis() {
+	a.crossAxis()
+}
+
+// crossAxis returns the points sorted along the other axis from the one
+// the index is sorted by, building it if needed. Writes keep it up to date
+// from then on, so it's only built when enabling it, never by queries.
+func (a *Axdex[T]) crossAxis() *axis[T] {
+	if a.cross != nil {
+		return a.cross
+	}
+
+	a.cross = a.newCrossAxis(uint(len(a.points)))
+	for _, p := range a.points {
+		a.cross.Insert(p)
+	}
+	a.cross.runSort()
+
+	return a.cross
+}
+
+// newCrossAxis returns an empty axis with room for `capacity` points,
+// along the other axis from the one the index is sorted by.
+func (a *Axdex[T]) newCrossAxis(capacity uint) *axis[T] {
+	if a.along == AxisY {
+		return newAxis(capacity, func(p *Point[T]) T { return p.X })
+	}
+
+	return newAxis(capacity, func(p *Point[T]) T { return p.Y })
+}
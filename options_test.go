@@ -0,0 +1,39 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNWith(t *testing.T) {
+	// Every point shares the same X coordinate, the worst case for an
+	// index sorted along X.
+	idx := NewAxdex[float32](100)
+	for i := 0; i < 100; i++ {
+		idx.Insert(&Point[float32]{0, float32(i)})
+	}
+	idx.EnableCrossAxis()
+	p := &Point[float32]{0, 50.2}
+	expected := []*Point[float32]{idx.Points()[50], idx.Points()[51], idx.Points()[49]}
+
+	assert.Equal(t, expected, idx.NearestNWith(p, 3, 100, QueryOptions[float32]{}))
+	assert.Equal(t, expected, idx.NearestNWith(p, 3, 100, QueryOptions[float32]{CrossAxis: true}))
+	assert.Equal(t, expected, idx.NearestNWith(p, 3, 100, QueryOptions[float32]{Window: 0.5}))
+	assert.Equal(t, expected, idx.NearestNWith(p, 3, 100, QueryOptions[float32]{CrossAxis: true, MaxCandidates: 4}))
+
+	// Along X the first few candidates are nowhere near the point.
+	limited := idx.NearestNWith(p, 3, 100, QueryOptions[float32]{MaxCandidates: 4})
+	assert.Len(t, limited, 3)
+	assert.NotEqual(t, expected, limited)
+
+	// Moving points invalidates the cross axis.
+	idx.ApplyWithin(&Circle[float32]{Center: Point[float32]{0, 50}, Radius: 0.1}, func(p *Point[float32]) { p.Y = 1000 })
+	assert.Equal(t, []*Point[float32]{idx.Points()[51]}, idx.NearestNWith(p, 1, 100, QueryOptions[float32]{CrossAxis: true}))
+
+	// Queries never build the cross axis themselves.
+	other := NewAxdex[float32](0)
+	other.Insert(p)
+	assert.Panics(t, func() { other.NearestNWith(p, 1, 0, QueryOptions[float32]{CrossAxis: true}) })
+	assert.Nil(t, other.cross)
+}
@@ -0,0 +1,118 @@
+package microspace
+
+import (
+	"iter"
+	"slices"
+)
+
+// pageOf returns the slice of up to `limit` points starting at `offset`.
+// A negative limit returns every point after the offset.
+func pageOf[T Float](points []*Point[T], offset, limit int) []*Point[T] {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(points) {
+		return nil
+	}
+
+	points = points[offset:]
+	if limit >= 0 && limit < len(points) {
+		points = points[:limit]
+	}
+
+	return points
+}
+
+// PointsIter returns an iterator over the points in the index, in the
+// same order as Points.
+func (a *Axdex[T]) PointsIter() iter.Seq[*Point[T]] {
+	return slices.Values(a.points)
+}
+
+// PointsPage returns up to `limit` points starting at `offset`, in the
+// same order as Points. A negative limit returns the rest of the points.
+func (a *Axdex[T]) PointsPage(offset, limit int) []*Point[T] {
+	return pageOf(a.points, offset, limit)
+}
+
+// PointsIter returns an iterator over the points in the read index.
+func (d *DoubleBuffered[T]) PointsIter() iter.Seq[*Point[T]] {
+	return d.Read().PointsIter()
+}
+
+// PointsPage returns a page of the points in the read index.
+func (d *DoubleBuffered[T]) PointsPage(offset, limit int) []*Point[T] {
+	return d.Read().PointsPage(offset, limit)
+}
+
+// PointsIter returns an iterator which streams the points in the index
+// from disk, in the same order as Points. The index can't be modified
+// until the iteration has finished. Read errors stop the iteration
+// early and are reported by Err.
+func (d *DiskIndex[T]) PointsIter() iter.Seq[*Point[T]] {
+	return func(yield func(*Point[T]) bool) {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+
+		for _, p := range d.mem {
+			if !yield(p) {
+				return
+			}
+		}
+
+		for _, r := range d.runs {
+			for i := 0; i < r.size; i++ {
+				p, err := r.at(i)
+				if err != nil {
+					d.setErr(err)
+					return
+				}
+				if !yield(&p) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PointsPage returns up to `limit` points starting at `offset`, in the
+// same order as Points, only reading that page from disk. A negative
+// limit returns the rest of the points.
+func (d *DiskIndex[T]) PointsPage(offset, limit int) []*Point[T] {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	var page []*Point[T]
+	if offset < len(d.mem) {
+		page = append(page, pageOf(d.mem, offset, limit)...)
+		offset = 0
+	} else {
+		offset -= len(d.mem)
+	}
+
+	for _, r := range d.runs {
+		if limit >= 0 && len(page) >= limit {
+			break
+		}
+		if offset >= r.size {
+			offset -= r.size
+			continue
+		}
+
+		for i := offset; i < r.size && (limit < 0 || len(page) < limit); i++ {
+			p, err := r.at(i)
+			if err != nil {
+				d.setErr(err)
+				return page
+			}
+			page = append(page, &p)
+		}
+		offset = 0
+	}
+
+	return page
+}
@@ -0,0 +1,48 @@
+package microspace
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAxdexPointsPage(t *testing.T) {
+	idx := generateIndex(10)
+	points := idx.Points()
+
+	assert.Equal(t, points, slices.Collect(idx.PointsIter()))
+	assert.Equal(t, points[:3], idx.PointsPage(0, 3))
+	assert.Equal(t, points[8:], idx.PointsPage(8, 5))
+	assert.Equal(t, points[4:], idx.PointsPage(4, -1))
+	assert.Empty(t, idx.PointsPage(10, 3))
+}
+
+func TestDiskIndexPointsPage(t *testing.T) {
+	d, err := OpenDiskIndex[float32](t.TempDir(), 4, 100)
+	assert.NoError(t, err)
+	defer d.Close()
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, d.Insert(Point[float32]{float32(i), 0}))
+	}
+
+	points := d.Points()
+	assert.Equal(t, points, slices.Collect(d.PointsIter()))
+	for offset := 0; offset < 10; offset++ {
+		for limit := 1; limit <= 4; limit++ {
+			assert.Equal(t, pageOf(points, offset, limit), d.PointsPage(offset, limit))
+		}
+	}
+	assert.Empty(t, d.PointsPage(10, 3))
+	assert.Empty(t, d.PointsPage(2, 0))
+	assert.Equal(t, points[3:], d.PointsPage(3, -1))
+
+	var first []*Point[float32]
+	for p := range d.PointsIter() {
+		if first = append(first, p); len(first) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, points[:2], first)
+}
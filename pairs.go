@@ -0,0 +1,22 @@
+package microspace
+
+// ForEachPairWithin calls fn with every pair of points within `d` of each
+// other, each pair once, stopping early if fn returns false. The sorted
+// axis is swept once, only comparing each point with those after it
+// within `d` along the axis. Pairs are visited in order along the axis of
+// their first point.
+func (a *Axdex[T]) ForEachPairWithin(d T, fn func(a, b *Point[T]) bool) {
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	data := a.axis.data
+	for i := range data {
+		p, limit := data[i].p, data[i].value+d
+		for j := i + 1; j < len(data) && data[j].value <= limit; j++ {
+			if q := data[j].p; q.DistanceToSqr(p) <= d*d && !fn(p, q) {
+				return
+			}
+		}
+	}
+}
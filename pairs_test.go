@@ -0,0 +1,45 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachPairWithin(t *testing.T) {
+	for _, idx := range []*Axdex[float32]{generateIndex(500), NewAxdexOnAxis[float32](0, AxisY)} {
+		if len(idx.Points()) == 0 {
+			for _, p := range randomPoints(500) {
+				idx.Insert(p)
+			}
+		}
+
+		expected := map[[2]*Point[float32]]bool{}
+		points := idx.Points()
+		for i, p := range points {
+			for _, q := range points[i+1:] {
+				if p.DistanceToSqr(q) <= 0.05*0.05 {
+					expected[[2]*Point[float32]{p, q}] = true
+				}
+			}
+		}
+
+		found := map[[2]*Point[float32]]bool{}
+		idx.ForEachPairWithin(0.05, func(p, q *Point[float32]) bool {
+			if expected[[2]*Point[float32]{q, p}] {
+				p, q = q, p
+			}
+			assert.False(t, found[[2]*Point[float32]{p, q}])
+			found[[2]*Point[float32]{p, q}] = true
+			return true
+		})
+		assert.Equal(t, expected, found)
+
+		calls := 0
+		idx.ForEachPairWithin(1, func(p, q *Point[float32]) bool {
+			calls++
+			return calls < 3
+		})
+		assert.Equal(t, 3, calls)
+	}
+}
@@ -0,0 +1,88 @@
+package microspace
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelSortThreshold is the number of points from which axes are
+// sorted in parallel. Below it, the cost of spreading the work across
+// goroutines outweighs the gain.
+const parallelSortThreshold = 1 << 16
+
+// sortAxisPoints sorts the points by their value. Large lists are cut
+// into one chunk per CPU, which are sorted concurrently and then merged
+// pairwise, also concurrently, until a single sorted list remains.
+func sortAxisPoints[T Float](data axisPointList[T]) axisPointList[T] {
+	return sortAxisPointsOn(data, runtime.GOMAXPROCS(0))
+}
+
+// sortAxisPointsOn is like sortAxisPoints, sorting across `workers`
+// goroutines.
+func sortAxisPointsOn[T Float](data axisPointList[T], workers int) axisPointList[T] {
+	if len(data) < parallelSortThreshold || workers < 2 {
+		sortAxisChunk(data)
+		return data
+	}
+
+	size := (len(data) + workers - 1) / workers
+	var chunks []axisPointList[T]
+	for start := 0; start < len(data); start += size {
+		chunks = append(chunks, data[start:min(start+size, len(data))])
+	}
+
+	var wg sync.WaitGroup
+	for _, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sortAxisChunk(chunk)
+		}()
+	}
+	wg.Wait()
+
+	// Each round merges pairs of neighboring chunks from one buffer into
+	// the other, so the chunks always cover a contiguous buffer.
+	buf := make(axisPointList[T], len(data), cap(data))
+	src, dst := data, buf
+	for len(chunks) > 1 {
+		merged := make([]axisPointList[T], 0, (len(chunks)+1)/2)
+		offset := 0
+		for i := 0; i < len(chunks); i += 2 {
+			left := chunks[i]
+			var right axisPointList[T]
+			if i+1 < len(chunks) {
+				right = chunks[i+1]
+			}
+
+			out := dst[offset : offset+len(left)+len(right)]
+			offset += len(out)
+			merged = append(merged, out)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mergeAxisPoints(out[:0], left, right)
+			}()
+		}
+		wg.Wait()
+
+		chunks, src, dst = merged, dst, src
+	}
+
+	return src
+}
+
+// mergeAxisPoints appends the merge of two sorted lists to dst, which
+// must not overlap either of them, and returns the result.
+func mergeAxisPoints[T Float](dst, a, b axisPointList[T]) axisPointList[T] {
+	for len(a) > 0 && len(b) > 0 {
+		if b[0].value < a[0].value {
+			dst, b = append(dst, b[0]), b[1:]
+		} else {
+			dst, a = append(dst, a[0]), a[1:]
+		}
+	}
+
+	return append(append(dst, a...), b...)
+}
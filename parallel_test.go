@@ -0,0 +1,63 @@
+package microspace
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortAxisPoints(t *testing.T) {
+	for _, n := range []int{0, 10, parallelSortThreshold + 7, 3 * parallelSortThreshold} {
+		data := make(axisPointList[float32], 0, n)
+		for _, p := range randomPoints(n) {
+			data = append(data, axisPoint[float32]{p: p, value: p.X})
+		}
+
+		for _, workers := range []int{1, 3, 8} {
+			sorted := sortAxisPointsOn(append(axisPointList[float32](nil), data...), workers)
+			assert.Len(t, sorted, n)
+			assert.True(t, sort.IsSorted(sorted))
+
+			seen := map[*Point[float32]]bool{}
+			for _, ap := range sorted {
+				seen[ap.p] = true
+			}
+			assert.Len(t, seen, n)
+		}
+	}
+}
+
+func TestParallelBuild(t *testing.T) {
+	points := randomPoints(2 * parallelSortThreshold)
+	idx := NewAxdex[float32](uint(len(points)))
+	for _, p := range points[:len(points)/2] {
+		idx.Insert(p)
+	}
+	idx.NearestN(points[0], 1, 0)
+	for _, p := range points[len(points)/2:] {
+		idx.Insert(p)
+	}
+
+	assertExact(t, idx, randomPoints(20), 5, 2)
+	misplaced := 0
+	for _, p := range points {
+		if idx.axis.data[idx.axis.IndexFor(p)].p != p {
+			misplaced++
+		}
+	}
+	assert.Equal(t, 0, misplaced)
+}
+
+func BenchmarkIndexBuild1000000(b *testing.B) {
+	points := randomPoints(1000000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		idx := NewAxdex[float32](uint(len(points)))
+		for _, p := range points {
+			idx.Insert(p)
+		}
+		idx.axis.runSort()
+	}
+}
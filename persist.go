@@ -0,0 +1,166 @@
+package microspace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrBadFormat is returned when reading an index from data which wasn't
+// written by WriteTo, or by an unsupported version of it.
+var ErrBadFormat = errors.New("microspace: unrecognized index format")
+
+const (
+	// persistMagic starts every index written by WriteTo.
+	persistMagic = "MSAX"
+	// persistVersion is the version of the format written by WriteTo.
+	persistVersion = 1
+	// persistHeaderSize is the size of the header preceding the points.
+	persistHeaderSize = len(persistMagic) + 10
+)
+
+// WriteTo writes the index to w in a compact binary format, which can be
+// loaded back with ReadFrom without sorting the points again. The format
+// is a header of the magic bytes "MSAX", a version byte, the axis byte and
+// the point count as a little endian uint64, followed by the points in
+// order along the axis, each as two little endian float64 coordinates.
+// Indexes on a custom axis can't be written, as the axis is a function.
+// It implements io.WriterTo.
+func (a *Axdex[T]) WriteTo(w io.Writer) (int64, error) {
+	if a.along == AxisCustom {
+		return 0, errors.New("microspace: cannot write an index on a custom axis")
+	}
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	if _, err := cw.Write(persistHeader(a.along, uint64(len(a.axis.data)))); err != nil {
+		return cw.n, err
+	}
+
+	buf := make([]byte, externalPointSize)
+	for _, ap := range a.axis.data {
+		encodePoint(buf, *ap.p)
+		if _, err := cw.Write(buf); err != nil {
+			return cw.n, err
+		}
+	}
+
+	return cw.n, bw.Flush()
+}
+
+// ReadFrom replaces the points in the index with those written to r by
+// WriteTo, along with the axis they were sorted by. The points are loaded
+// already sorted, and are listed by Points in their order along the axis.
+// The index's other settings are kept, while velocities and versions are
+// dropped. It implements io.ReaderFrom.
+func (a *Axdex[T]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: bufio.NewReader(r)}
+	header := make([]byte, persistHeaderSize)
+	if _, err := io.ReadFull(cr, header); err != nil {
+		return cr.n, fmt.Errorf("%w: %w", ErrBadFormat, err)
+	}
+	along, count, err := parsePersistHeader(header)
+	if err != nil {
+		return cr.n, err
+	}
+
+	// The count isn't trusted for preallocating beyond a point, in case
+	// the data is corrupt.
+	loaded := NewAxdexOnAxis[T](uint(min(count, 1<<20)), along)
+	buf := make([]byte, externalPointSize)
+	for i := uint64(0); i < count; i++ {
+		if _, err := io.ReadFull(cr, buf); err != nil {
+			return cr.n, fmt.Errorf("%w: %w", ErrBadFormat, err)
+		}
+
+		p := decodePoint[T](buf)
+		loaded.axis.Insert(&p)
+		loaded.points = append(loaded.points, &p)
+	}
+	loaded.axis.markSorted()
+
+	a.replace(loaded)
+	return cr.n, nil
+}
+
+// replace replaces the points in the index and the axis they're sorted
+// along with those of the loaded index, keeping the index's settings. A
+// zero Axdex, such as one being decoded into, becomes the loaded index.
+func (a *Axdex[T]) replace(loaded *Axdex[T]) {
+	if a.axis == nil {
+		*a = *loaded
+		return
+	}
+
+	a.Reset()
+	a.axis, a.along, a.points = loaded.axis, loaded.along, loaded.points
+	if a.cross != nil {
+		a.cross = nil
+		a.crossAxis()
+	}
+}
+
+// persistHeader returns the header written by WriteTo for `count` points
+// sorted along the axis.
+func persistHeader(along Axis, count uint64) []byte {
+	header := make([]byte, persistHeaderSize)
+	copy(header, persistMagic)
+	header[4], header[5] = persistVersion, byte(along)
+	binary.LittleEndian.PutUint64(header[6:], count)
+	return header
+}
+
+// parsePersistHeader returns the axis and point count from the header
+// written by WriteTo.
+func parsePersistHeader(header []byte) (Axis, uint64, error) {
+	if string(header[:4]) != persistMagic || header[4] != persistVersion {
+		return 0, 0, ErrBadFormat
+	}
+	along := Axis(header[5])
+	if along != AxisX && along != AxisY {
+		return 0, 0, ErrBadFormat
+	}
+
+	return along, binary.LittleEndian.Uint64(header[6:]), nil
+}
+
+// ReadAxdex returns a new index read from r, which was written by WriteTo.
+func ReadAxdex[T Float](r io.Reader) (*Axdex[T], error) {
+	a := NewAxdex[T](0)
+	if _, err := a.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.Write
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader.Read
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
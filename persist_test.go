@@ -0,0 +1,53 @@
+package microspace
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteReadIndex(t *testing.T) {
+	for _, along := range []Axis{AxisX, AxisY} {
+		idx := NewAxdexOnAxis[float32](0, along)
+		for _, p := range randomPoints(300) {
+			idx.Insert(p)
+		}
+
+		var buf bytes.Buffer
+		written, err := idx.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), written)
+		assert.Equal(t, int64(14+300*externalPointSize), written)
+
+		loaded, err := ReadAxdex[float32](bytes.NewReader(buf.Bytes()))
+		assert.NoError(t, err)
+		assert.Equal(t, along, loaded.along)
+		assert.True(t, loaded.axis.sorted)
+		assert.Len(t, loaded.Points(), 300)
+
+		for _, q := range randomPoints(10) {
+			expected, actual := []Point[float32]{}, []Point[float32]{}
+			for _, p := range idx.NearestN(q, 5, 0.3) {
+				expected = append(expected, *p)
+			}
+			for _, p := range loaded.NearestN(q, 5, 0.3) {
+				actual = append(actual, *p)
+			}
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestReadIndexErrors(t *testing.T) {
+	_, err := ReadAxdex[float32](bytes.NewReader([]byte("nope")))
+	assert.ErrorIs(t, err, ErrBadFormat)
+	_, err = ReadAxdex[float32](bytes.NewReader([]byte("MSAX\x02\x00\x00\x00\x00\x00\x00\x00\x00\x00")))
+	assert.ErrorIs(t, err, ErrBadFormat)
+	_, err = ReadAxdex[float32](bytes.NewReader([]byte("MSAX\x01\x00\x05\x00\x00\x00\x00\x00\x00\x00")))
+	assert.ErrorIs(t, err, ErrBadFormat)
+
+	custom := NewAxdexAlong[float32](0, func(p *Point[float32]) float32 { return p.X + p.Y })
+	_, err = custom.WriteTo(&bytes.Buffer{})
+	assert.Error(t, err)
+}
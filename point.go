@@ -2,16 +2,109 @@ package microspace
 
 import "fmt"
 
+// Float is the set of floating point types that a Point's coordinates,
+// and the indexes built over it, can be expressed in.
+type Float interface {
+	~float32 | ~float64
+}
+
 // Point represents a point in two-dimensional space.
-type Point struct{ X, Y float32 }
+type Point[T Float] struct{ X, Y T }
 
 // DistanceToSqr returns the squared distance to the `other` point.
-func (p *Point) DistanceToSqr(other *Point) float32 {
+func (p *Point[T]) DistanceToSqr(other *Point[T]) T {
 	dx, dy := (p.X - other.X), (p.Y - other.Y)
 	return dx*dx + dy*dy
 }
 
 // String returns a textual representation of the point.
-func (p *Point) String() string {
+func (p *Point[T]) String() string {
 	return fmt.Sprintf("(%.4f, %.4f)", p.X, p.Y)
 }
+
+// Rect is an axis-aligned rectangle, including its edges.
+type Rect[T Float] struct{ Min, Max Point[T] }
+
+// Contains returns true if the point lies within the rectangle.
+func (r *Rect[T]) Contains(p *Point[T]) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Bounds returns the rectangle itself, implementing Region.
+func (r *Rect[T]) Bounds() Rect[T] {
+	return *r
+}
+
+// DistanceToSqr returns the squared distance from the point to the closest
+// point in the rectangle, which is zero for points inside it.
+func (r *Rect[T]) DistanceToSqr(p *Point[T]) T {
+	var dx, dy T
+	if p.X < r.Min.X {
+		dx = r.Min.X - p.X
+	} else if p.X > r.Max.X {
+		dx = p.X - r.Max.X
+	}
+	if p.Y < r.Min.Y {
+		dy = r.Min.Y - p.Y
+	} else if p.Y > r.Max.Y {
+		dy = p.Y - r.Max.Y
+	}
+
+	return dx*dx + dy*dy
+}
+
+// overlaps returns true if the rectangles share any point.
+func (r *Rect[T]) overlaps(other *Rect[T]) bool {
+	return r.Min.X <= other.Max.X && other.Min.X <= r.Max.X &&
+		r.Min.Y <= other.Max.Y && other.Min.Y <= r.Max.Y
+}
+
+// union returns the smallest rectangle containing both rectangles.
+func (r *Rect[T]) union(other Rect[T]) Rect[T] {
+	return Rect[T]{
+		Min: Point[T]{X: min(r.Min.X, other.Min.X), Y: min(r.Min.Y, other.Min.Y)},
+		Max: Point[T]{X: max(r.Max.X, other.Max.X), Y: max(r.Max.Y, other.Max.Y)},
+	}
+}
+
+// area returns the area of the rectangle.
+func (r *Rect[T]) area() T {
+	return (r.Max.X - r.Min.X) * (r.Max.Y - r.Min.Y)
+}
+
+// center returns the point in the middle of the rectangle.
+func (r *Rect[T]) center() Point[T] {
+	return Point[T]{X: (r.Min.X + r.Max.X) / 2, Y: (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Circle is a circular region, including its edge.
+type Circle[T Float] struct {
+	Center Point[T]
+	Radius T
+}
+
+// Contains returns true if the point lies within the circle.
+func (c *Circle[T]) Contains(p *Point[T]) bool {
+	return c.Center.DistanceToSqr(p) <= c.Radius*c.Radius
+}
+
+// Bounds returns the smallest rectangle containing the circle.
+func (c *Circle[T]) Bounds() Rect[T] {
+	return Rect[T]{
+		Min: Point[T]{X: c.Center.X - c.Radius, Y: c.Center.Y - c.Radius},
+		Max: Point[T]{X: c.Center.X + c.Radius, Y: c.Center.Y + c.Radius},
+	}
+}
+
+// Region is an area of space which points can be tested against.
+type Region[T Float] interface {
+	// Contains returns true if the point lies within the region.
+	Contains(p *Point[T]) bool
+	// Bounds returns a rectangle containing the whole region.
+	Bounds() Rect[T]
+}
+
+var (
+	_ Region[float32] = new(Rect[float32])
+	_ Region[float32] = new(Circle[float32])
+)
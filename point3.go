@@ -0,0 +1,54 @@
+package microspace
+
+import "fmt"
+
+// Point3 represents a point in three-dimensional space.
+type Point3[T Float] struct{ X, Y, Z T }
+
+// DistanceToSqr returns the squared distance to the `other` point.
+func (p *Point3[T]) DistanceToSqr(other *Point3[T]) T {
+	dx, dy, dz := (p.X - other.X), (p.Y - other.Y), (p.Z - other.Z)
+	return dx*dx + dy*dy + dz*dz
+}
+
+// String returns a textual representation of the point.
+func (p *Point3[T]) String() string {
+	return fmt.Sprintf("(%.4f, %.4f, %.4f)", p.X, p.Y, p.Z)
+}
+
+// Box is an axis-aligned box, including its faces.
+type Box[T Float] struct{ Min, Max Point3[T] }
+
+// Contains returns true if the point lies within the box.
+func (b *Box[T]) Contains(p *Point3[T]) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
+
+// DistanceToSqr returns the squared distance from the point to the closest
+// point in the box, which is zero for points inside it.
+func (b *Box[T]) DistanceToSqr(p *Point3[T]) T {
+	d := func(v, lo, hi T) T {
+		if v < lo {
+			return lo - v
+		} else if v > hi {
+			return v - hi
+		}
+		return 0
+	}
+
+	dx, dy, dz := d(p.X, b.Min.X, b.Max.X), d(p.Y, b.Min.Y, b.Max.Y), d(p.Z, b.Min.Z, b.Max.Z)
+	return dx*dx + dy*dy + dz*dz
+}
+
+// Index3 describes a spatial index over three-dimensional points, with
+// the same semantics as Index.
+type Index3[T Float] interface {
+	// NearestN returns up the `n` nearest neighbors of the point, with
+	// a `max` search distance. `n` May be set to -1 to search for all
+	// neighbors in the distance.
+	NearestN(p *Point3[T], n int, max T) []*Point3[T]
+	// Points returns all points contained in the spatial index.
+	Points() []*Point3[T]
+}
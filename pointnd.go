@@ -0,0 +1,122 @@
+package microspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PointND represents a point in any number of dimensions, such as a small
+// feature vector. Points compared with each other must have the same
+// number of dimensions.
+type PointND[T Float] []T
+
+// DistanceToSqr returns the squared distance to the `other` point.
+func (p PointND[T]) DistanceToSqr(other PointND[T]) T {
+	var d T
+	for i, v := range p {
+		delta := v - other[i]
+		d += delta * delta
+	}
+
+	return d
+}
+
+// String returns a textual representation of the point.
+func (p PointND[T]) String() string {
+	coords := make([]string, len(p))
+	for i, v := range p {
+		coords[i] = fmt.Sprintf("%.4f", v)
+	}
+
+	return "(" + strings.Join(coords, ", ") + ")"
+}
+
+// KDTreeND is the N-dimensional counterpart to KDTree, splitting points
+// along each dimension in turn. Like KDTree it's built once from a slice
+// of points and can't be modified after. It works best for a handful of
+// dimensions; with many dimensions most of the tree has to be searched.
+type KDTreeND[T Float] struct {
+	dims   int
+	points []PointND[T]
+	// nodes is laid out like KDTree.nodes, with nodes at depth d split
+	// along dimension d mod dims.
+	nodes []PointND[T]
+}
+
+// NewKDTreeND returns a k-d tree built from the points, which must all
+// have the same number of dimensions.
+func NewKDTreeND[T Float](points []PointND[T]) *KDTreeND[T] {
+	t := &KDTreeND[T]{
+		points: points,
+		nodes:  append([]PointND[T](nil), points...),
+	}
+	if len(points) > 0 {
+		t.dims = len(points[0])
+		t.build(t.nodes, 0)
+	}
+
+	return t
+}
+
+// build arranges the nodes into a subtree rooted at depth.
+func (t *KDTreeND[T]) build(nodes []PointND[T], depth int) {
+	if len(nodes) <= 1 {
+		return
+	}
+
+	dim := depth % t.dims
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i][dim] < nodes[j][dim] })
+
+	mid := len(nodes) / 2
+	t.build(nodes[:mid], depth+1)
+	t.build(nodes[mid+1:], depth+1)
+}
+
+// Points returns all points contained in the index.
+func (t *KDTreeND[T]) Points() []PointND[T] {
+	return t.points
+}
+
+// NearestN returns up the `n` nearest neighbors of the point, with the
+// same semantics as Index.NearestN. The point doesn't need to be in the
+// index.
+func (t *KDTreeND[T]) NearestN(p PointND[T], n int, max T) []PointND[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(t.points) {
+		n = len(t.points)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	results := newRankedList[PointND[T], T](n)
+	t.search(t.nodes, 0, p, max, &results)
+	return results.Sorted()
+}
+
+// search adds the points in the subtree within `max` of p to the results,
+// skipping any side of a split that's further away than the worst result.
+func (t *KDTreeND[T]) search(nodes []PointND[T], depth int, p PointND[T], max T, results *rankedList[PointND[T], T]) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	if d := node.DistanceToSqr(p); d <= max*max {
+		results.Insert(node, d)
+	}
+
+	dim := depth % t.dims
+	delta := p[dim] - node[dim]
+	near, far := nodes[:mid], nodes[mid+1:]
+	if delta > 0 {
+		near, far = far, near
+	}
+
+	t.search(near, depth+1, p, max, results)
+	if delta*delta <= max*max && (!results.Full() || delta*delta < results.Worst()) {
+		t.search(far, depth+1, p, max, results)
+	}
+}
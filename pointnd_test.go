@@ -0,0 +1,43 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKDTreeND(t *testing.T) {
+	points := make([]PointND[float64], 1000)
+	for i := range points {
+		points[i] = make(PointND[float64], 6)
+		for d := range points[i] {
+			points[i][d] = rand.Float64()
+		}
+	}
+	tree := NewKDTreeND(points)
+	assert.Len(t, tree.Points(), 1000)
+
+	for _, p := range points[:30] {
+		expected := []float64{}
+		for _, other := range points {
+			if d := other.DistanceToSqr(p); d <= 0.5*0.5 {
+				expected = append(expected, d)
+			}
+		}
+		sort.Float64s(expected)
+		if len(expected) > 5 {
+			expected = expected[:5]
+		}
+
+		actual := []float64{}
+		for _, q := range tree.NearestN(p, 5, 0.5) {
+			actual = append(actual, q.DistanceToSqr(p))
+		}
+		assert.Equal(t, expected, actual)
+	}
+
+	assert.Equal(t, "(1.0000, 2.5000)", PointND[float32]{1, 2.5}.String())
+	assert.Empty(t, NewKDTreeND[float32](nil).NearestN(PointND[float32]{0, 0}, 3, 1))
+}
@@ -0,0 +1,46 @@
+package microspace
+
+// Polygon is a simple polygon region given by its vertices in order,
+// with an implicit edge from the last vertex back to the first. Points
+// exactly on an edge may or may not be considered inside it.
+type Polygon[T Float] []Point[T]
+
+var _ Region[float32] = Polygon[float32]{}
+
+// Contains returns true if the point lies within the polygon, counting
+// how many edges a ray from the point crosses.
+func (poly Polygon[T]) Contains(p *Point[T]) bool {
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Bounds returns the smallest rectangle containing the polygon.
+func (poly Polygon[T]) Bounds() Rect[T] {
+	if len(poly) == 0 {
+		return Rect[T]{}
+	}
+
+	r := Rect[T]{Min: poly[0], Max: poly[0]}
+	for _, v := range poly[1:] {
+		r = r.union(Rect[T]{Min: v, Max: v})
+	}
+
+	return r
+}
+
+// WithinPolygon returns the points inside the polygon. Only the points in
+// the range of the polygon's bounds along the axis are tested against it.
+func (a *Axdex[T]) WithinPolygon(poly []Point[T]) []*Point[T] {
+	if len(poly) < 3 {
+		return nil
+	}
+
+	return a.within(Polygon[T](poly))
+}
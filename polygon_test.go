@@ -0,0 +1,37 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolygonContains(t *testing.T) {
+	// A U shape, open at the top.
+	u := Polygon[float32]{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	assert.True(t, u.Contains(&Point[float32]{0.5, 2}))
+	assert.True(t, u.Contains(&Point[float32]{1.5, 0.5}))
+	assert.False(t, u.Contains(&Point[float32]{1.5, 2}))
+	assert.False(t, u.Contains(&Point[float32]{4, 0.5}))
+	assert.Equal(t, Rect[float32]{Max: Point[float32]{3, 3}}, u.Bounds())
+}
+
+func TestWithinPolygon(t *testing.T) {
+	idx := generateIndex(1000)
+	triangle := []Point[float32]{{0.1, 0.1}, {0.9, 0.2}, {0.4, 0.8}}
+
+	expected := map[*Point[float32]]bool{}
+	for _, p := range idx.Points() {
+		if Polygon[float32](triangle).Contains(p) {
+			expected[p] = true
+		}
+	}
+	assert.NotEmpty(t, expected)
+
+	found := map[*Point[float32]]bool{}
+	for _, p := range idx.WithinPolygon(triangle) {
+		found[p] = true
+	}
+	assert.Equal(t, expected, found)
+	assert.Empty(t, idx.WithinPolygon(triangle[:2]))
+}
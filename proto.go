@@ -0,0 +1,217 @@
+package microspace
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The ToProto and FromProto helpers encode and decode the messages defined
+// in microspace.proto in the Protocol Buffers wire format, so they can be
+// exchanged with services using code generated from the schema, without
+// the package depending on a Protocol Buffers runtime.
+
+// Wire types used by the messages.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// errProtoTruncated is returned for messages which end partway through a
+// field.
+var errProtoTruncated = fmt.Errorf("%w: truncated protobuf message", ErrBadFormat)
+
+// PointToProto encodes the point as a Point message.
+func PointToProto[T Float](p Point[T]) []byte {
+	return appendProtoPoint(nil, p)
+}
+
+// PointFromProto decodes a Point message.
+func PointFromProto[T Float](data []byte) (Point[T], error) {
+	var p Point[T]
+	err := parseProto(data, func(num int, typ int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && typ == protoFixed64:
+			p.X = T(math.Float64frombits(v))
+		case num == 2 && typ == protoFixed64:
+			p.Y = T(math.Float64frombits(v))
+		}
+		return nil
+	})
+
+	return p, err
+}
+
+// NeighborsToProto encodes the results of a query as a Neighbors message.
+func NeighborsToProto[T Float](neighbors []Neighbor[T]) []byte {
+	var buf, msg []byte
+	for _, n := range neighbors {
+		msg = appendProtoBytes(msg[:0], 1, PointToProto(*n.Point))
+		msg = appendProtoDouble(msg, 2, float64(n.DistSqr))
+		buf = appendProtoBytes(buf, 1, msg)
+	}
+
+	return buf
+}
+
+// NeighborsFromProto decodes a Neighbors message. Each neighbor's point is
+// newly allocated.
+func NeighborsFromProto[T Float](data []byte) ([]Neighbor[T], error) {
+	var neighbors []Neighbor[T]
+	err := parseProto(data, func(num int, typ int, v uint64, b []byte) error {
+		if num != 1 || typ != protoBytes {
+			return nil
+		}
+
+		n := Neighbor[T]{Point: &Point[T]{}}
+		err := parseProto(b, func(num int, typ int, v uint64, b []byte) error {
+			switch {
+			case num == 1 && typ == protoBytes:
+				p, err := PointFromProto[T](b)
+				*n.Point = p
+				return err
+			case num == 2 && typ == protoFixed64:
+				n.DistSqr = T(math.Float64frombits(v))
+			}
+			return nil
+		})
+		neighbors = append(neighbors, n)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return neighbors, nil
+}
+
+// ToProto encodes the index as an Index message, with its points in order
+// along its axis. Indexes on a custom axis can't be encoded.
+func (a *Axdex[T]) ToProto() ([]byte, error) {
+	if a.along == AxisCustom {
+		return nil, errors.New("microspace: cannot encode an index on a custom axis")
+	}
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	buf := make([]byte, 0, 2+len(a.axis.data)*20)
+	if a.along == AxisY {
+		buf = appendProtoVarint(buf, 1<<3|protoVarint)
+		buf = appendProtoVarint(buf, uint64(AxisY))
+	}
+
+	var point []byte
+	for _, ap := range a.axis.data {
+		point = appendProtoPoint(point[:0], *ap.p)
+		buf = appendProtoBytes(buf, 2, point)
+	}
+
+	return buf, nil
+}
+
+// AxdexFromProto decodes an Index message into a new index. The points
+// are sorted again in case the message didn't come from ToProto.
+func AxdexFromProto[T Float](data []byte) (*Axdex[T], error) {
+	along := AxisX
+	var points []Point[T]
+	err := parseProto(data, func(num int, typ int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && typ == protoVarint:
+			if along = Axis(v); along != AxisX && along != AxisY {
+				return fmt.Errorf("%w: unknown axis %d", ErrBadFormat, v)
+			}
+		case num == 2 && typ == protoBytes:
+			p, err := PointFromProto[T](b)
+			points = append(points, p)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a := NewAxdexOnAxis[T](uint(len(points)), along)
+	for i := range points {
+		a.Insert(&points[i])
+	}
+	a.axis.runSort()
+
+	return a, nil
+}
+
+// appendProtoPoint appends the fields of a Point message.
+func appendProtoPoint[T Float](buf []byte, p Point[T]) []byte {
+	buf = appendProtoDouble(buf, 1, float64(p.X))
+	return appendProtoDouble(buf, 2, float64(p.Y))
+}
+
+// appendProtoDouble appends a double field.
+func appendProtoDouble(buf []byte, num int, v float64) []byte {
+	buf = appendProtoVarint(buf, uint64(num)<<3|protoFixed64)
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+// appendProtoBytes appends a length-delimited field, such as a message.
+func appendProtoBytes(buf []byte, num int, b []byte) []byte {
+	buf = appendProtoVarint(buf, uint64(num)<<3|protoBytes)
+	buf = appendProtoVarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// appendProtoVarint appends a varint.
+func appendProtoVarint(buf []byte, v uint64) []byte {
+	return binary.AppendUvarint(buf, v)
+}
+
+// parseProto calls fn with every field of the message: its number, wire
+// type, and either its numeric value or its bytes. Groups, which are
+// deprecated, aren't supported.
+func parseProto(data []byte, fn func(num int, typ int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		data = data[n:]
+
+		num, typ := int(key>>3), int(key&7)
+		var v uint64
+		var b []byte
+		switch typ {
+		case protoVarint:
+			if v, n = binary.Uvarint(data); n <= 0 {
+				return errProtoTruncated
+			}
+		case protoFixed64:
+			if n = 8; len(data) < n {
+				return errProtoTruncated
+			}
+			v = binary.LittleEndian.Uint64(data)
+		case protoFixed32:
+			if n = 4; len(data) < n {
+				return errProtoTruncated
+			}
+			v = uint64(binary.LittleEndian.Uint32(data))
+		case protoBytes:
+			size, m := binary.Uvarint(data)
+			if m <= 0 || uint64(len(data)-m) < size {
+				return errProtoTruncated
+			}
+			b, n = data[m:m+int(size)], m+int(size)
+		default:
+			return fmt.Errorf("%w: unsupported protobuf wire type %d", ErrBadFormat, typ)
+		}
+		data = data[n:]
+
+		if err := fn(num, typ, v, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
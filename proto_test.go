@@ -0,0 +1,65 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointProto(t *testing.T) {
+	data := PointToProto(Point[float32]{1.5, -2})
+	// Fields 1 and 2 as doubles.
+	assert.Equal(t, []byte{0x09, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f, 0x11, 0, 0, 0, 0, 0, 0, 0, 0xc0}, data)
+
+	p, err := PointFromProto[float32](data)
+	assert.NoError(t, err)
+	assert.Equal(t, Point[float32]{1.5, -2}, p)
+
+	// Unknown fields are skipped, and missing fields are zero.
+	p, err = PointFromProto[float32]([]byte{0x18, 0x96, 0x01, 0x25, 1, 2, 3, 4, 0x11, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f})
+	assert.NoError(t, err)
+	assert.Equal(t, Point[float32]{0, 1}, p)
+
+	_, err = PointFromProto[float32](data[:5])
+	assert.ErrorIs(t, err, ErrBadFormat)
+}
+
+func TestNeighborsProto(t *testing.T) {
+	neighbors := []Neighbor[float64]{{&Point[float64]{1, 1}, 0}, {&Point[float64]{2, 3}, 5}}
+	decoded, err := NeighborsFromProto[float64](NeighborsToProto(neighbors))
+	assert.NoError(t, err)
+	assert.Equal(t, neighbors, decoded)
+
+	decoded, err = NeighborsFromProto[float64](nil)
+	assert.NoError(t, err)
+	assert.Empty(t, decoded)
+}
+
+func TestAxdexProto(t *testing.T) {
+	for _, along := range []Axis{AxisX, AxisY} {
+		idx := NewAxdexOnAxis[float32](0, along)
+		for _, p := range randomPoints(100) {
+			idx.Insert(p)
+		}
+
+		data, err := idx.ToProto()
+		assert.NoError(t, err)
+		decoded, err := AxdexFromProto[float32](data)
+		assert.NoError(t, err)
+		assert.Equal(t, along, decoded.along)
+		assert.Len(t, decoded.Points(), 100)
+
+		q := &Point[float32]{0.5, 0.5}
+		expected, actual := []Point[float32]{}, []Point[float32]{}
+		for _, p := range idx.NearestN(q, 5, 0) {
+			expected = append(expected, *p)
+		}
+		for _, p := range decoded.NearestN(q, 5, 0) {
+			actual = append(actual, *p)
+		}
+		assert.Equal(t, expected, actual)
+	}
+
+	_, err := AxdexFromProto[float32]([]byte{0x08, 0x07})
+	assert.ErrorIs(t, err, ErrBadFormat)
+}
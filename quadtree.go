@@ -0,0 +1,266 @@
+package microspace
+
+// Quadtree is an index that recursively splits space into four quadrants,
+// splitting any quadrant holding more than a leaf's capacity of points.
+// It adapts to clustered points, where a single sorted axis often has to
+// scan through unrelated points in other clusters.
+type Quadtree[T Float] struct {
+	maxDepth     int
+	leafCapacity int
+
+	root   *quadNode[T]
+	points []*Point[T]
+	// free holds sets of children released when their parent collapsed,
+	// to be reused when a leaf is split again.
+	free []*[4]*quadNode[T]
+}
+
+// quadNode is a quadrant of the tree, which either holds points as a leaf
+// or has been split into four children.
+type quadNode[T Float] struct {
+	// center is where the quadrant splits, and half is half its size.
+	center, half Point[T]
+	// extent bounds the points actually held under the node, which may
+	// stray outside the quadrant for points outside the tree's bounds.
+	extent Rect[T]
+	count  int
+
+	points   []*Point[T]
+	children *[4]*quadNode[T]
+}
+
+// Default quadtree parameters, used for values that aren't set.
+const (
+	DefaultQuadtreeMaxDepth     = 16
+	DefaultQuadtreeLeafCapacity = 8
+)
+
+// NewQuadtree returns a new quadtree covering the bounds. Leaves are split
+// once they hold more than `leafCapacity` points, unless they're already
+// `maxDepth` levels deep. Values below 1 are replaced by the defaults.
+// Points outside the bounds may still be inserted, but are kept in the
+// quadrants on the edge of the bounds which makes queries on them slower.
+func NewQuadtree[T Float](bounds Rect[T], maxDepth, leafCapacity int) *Quadtree[T] {
+	if maxDepth < 1 {
+		maxDepth = DefaultQuadtreeMaxDepth
+	}
+	if leafCapacity < 1 {
+		leafCapacity = DefaultQuadtreeLeafCapacity
+	}
+
+	return &Quadtree[T]{
+		maxDepth:     maxDepth,
+		leafCapacity: leafCapacity,
+		root: &quadNode[T]{
+			center: Point[T]{X: (bounds.Min.X + bounds.Max.X) / 2, Y: (bounds.Min.Y + bounds.Max.Y) / 2},
+			half:   Point[T]{X: (bounds.Max.X - bounds.Min.X) / 2, Y: (bounds.Max.Y - bounds.Min.Y) / 2},
+		},
+	}
+}
+
+var _ Index[float32] = new(Quadtree[float32])
+
+// Insert adds a point to the index.
+func (q *Quadtree[T]) Insert(p *Point[T]) {
+	q.points = append(q.points, p)
+	q.insert(q.root, p, 0)
+}
+
+// Points implements Index.Points
+func (q *Quadtree[T]) Points() []*Point[T] {
+	return q.points
+}
+
+// Remove removes the point from the index, returning false if it wasn't
+// in the index.
+func (q *Quadtree[T]) Remove(p *Point[T]) bool {
+	if !q.remove(q.root, p) {
+		return false
+	}
+
+	q.points, _ = removePoint(q.points, p)
+	return true
+}
+
+// remove removes the point from under the node. Nodes which are left with
+// no more than a leaf's capacity of points collapse back into leaves, and
+// their children are kept for reuse, so constant inserts and removes
+// don't allocate new nodes. Nodes keep their extent, which still bounds
+// the points that are left.
+func (q *Quadtree[T]) remove(n *quadNode[T], p *Point[T]) bool {
+	if n.children != nil {
+		if !q.remove(n.children[n.quadrant(p)], p) {
+			return false
+		}
+	} else {
+		var ok bool
+		if n.points, ok = removePoint(n.points, p); !ok {
+			return false
+		}
+	}
+
+	n.count--
+	if n.children != nil && n.count <= q.leafCapacity {
+		for _, child := range n.children {
+			n.points = child.collect(n.points)
+		}
+		q.release(n.children)
+		n.children = nil
+	}
+
+	return true
+}
+
+// collect appends the points under the node to `points`.
+func (n *quadNode[T]) collect(points []*Point[T]) []*Point[T] {
+	if n.children == nil {
+		return append(points, n.points...)
+	}
+
+	for _, child := range n.children {
+		points = child.collect(points)
+	}
+
+	return points
+}
+
+// release frees the children, and any of theirs, for reuse.
+func (q *Quadtree[T]) release(children *[4]*quadNode[T]) {
+	for _, child := range children {
+		if child.children != nil {
+			q.release(child.children)
+		}
+	}
+
+	q.free = append(q.free, children)
+}
+
+// newChildren returns four children for the node, centered on its
+// quadrants, reusing released ones if there are any.
+func (q *Quadtree[T]) newChildren(n *quadNode[T]) *[4]*quadNode[T] {
+	var children *[4]*quadNode[T]
+	if k := len(q.free); k > 0 {
+		children, q.free = q.free[k-1], q.free[:k-1]
+	} else {
+		children = &[4]*quadNode[T]{new(quadNode[T]), new(quadNode[T]), new(quadNode[T]), new(quadNode[T])}
+	}
+
+	quarter := Point[T]{X: n.half.X / 2, Y: n.half.Y / 2}
+	for i, child := range children {
+		center := Point[T]{X: n.center.X - quarter.X, Y: n.center.Y - quarter.Y}
+		if i&1 != 0 {
+			center.X += n.half.X
+		}
+		if i&2 != 0 {
+			center.Y += n.half.Y
+		}
+
+		// Leaves get room for as many points as they hold before they're
+		// split, which released children already have.
+		points := child.points[:0]
+		if cap(points) <= q.leafCapacity {
+			points = make([]*Point[T], 0, q.leafCapacity+1)
+		}
+		*child = quadNode[T]{center: center, half: quarter, points: points}
+	}
+
+	return children
+}
+
+// insert adds the point to the node at the depth, splitting it if needed.
+func (q *Quadtree[T]) insert(n *quadNode[T], p *Point[T], depth int) {
+	if n.count == 0 {
+		n.extent = Rect[T]{Min: *p, Max: *p}
+	} else {
+		n.extent.Min.X = min(n.extent.Min.X, p.X)
+		n.extent.Min.Y = min(n.extent.Min.Y, p.Y)
+		n.extent.Max.X = max(n.extent.Max.X, p.X)
+		n.extent.Max.Y = max(n.extent.Max.Y, p.Y)
+	}
+	n.count++
+
+	if n.children != nil {
+		q.insert(n.children[n.quadrant(p)], p, depth+1)
+		return
+	}
+
+	n.points = append(n.points, p)
+	if len(n.points) <= q.leafCapacity || depth >= q.maxDepth {
+		return
+	}
+
+	n.children = q.newChildren(n)
+	for _, pt := range n.points {
+		q.insert(n.children[n.quadrant(pt)], pt, depth+1)
+	}
+	clear(n.points)
+	n.points = n.points[:0]
+}
+
+// quadrant returns the index of the child the point belongs in.
+func (n *quadNode[T]) quadrant(p *Point[T]) int {
+	var i int
+	if p.X >= n.center.X {
+		i |= 1
+	}
+	if p.Y >= n.center.Y {
+		i |= 2
+	}
+
+	return i
+}
+
+// NearestN implements Index.NearestN. The point doesn't need to be in the
+// index.
+func (q *Quadtree[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return q.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (q *Quadtree[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return q.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` nearest neighbors of p
+// within `max`.
+func (q *Quadtree[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(q.points) {
+		n = len(q.points)
+	}
+	if n <= 0 {
+		return &neighborList[T]{}
+	}
+
+	results := newNeighborList[T](n)
+	q.root.search(p, max, results)
+	return results
+}
+
+// search adds the points under the node within `max` of p to the results,
+// visiting closer children first and skipping any that can't hold a point
+// closer than the worst result.
+func (n *quadNode[T]) search(p *Point[T], max T, results *neighborList[T]) {
+	if n.count == 0 {
+		return
+	}
+	if d := n.extent.DistanceToSqr(p); d > max*max || (results.Full() && d >= results.Worst()) {
+		return
+	}
+
+	if n.children == nil {
+		for _, pt := range n.points {
+			if d := pt.DistanceToSqr(p); d <= max*max {
+				results.Insert(pt, d)
+			}
+		}
+		return
+	}
+
+	// Visit the quadrant holding p first, then its neighbors, and the
+	// opposite quadrant last.
+	first := n.quadrant(p)
+	for _, i := range [4]int{first, first ^ 1, first ^ 2, first ^ 3} {
+		n.children[i].search(p, max, results)
+	}
+}
@@ -0,0 +1,83 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuadtreeNearest(t *testing.T) {
+	q := NewQuadtree[float32](Rect[float32]{Max: Point[float32]{1, 1}}, 6, 4)
+
+	// Clustered points, plus a few outside the bounds.
+	for c := 0; c < 10; c++ {
+		center := Point[float32]{rand.Float32(), rand.Float32()}
+		for i := 0; i < 100; i++ {
+			q.Insert(&Point[float32]{center.X + rand.Float32()*0.02, center.Y + rand.Float32()*0.02})
+		}
+	}
+	q.Insert(&Point[float32]{-1, 0.5})
+	q.Insert(&Point[float32]{3, 3})
+	assert.Len(t, q.Points(), 1002)
+
+	assertExact(t, q, q.Points()[:50], 5, 0.1)
+	assertExact(t, q, randomPoints(50), 8, 1)
+	assertExact(t, q, []*Point[float32]{{-1, 0.4}, {2.5, 2.5}}, 3, 10)
+}
+
+func TestQuadtreeDuplicates(t *testing.T) {
+	// Identical points can't be separated, so splitting stops at maxDepth.
+	q := NewQuadtree[float64](Rect[float64]{Max: Point[float64]{1, 1}}, 0, 0)
+	for i := 0; i < 100; i++ {
+		q.Insert(&Point[float64]{0.5, 0.5})
+	}
+
+	assert.Len(t, q.NearestN(&Point[float64]{0, 0}, -1, 1), 100)
+	assert.Empty(t, NewQuadtree[float64](Rect[float64]{}, 0, 0).NearestN(&Point[float64]{0, 0}, 3, 1))
+}
+
+func TestQuadtreeRemove(t *testing.T) {
+	q := NewQuadtree[float32](Rect[float32]{Max: Point[float32]{1, 1}}, 0, 2)
+	points := randomPoints(200)
+	for _, p := range points {
+		q.Insert(p)
+	}
+
+	for _, p := range points[:100] {
+		assert.True(t, q.Remove(p))
+	}
+	assert.False(t, q.Remove(points[0]))
+	assert.Equal(t, points[100:], q.Points())
+	assertExact(t, q, points[:20], 3, 0.2)
+}
+
+func TestQuadtreeRemoveCollapses(t *testing.T) {
+	q := NewQuadtree[float32](Rect[float32]{Max: Point[float32]{1, 1}}, 0, 2)
+	points := randomPoints(200)
+	churn := func() {
+		for _, p := range points {
+			q.Insert(p)
+		}
+		for _, p := range points {
+			q.Remove(p)
+		}
+	}
+
+	churn()
+	assert.Nil(t, q.root.children)
+	assert.Empty(t, q.Points())
+
+	// Once nodes have been released, the same inserts and removes reuse
+	// them rather than allocating new ones.
+	assert.Equal(t, 0.0, testing.AllocsPerRun(10, churn))
+
+	for _, p := range points {
+		q.Insert(p)
+	}
+	assertExact(t, q, points[:20], 3, 0.2)
+	for _, p := range points[:150] {
+		assert.True(t, q.Remove(p))
+	}
+	assertExact(t, q, points[:20], 3, 0.2)
+}
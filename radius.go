@@ -0,0 +1,19 @@
+package microspace
+
+// WithinRadius calls fn with every point within `r` of p, stopping early
+// if fn returns false. Unlike NearestN it doesn't allocate or order the
+// results: points are visited in their order along the axis. The point
+// doesn't need to be in the index.
+func (a *Axdex[T]) WithinRadius(p *Point[T], r T, fn func(*Point[T]) bool) {
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	value := a.axis.ValueFor(p)
+	for i := a.axis.Search(value - r); i < len(a.axis.data) && a.axis.data[i].value <= value+r; i++ {
+		q := a.axis.data[i].p
+		if q.DistanceToSqr(p) <= r*r && !fn(q) {
+			return
+		}
+	}
+}
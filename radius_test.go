@@ -0,0 +1,33 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithinRadius(t *testing.T) {
+	idx := generateIndex(1000)
+	p := &Point[float32]{0.5, 0.5}
+
+	expected := map[*Point[float32]]bool{}
+	for _, q := range idx.Points() {
+		if q.DistanceToSqr(p) <= 0.1*0.1 {
+			expected[q] = true
+		}
+	}
+
+	found := map[*Point[float32]]bool{}
+	idx.WithinRadius(p, 0.1, func(q *Point[float32]) bool {
+		found[q] = true
+		return true
+	})
+	assert.Equal(t, expected, found)
+
+	calls := 0
+	idx.WithinRadius(p, 0.1, func(q *Point[float32]) bool {
+		calls++
+		return calls < 3
+	})
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,80 @@
+package microspace
+
+import (
+	"math"
+	"sort"
+	"unsafe"
+)
+
+// radixSortThreshold is the number of points from which axes are sorted
+// with a radix sort, below which sort.Sort is faster.
+const radixSortThreshold = 1 << 10
+
+// sortAxisChunk sorts the points by their value, using a radix sort for
+// large lists and sort.Sort otherwise.
+func sortAxisChunk[T Float](data axisPointList[T]) {
+	if len(data) < radixSortThreshold {
+		sort.Sort(data)
+		return
+	}
+
+	radixSortAxisPoints(data)
+}
+
+// radixSortAxisPoints sorts the points by their value with a least
+// significant digit radix sort, a byte at a time, over keys which order
+// the same as the values. Passes where every key shares the same byte are
+// skipped.
+func radixSortAxisPoints[T Float](data axisPointList[T]) {
+	var zero T
+	bytes := int(unsafe.Sizeof(zero))
+
+	src, dst := data, make(axisPointList[T], len(data))
+	for pass := 0; pass < bytes; pass++ {
+		shift := uint(pass * 8)
+
+		var counts [256]int
+		for _, ap := range src {
+			counts[byte(radixKey(ap.value)>>shift)]++
+		}
+		if counts[byte(radixKey(src[0].value)>>shift)] == len(src) {
+			continue
+		}
+
+		offset := 0
+		for i, count := range counts {
+			counts[i] = offset
+			offset += count
+		}
+		for _, ap := range src {
+			digit := byte(radixKey(ap.value) >> shift)
+			dst[counts[digit]] = ap
+			counts[digit]++
+		}
+
+		src, dst = dst, src
+	}
+
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
+
+// radixKey returns an unsigned key for the value which sorts in the same
+// order as the values: the sign bit of positive values is set, and all
+// bits of negative values are flipped so larger magnitudes sort first.
+func radixKey[T Float](v T) uint64 {
+	if unsafe.Sizeof(v) == 4 {
+		bits := math.Float32bits(float32(v))
+		if bits>>31 == 1 {
+			return uint64(^bits)
+		}
+		return uint64(bits | 1<<31)
+	}
+
+	bits := math.Float64bits(float64(v))
+	if bits>>63 == 1 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
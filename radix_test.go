@@ -0,0 +1,49 @@
+package microspace
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRadixSortAxisPoints(t *testing.T) {
+	special := []float64{0, math.Copysign(0, -1), 1, -1, math.Inf(1), math.Inf(-1), 1e-40, -1e-40, 1e300, -1e300}
+
+	data32 := make(axisPointList[float32], 0, 5000)
+	data64 := make(axisPointList[float64], 0, 5000)
+	for i := 0; i < 5000; i++ {
+		v := (rand.Float64() - 0.5) * 1e6
+		if i%10 == 0 {
+			v = special[rand.Intn(len(special))]
+		}
+		data32 = append(data32, axisPoint[float32]{p: &Point[float32]{}, value: float32(v)})
+		data64 = append(data64, axisPoint[float64]{p: &Point[float64]{}, value: v})
+	}
+
+	sorted32 := append(axisPointList[float32](nil), data32...)
+	radixSortAxisPoints(sorted32)
+	assert.True(t, sort.IsSorted(sorted32))
+	assert.Equal(t, pointSet(data32), pointSet(sorted32))
+
+	sorted64 := append(axisPointList[float64](nil), data64...)
+	radixSortAxisPoints(sorted64)
+	assert.True(t, sort.IsSorted(sorted64))
+	assert.Equal(t, pointSet(data64), pointSet(sorted64))
+
+	same := axisPointList[float32]{{value: 2}, {value: 2}, {value: 2}}
+	radixSortAxisPoints(same)
+	assert.Len(t, same, 3)
+}
+
+// pointSet returns the set of points in the list.
+func pointSet[T Float](data axisPointList[T]) map[*Point[T]]bool {
+	set := map[*Point[T]]bool{}
+	for _, ap := range data {
+		set[ap.p] = true
+	}
+
+	return set
+}
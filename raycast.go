@@ -0,0 +1,77 @@
+package microspace
+
+import (
+	"math"
+	"sort"
+)
+
+// Raycast returns the points within `radius` of the ray from the origin
+// in the direction `dir`, up to `maxDist` along it, ordered by how far
+// along the ray they are, such as for hit detection. The direction
+// doesn't need to be normalized, and `maxDist` may be 0 or less to cast
+// without a limit. Points behind the origin aren't hit.
+func (a *Axdex[T]) Raycast(origin, dir Point[T], maxDist, radius T) []*Point[T] {
+	length := T(math.Sqrt(float64(dir.X*dir.X + dir.Y*dir.Y)))
+	if length == 0 {
+		panic("Cannot cast a ray without a direction.")
+	}
+	dx, dy := dir.X/length, dir.Y/length
+
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	lo, hi := T(math.Inf(-1)), T(math.Inf(1))
+	if maxDist > 0 {
+		end := Point[T]{X: origin.X + dx*maxDist, Y: origin.Y + dy*maxDist}
+		lo, hi = a.axis.rangeOf(Rect[T]{
+			Min: Point[T]{X: min(origin.X, end.X) - radius, Y: min(origin.Y, end.Y) - radius},
+			Max: Point[T]{X: max(origin.X, end.X) + radius, Y: max(origin.Y, end.Y) + radius},
+		})
+	} else {
+		maxDist = T(math.Inf(1))
+	}
+
+	var hits []*Point[T]
+	var along []T
+	for i := a.axis.Search(lo); i < len(a.axis.data) && a.axis.data[i].value <= hi; i++ {
+		q := a.axis.data[i].p
+		ox, oy := q.X-origin.X, q.Y-origin.Y
+		t := ox*dx + oy*dy
+		if t < 0 || t > maxDist {
+			continue
+		}
+
+		// The offset from the ray is what's left of the offset from the
+		// origin once the part along the ray is taken away.
+		if px, py := ox-t*dx, oy-t*dy; px*px+py*py <= radius*radius {
+			hits = append(hits, q)
+			along = append(along, t)
+		}
+	}
+
+	sort.Sort(raycastHits[T]{hits, along})
+	return hits
+}
+
+// raycastHits sorts points hit by a ray by how far along it they are.
+type raycastHits[T Float] struct {
+	points []*Point[T]
+	along  []T
+}
+
+// Len implements sort.Interface.Len
+func (h raycastHits[T]) Len() int {
+	return len(h.points)
+}
+
+// Less implements sort.Interface.Less
+func (h raycastHits[T]) Less(i, j int) bool {
+	return h.along[i] < h.along[j]
+}
+
+// Swap implements sort.Interface.Swap
+func (h raycastHits[T]) Swap(i, j int) {
+	h.points[i], h.points[j] = h.points[j], h.points[i]
+	h.along[i], h.along[j] = h.along[j], h.along[i]
+}
@@ -0,0 +1,50 @@
+package microspace
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaycast(t *testing.T) {
+	idx := NewAxdex[float64](0)
+	points := []*Point[float64]{{3, 3.05}, {1, 1}, {2, 2.5}, {-1, -1}, {5, 5}, {4, 3.9}}
+	for _, p := range points {
+		idx.Insert(p)
+	}
+
+	// Along the diagonal, within 0.1 of it.
+	assert.Equal(t, []*Point[float64]{points[1], points[0], points[5]}, idx.Raycast(Point[float64]{}, Point[float64]{2, 2}, 6, 0.1))
+	assert.Equal(t, []*Point[float64]{points[1], points[0], points[5], points[4]}, idx.Raycast(Point[float64]{}, Point[float64]{1, 1}, 0, 0.1))
+	assert.Equal(t, []*Point[float64]{points[5], points[0], points[2], points[1], points[3]}, idx.Raycast(Point[float64]{4.5, 4.5}, Point[float64]{-1, -1}, 8, 0.5))
+	assert.Empty(t, idx.Raycast(Point[float64]{}, Point[float64]{0, 1}, 0, 0.1))
+
+	// Every hit matches the brute force answer for rays across random
+	// points, on a custom axis too.
+	diagonal := NewAxdexAlong(0, func(p *Point[float32]) float32 { return (p.X + p.Y) * math.Sqrt2 / 2 })
+	for _, idx := range []*Axdex[float32]{generateIndex(500), diagonal} {
+		if idx == diagonal {
+			for _, p := range randomPoints(500) {
+				idx.Insert(p)
+			}
+		}
+
+		for _, origin := range randomPoints(10) {
+			dir := Point[float32]{0.3, -0.7}
+			hits := idx.Raycast(*origin, dir, 0.5, 0.05)
+
+			var expected []*Point[float32]
+			for _, q := range idx.Points() {
+				ox, oy := float64(q.X-origin.X), float64(q.Y-origin.Y)
+				along := (ox*0.3 - oy*0.7) / math.Sqrt(0.58)
+				if along >= 0 && along <= 0.5 && ox*ox+oy*oy-along*along <= 0.05*0.05 {
+					expected = append(expected, q)
+				}
+			}
+			assert.ElementsMatch(t, expected, hits)
+		}
+	}
+
+	assert.Panics(t, func() { idx.Raycast(Point[float64]{}, Point[float64]{}, 1, 1) })
+}
@@ -0,0 +1,192 @@
+package microspace
+
+import (
+	"math"
+	"sort"
+)
+
+// RectIndex describes a spatial index over rectangles, such as the
+// bounding boxes of sprites, parallel to Index for points.
+type RectIndex[T Float] interface {
+	// NearestN returns up to the `n` rectangles nearest to the point by
+	// the distance to their edges, which is 0 for those containing it,
+	// with the same semantics for `n` and `max` as Index.NearestN.
+	NearestN(p *Point[T], n int, max T) []*Rect[T]
+	// Overlapping returns the rectangles which share any point with r.
+	Overlapping(r Rect[T]) []*Rect[T]
+	// Rects returns all rectangles contained in the index.
+	Rects() []*Rect[T]
+}
+
+// RectAxdex is a RectIndex which keeps its rectangles sorted by their
+// left edges, like an Axdex. Queries scan outwards from the point along X
+// until no rectangle further along could be closer, allowing for the
+// widest rectangle in the index, so it works best when the rectangles are
+// of similar widths.
+type RectAxdex[T Float] struct {
+	rects  []*Rect[T]
+	sorted bool
+	// width is the widest any rectangle in the index has been. It's not
+	// reduced when that rectangle is removed.
+	width T
+}
+
+var _ RectIndex[float32] = new(RectAxdex[float32])
+
+// NewRectAxdex returns a new, empty index with room for `capacity`
+// rectangles.
+func NewRectAxdex[T Float](capacity int) *RectAxdex[T] {
+	return &RectAxdex[T]{rects: make([]*Rect[T], 0, capacity), sorted: true}
+}
+
+// Insert adds a rectangle to the index.
+func (x *RectAxdex[T]) Insert(r *Rect[T]) {
+	x.rects = append(x.rects, r)
+	x.width = max(x.width, r.Max.X-r.Min.X)
+	x.sorted = false
+}
+
+// Update moves the rectangle in the index to the new rectangle, returning
+// false if it isn't in the index.
+func (x *RectAxdex[T]) Update(r *Rect[T], to Rect[T]) bool {
+	i := x.slotOf(r)
+	if i == -1 {
+		return false
+	}
+
+	*r = to
+	x.width = max(x.width, r.Max.X-r.Min.X)
+	for ; i > 0 && x.rects[i-1].Min.X > r.Min.X; i-- {
+		x.rects[i] = x.rects[i-1]
+	}
+	for ; i < len(x.rects)-1 && x.rects[i+1].Min.X < r.Min.X; i++ {
+		x.rects[i] = x.rects[i+1]
+	}
+	x.rects[i] = r
+
+	return true
+}
+
+// Remove removes the rectangle from the index, returning false if it
+// wasn't in the index.
+func (x *RectAxdex[T]) Remove(r *Rect[T]) bool {
+	i := x.slotOf(r)
+	if i == -1 {
+		return false
+	}
+
+	x.rects = append(x.rects[:i], x.rects[i+1:]...)
+	return true
+}
+
+// Len returns the number of rectangles in the index.
+func (x *RectAxdex[T]) Len() int {
+	return len(x.rects)
+}
+
+// Rects implements RectIndex.Rects
+func (x *RectAxdex[T]) Rects() []*Rect[T] {
+	return x.rects
+}
+
+// Overlapping implements RectIndex.Overlapping
+func (x *RectAxdex[T]) Overlapping(r Rect[T]) []*Rect[T] {
+	x.sort()
+
+	var results []*Rect[T]
+	for i := x.search(r.Min.X - x.width); i < len(x.rects) && x.rects[i].Min.X <= r.Max.X; i++ {
+		if x.rects[i].overlaps(&r) {
+			results = append(results, x.rects[i])
+		}
+	}
+
+	return results
+}
+
+// NearestN implements RectIndex.NearestN
+func (x *RectAxdex[T]) NearestN(p *Point[T], n int, max T) []*Rect[T] {
+	return x.nearest(p, n, max, func(r *Rect[T]) T { return r.DistanceToSqr(p) })
+}
+
+// NearestNByCenter is like NearestN, but ranks the rectangles by the
+// distance to their centers.
+func (x *RectAxdex[T]) NearestNByCenter(p *Point[T], n int, max T) []*Rect[T] {
+	return x.nearest(p, n, max, func(r *Rect[T]) T {
+		c := r.center()
+		return c.DistanceToSqr(p)
+	})
+}
+
+// nearest returns up to the `n` rectangles nearest to p within `max`, by
+// the squared distance function. The distance to any point of a rectangle
+// must be no less than the distance along X to its nearest edge.
+func (x *RectAxdex[T]) nearest(p *Point[T], n int, max T, distSqr func(*Rect[T]) T) []*Rect[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(x.rects) {
+		n = len(x.rects)
+	}
+	if n <= 0 || len(x.rects) == 0 {
+		return nil
+	}
+
+	x.sort()
+	results := newRankedList[*Rect[T], T](n)
+	limit := func() T {
+		if results.Full() {
+			return T(math.Sqrt(float64(results.Worst())))
+		}
+		return max
+	}
+	visit := func(r *Rect[T]) {
+		if d := distSqr(r); d <= max*max {
+			results.Insert(r, d)
+		}
+	}
+
+	// Rectangles to the right are no closer than their left edges, and
+	// those to the left are no closer than the widest rectangle allows.
+	start := x.search(p.X)
+	for i := start; i < len(x.rects) && x.rects[i].Min.X-p.X <= limit(); i++ {
+		visit(x.rects[i])
+	}
+	for i := start - 1; i >= 0 && p.X-x.rects[i].Min.X-x.width <= limit(); i-- {
+		visit(x.rects[i])
+	}
+
+	return results.Sorted()
+}
+
+// sort sorts the rectangles by their left edges, if they've changed.
+func (x *RectAxdex[T]) sort() {
+	if x.sorted {
+		return
+	}
+
+	sort.SliceStable(x.rects, func(i, j int) bool { return x.rects[i].Min.X < x.rects[j].Min.X })
+	x.sorted = true
+}
+
+// search returns the index of the first rectangle whose left edge is not
+// less than the value.
+func (x *RectAxdex[T]) search(value T) int {
+	return sort.Search(len(x.rects), func(i int) bool { return x.rects[i].Min.X >= value })
+}
+
+// slotOf returns the position of the rectangle in the sorted list, or -1
+// if it isn't in the index.
+func (x *RectAxdex[T]) slotOf(r *Rect[T]) int {
+	x.sort()
+	for i := x.search(r.Min.X); i < len(x.rects) && x.rects[i].Min.X == r.Min.X; i++ {
+		if x.rects[i] == r {
+			return i
+		}
+	}
+
+	for i, other := range x.rects {
+		if other == r {
+			return i
+		}
+	}
+
+	return -1
+}
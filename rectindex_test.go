@@ -0,0 +1,76 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomRects returns rectangles of up to 0.1 across in the unit square.
+func randomRects(n int) []*Rect[float32] {
+	rects := make([]*Rect[float32], n)
+	for i := range rects {
+		x, y := rand.Float32(), rand.Float32()
+		rects[i] = &Rect[float32]{Min: Point[float32]{x, y}, Max: Point[float32]{x + rand.Float32()*0.1, y + rand.Float32()*0.1}}
+	}
+
+	return rects
+}
+
+func TestRectAxdex(t *testing.T) {
+	idx := NewRectAxdex[float32](0)
+	rects := randomRects(500)
+	for _, r := range rects {
+		idx.Insert(r)
+	}
+	for _, r := range rects[:50] {
+		assert.True(t, idx.Update(r, Rect[float32]{Min: Point[float32]{r.Min.X + 0.05, r.Min.Y}, Max: Point[float32]{r.Max.X + 0.05, r.Max.Y}}))
+	}
+	for _, r := range rects[50:100] {
+		assert.True(t, idx.Remove(r))
+	}
+	rects = rects[:0:0]
+	rects = append(rects, idx.Rects()...)
+	assert.Len(t, rects, 450)
+
+	for _, p := range randomPoints(20) {
+		for _, byCenter := range []bool{false, true} {
+			dist := func(r *Rect[float32]) float32 { return r.DistanceToSqr(p) }
+			found := idx.NearestN(p, 5, 0.2)
+			if byCenter {
+				dist = func(r *Rect[float32]) float32 { c := r.center(); return c.DistanceToSqr(p) }
+				found = idx.NearestNByCenter(p, 5, 0.2)
+			}
+
+			var expected []float32
+			for _, r := range rects {
+				if d := dist(r); d <= 0.2*0.2 {
+					expected = append(expected, d)
+				}
+			}
+			sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+			expected = expected[:min(5, len(expected))]
+
+			assert.Len(t, found, len(expected))
+			for i, r := range found {
+				assert.Equal(t, expected[i], dist(r))
+			}
+		}
+
+		box := Rect[float32]{Min: *p, Max: Point[float32]{p.X + 0.1, p.Y + 0.1}}
+		var expected []*Rect[float32]
+		for _, r := range rects {
+			if r.overlaps(&box) {
+				expected = append(expected, r)
+			}
+		}
+		assert.ElementsMatch(t, expected, idx.Overlapping(box))
+	}
+
+	assert.False(t, idx.Remove(&Rect[float32]{}))
+	assert.False(t, idx.Update(&Rect[float32]{}, Rect[float32]{}))
+	assert.Len(t, idx.NearestN(&Point[float32]{0.5, 0.5}, -1, 0), 450)
+	assert.Empty(t, NewRectAxdex[float32](0).NearestN(&Point[float32]{}, 3, 0))
+}
@@ -0,0 +1,116 @@
+package microspace
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry manages a set of named indexes, such as one each for enemies,
+// pickups and projectiles, and can query several of them at once. It's
+// safe to use concurrently, though the indexes themselves are only as
+// safe as their own types make them.
+type Registry[T Float] struct {
+	mu      sync.RWMutex
+	indexes map[string]Index[T]
+}
+
+// Hit is a point found by a Registry query, along with the name of the
+// index it was found in and its squared distance from the query point.
+type Hit[T Float] struct {
+	Index   string
+	Point   *Point[T]
+	DistSqr T
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry[T Float]() *Registry[T] {
+	return &Registry[T]{indexes: map[string]Index[T]{}}
+}
+
+// Register adds the index under the name, returning the index previously
+// registered under it, if any. Replacing an index this way is atomic for
+// concurrent queries.
+func (r *Registry[T]) Register(name string, index Index[T]) (previous Index[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous = r.indexes[name]
+	r.indexes[name] = index
+	return previous
+}
+
+// Get returns the index registered under the name.
+func (r *Registry[T]) Get(name string) (Index[T], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index, ok := r.indexes[name]
+	return index, ok
+}
+
+// Remove unregisters the index with the name, returning false if there
+// was none.
+func (r *Registry[T]) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.indexes[name]
+	delete(r.indexes, name)
+	return ok
+}
+
+// Names returns the names of the registered indexes, in sorted order.
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.indexes))
+	for name := range r.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Clear unregisters every index.
+func (r *Registry[T]) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indexes = map[string]Index[T]{}
+}
+
+// NearestN returns up to the `n` nearest neighbors of the point across
+// the named indexes, or across all of them if no names are given, with a
+// `max` search distance. `n` may be set to -1 to return all neighbors in
+// the distance, and `max` to 0 or less to search without a limit on
+// distance. The point is usually not in most of the indexes, so they
+// must support querying points which aren't in them.
+func (r *Registry[T]) NearestN(p *Point[T], n int, max T, names ...string) []Hit[T] {
+	r.mu.RLock()
+	if len(names) == 0 {
+		for name := range r.indexes {
+			names = append(names, name)
+		}
+	}
+
+	var hits []Hit[T]
+	for _, name := range names {
+		index, ok := r.indexes[name]
+		if !ok {
+			continue
+		}
+
+		for _, q := range index.NearestN(p, n, max) {
+			hits = append(hits, Hit[T]{Index: name, Point: q, DistSqr: q.DistanceToSqr(p)})
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].DistSqr < hits[j].DistSqr })
+	if n >= 0 && len(hits) > n {
+		hits = hits[:n]
+	}
+
+	return hits
+}
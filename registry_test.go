@@ -0,0 +1,43 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	// Single huge buckets make the LSH indexes exact.
+	enemies, pickups := NewLSH[float32](1, 1, 1e9, 1), NewLSH[float32](1, 1, 1e9, 1)
+	e1, e2 := &Point[float32]{1, 0}, &Point[float32]{5, 0}
+	p1 := &Point[float32]{2, 0}
+	enemies.Insert(e1)
+	enemies.Insert(e2)
+	pickups.Insert(p1)
+
+	r := NewRegistry[float32]()
+	assert.Nil(t, r.Register("enemies", enemies))
+	assert.Nil(t, r.Register("pickups", pickups))
+	assert.Equal(t, []string{"enemies", "pickups"}, r.Names())
+
+	origin := &Point[float32]{0, 0}
+	assert.Equal(t, []Hit[float32]{
+		{Index: "enemies", Point: e1, DistSqr: 1},
+		{Index: "pickups", Point: p1, DistSqr: 4},
+	}, r.NearestN(origin, 2, 10))
+	assert.Equal(t, []Hit[float32]{
+		{Index: "enemies", Point: e1, DistSqr: 1},
+		{Index: "enemies", Point: e2, DistSqr: 25},
+	}, r.NearestN(origin, -1, 10, "enemies"))
+	assert.Len(t, r.NearestN(origin, -1, 0), 3)
+
+	index, ok := r.Get("pickups")
+	assert.True(t, ok)
+	assert.Equal(t, pickups, index)
+	assert.True(t, r.Remove("pickups"))
+	assert.False(t, r.Remove("pickups"))
+	assert.Len(t, r.NearestN(origin, -1, 10), 2)
+
+	r.Clear()
+	assert.Empty(t, r.Names())
+}
@@ -0,0 +1,234 @@
+package microspace
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoadFile reads points from a file into a new, sorted Axdex. The format
+// is picked by extension: ".csv" files hold one "x,y" point per line,
+// ".geojson" and ".json" files hold a GeoJSON FeatureCollection whose
+// Point features are loaded, and any other file holds points in the
+// binary run format written by ExternalBuilder and DiskIndex.
+func LoadFile[T Float](path string) (*Axdex[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var points []*Point[T]
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		points, err = readCSVPoints[T](f)
+	case ".geojson", ".json":
+		points, err = readGeoJSONPoints[T](f)
+	default:
+		points, err = readBinaryPoints[T](f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("microspace: loading %s: %w", path, err)
+	}
+
+	idx := NewAxdex[T](uint(len(points)))
+	for _, p := range points {
+		idx.Insert(p)
+	}
+	idx.axis.runSort()
+
+	return idx, nil
+}
+
+// readCSVPoints reads "x,y" lines.
+func readCSVPoints[T Float](r io.Reader) ([]*Point[T], error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]*Point[T], len(records))
+	for i, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("line %d: expected 2 fields, got %d", i+1, len(record))
+		}
+
+		x, err := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		points[i] = &Point[T]{X: T(x), Y: T(y)}
+	}
+
+	return points, nil
+}
+
+// readGeoJSONPoints reads the Point features of a FeatureCollection.
+func readGeoJSONPoints[T Float](r io.Reader) ([]*Point[T], error) {
+	features, err := readGeoJSONFeatures[T](r)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]*Point[T], len(features))
+	for i, f := range features {
+		points[i] = &f.Point
+	}
+
+	return points, nil
+}
+
+// readBinaryPoints reads points in the binary run format.
+func readBinaryPoints[T Float](r io.Reader) ([]*Point[T], error) {
+	var points []*Point[T]
+	run := &externalRun[T]{r: bufio.NewReader(r)}
+	for {
+		ok, err := run.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return points, nil
+		}
+
+		p := run.head
+		points = append(points, &p)
+	}
+}
+
+// Reloader keeps an index loaded from a file, polling the file for changes
+// and rebuilding the index in the background whenever it changes. Each
+// rebuilt index is swapped in atomically, so queries always see either
+// the old or the new data in full.
+type Reloader[T Float] struct {
+	path    string
+	current atomic.Pointer[Axdex[T]]
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	err     error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// WatchFile loads the index from the file with LoadFile, then checks the
+// file for changes every `interval` until the Reloader is closed.
+func WatchFile[T Float](path string, interval time.Duration) (*Reloader[T], error) {
+	r := &Reloader[T]{
+		path: path,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	go r.watch(interval)
+	return r, nil
+}
+
+var _ Index[float32] = new(Reloader[float32])
+
+// watch polls the file until the Reloader is closed.
+func (r *Reloader[T]) watch(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.reloadIfChanged()
+		}
+	}
+}
+
+// reloadIfChanged reloads the file if its size or modification time
+// changed since it was last loaded.
+func (r *Reloader[T]) reloadIfChanged() {
+	info, err := os.Stat(r.path)
+
+	r.mu.Lock()
+	if err != nil {
+		r.err = err
+		r.mu.Unlock()
+		return
+	}
+	changed := !info.ModTime().Equal(r.modTime) || info.Size() != r.size
+	r.mu.Unlock()
+
+	if changed {
+		r.Reload()
+	}
+}
+
+// Reload loads the file and swaps in the new index right away. If loading
+// fails, the current index is kept and the error is returned, and also
+// reported by Err until a later load succeeds.
+func (r *Reloader[T]) Reload() error {
+	info, err := os.Stat(r.path)
+	var idx *Axdex[T]
+	if err == nil {
+		idx, err = LoadFile[T](r.path)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err = err
+	if err != nil {
+		return err
+	}
+
+	r.modTime, r.size = info.ModTime(), info.Size()
+	r.current.Store(idx)
+	return nil
+}
+
+// Err returns the error from the latest failed reload, or nil if the
+// latest reload succeeded.
+func (r *Reloader[T]) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Index returns the currently loaded index.
+func (r *Reloader[T]) Index() *Axdex[T] {
+	return r.current.Load()
+}
+
+// NearestN implements Index.NearestN against the current index.
+func (r *Reloader[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return r.Index().NearestN(p, n, max)
+}
+
+// Points implements Index.Points against the current index.
+func (r *Reloader[T]) Points() []*Point[T] {
+	return r.Index().Points()
+}
+
+// Close stops watching the file. The last loaded index remains usable.
+func (r *Reloader[T]) Close() {
+	close(r.stop)
+	<-r.done
+}
@@ -0,0 +1,64 @@
+package microspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "points.csv")
+	os.WriteFile(csvPath, []byte("3,1\n1, 2\n"), 0644)
+	idx, err := LoadFile[float32](csvPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Point[float32]{{3, 1}, {1, 2}}, idx.Points())
+
+	geoPath := filepath.Join(dir, "points.geojson")
+	os.WriteFile(geoPath, []byte(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[-0.12,51.5]}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[1,1]]}}
+	]}`), 0644)
+	idx, err = LoadFile[float32](geoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Point[float32]{{-0.12, 51.5}}, idx.Points())
+
+	b := NewExternalBuilder[float32](dir, 1)
+	b.Insert(Point[float32]{4, 4})
+	b.Insert(Point[float32]{2, 2})
+	assert.NoError(t, b.Each(func(Point[float32]) error { return nil }))
+	idx, err = LoadFile[float32](b.runs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []*Point[float32]{{4, 4}}, idx.Points())
+	b.Close()
+
+	os.WriteFile(csvPath, []byte("1,x\n"), 0644)
+	_, err = LoadFile[float32](csvPath)
+	assert.Error(t, err)
+}
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "points.csv")
+	os.WriteFile(path, []byte("0,0\n"), 0644)
+
+	r, err := WatchFile[float32](path, time.Millisecond)
+	assert.NoError(t, err)
+	defer r.Close()
+	assert.Len(t, r.Points(), 1)
+
+	os.WriteFile(path, []byte("0,0\n1,1\n"), 0644)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(r.Points()) != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Len(t, r.Points(), 2)
+
+	os.WriteFile(path, []byte("broken\n"), 0644)
+	assert.Error(t, r.Reload())
+	assert.Error(t, r.Err())
+	assert.Len(t, r.Points(), 2)
+}
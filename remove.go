@@ -0,0 +1,50 @@
+package microspace
+
+import "slices"
+
+// Remove removes the point from the index, returning false if it wasn't
+// in the index. Any velocity or version attached to the point is dropped
+// with it.
+func (a *Axdex[T]) Remove(p *Point[T]) bool {
+	if !a.axis.remove(p) {
+		return false
+	}
+	if a.cross != nil {
+		a.cross.remove(p)
+	}
+
+	a.points, _ = removePoint(a.points, p)
+	delete(a.velocities, p)
+	delete(a.versions, p)
+
+	return true
+}
+
+// remove removes the point from the axis, returning false if it wasn't on
+// the axis.
+func (a *axis[T]) remove(p *Point[T]) bool {
+	i := a.IndexFor(p)
+	if i == -1 {
+		return false
+	}
+
+	a.data = slices.Delete(a.data, i, i+1)
+	delete(a.indexed, p)
+	for ; i < len(a.data); i++ {
+		a.indexed[a.data[i].p] = i
+	}
+	a.settled = len(a.data)
+
+	return true
+}
+
+// removePoint removes the point from the slice, keeping the order of the
+// remaining points, and returns false if it wasn't in the slice.
+func removePoint[T Float](points []*Point[T], p *Point[T]) ([]*Point[T], bool) {
+	i := slices.Index(points, p)
+	if i == -1 {
+		return points, false
+	}
+
+	return slices.Delete(points, i, i+1), true
+}
@@ -0,0 +1,50 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAxdexRemove(t *testing.T) {
+	idx := NewAxdex[float32](5)
+	points := []*Point[float32]{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}}
+	for _, p := range points {
+		idx.Insert(p)
+	}
+	idx.SetVelocity(points[1], Point[float32]{1, 0})
+
+	assert.Equal(t, points[:2], idx.NearestN(points[0], 2, 10))
+	assert.True(t, idx.Remove(points[1]))
+	assert.False(t, idx.Remove(points[1]))
+	assert.False(t, idx.Remove(&Point[float32]{0, 0}))
+
+	assert.Equal(t, []*Point[float32]{points[0], points[2], points[3], points[4]}, idx.Points())
+	assert.Equal(t, []*Point[float32]{points[0], points[2]}, idx.NearestN(points[0], 2, 10))
+	assert.Equal(t, Point[float32]{}, idx.VelocityOf(points[1]))
+	for i, ap := range idx.axis.data {
+		assert.Equal(t, i, idx.axis.IndexFor(ap.p))
+	}
+
+	// Removing works with points inserted since the last query too.
+	p := &Point[float32]{1.5, 0}
+	idx.Insert(p)
+	assert.True(t, idx.Remove(p))
+	assert.Equal(t, []*Point[float32]{points[0], points[2]}, idx.NearestN(points[0], 2, 10))
+}
+
+func TestRemoveMovedPoint(t *testing.T) {
+	idx := NewAxdex[float64](3)
+	a, b := &Point[float64]{1, 1}, &Point[float64]{2, 2}
+	idx.Insert(a)
+	idx.Insert(b)
+	idx.NearestN(a, 1, 0)
+
+	// Moving the point directly leaves it at its old place on the axis,
+	// where it's still found.
+	a.X = 5
+	assert.Equal(t, 0, idx.axis.IndexFor(a))
+	assert.True(t, idx.Remove(a))
+	assert.Equal(t, []*Point[float64]{b}, idx.Points())
+	assert.Equal(t, 1, len(idx.axis.data))
+}
@@ -0,0 +1,26 @@
+package microspace
+
+// Reset removes every point from the index, keeping its settings and the
+// memory backing it, so it can be filled again without allocating, such
+// as when rebuilding the index every tick.
+func (a *Axdex[T]) Reset() {
+	clear(a.points)
+	a.points = a.points[:0]
+	a.axis.reset()
+	if a.cross != nil {
+		a.cross.reset()
+	}
+
+	clear(a.velocities)
+	a.maxSpeedSqr = 0
+	clear(a.versions)
+}
+
+// reset removes every point from the axis, keeping its backing array.
+func (a *axis[T]) reset() {
+	clear(a.data)
+	a.data = a.data[:0]
+	clear(a.indexed)
+	a.sorted = false
+	a.settled = 0
+}
@@ -0,0 +1,33 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReset(t *testing.T) {
+	idx := NewAxdex[float32](100)
+	idx.EnableDualAxis()
+	for _, p := range randomPoints(100) {
+		idx.Insert(p)
+	}
+	moved := idx.Points()[0]
+	idx.Update(moved, 0.5, 0.5)
+	idx.NearestN(moved, 3, 0)
+
+	idx.Reset()
+	assert.Empty(t, idx.Points())
+	assert.Empty(t, idx.NearestN(&Point[float32]{0.5, 0.5}, 3, 0))
+	assert.Equal(t, uint64(0), idx.VersionOf(moved))
+
+	points := randomPoints(100)
+	allocs := testing.AllocsPerRun(10, func() {
+		idx.Reset()
+		for _, p := range points {
+			idx.Insert(p)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+	assertExact(t, idx, randomPoints(10), 3, 2)
+}
@@ -0,0 +1,66 @@
+package microspace
+
+import "math"
+
+// ReverseNearest returns the points which have p among their `k` nearest
+// neighbors, nearest to p first, such as the customers a new store would
+// be closest to. The point doesn't need to be in the index; if it is, it's
+// not counted as its own neighbor. Points tied with p for a place among
+// the neighbors may or may not be included.
+//
+// Around p, a point can only have p among its `k` nearest neighbors if
+// fewer than `k` points are closer to p within the same 60 degree sector,
+// since those would all be closer to it than p is. So the `k` points
+// nearest to p in each of six sectors are the only candidates, and each
+// is then checked with a query of its own.
+func (a *Axdex[T]) ReverseNearest(p *Point[T], k int) []*Point[T] {
+	if k <= 0 || len(a.points) == 0 {
+		return nil
+	}
+
+	var counts [6]int
+	var candidates []*Point[T]
+	var dists []T
+	for q, d := range a.NearestIter(p) {
+		if q == p {
+			continue
+		}
+
+		sector := 0
+		if d > 0 {
+			angle := math.Atan2(float64(q.Y-p.Y), float64(q.X-p.X))
+			sector = int((angle+math.Pi)/(math.Pi/3)) % 6
+		}
+		if counts[sector] < k {
+			counts[sector]++
+			candidates = append(candidates, q)
+			dists = append(dists, d)
+		}
+
+		if counts == [6]int{k, k, k, k, k, k} {
+			break
+		}
+	}
+
+	var results []*Point[T]
+	for i, q := range candidates {
+		if dists[i] == 0 || a.closerThan(q, p, dists[i], k) < k {
+			results = append(results, q)
+		}
+	}
+
+	return results
+}
+
+// closerThan returns how many points other than q and p are closer to q
+// than the squared distance d, counting up to `limit`.
+func (a *Axdex[T]) closerThan(q, p *Point[T], d T, limit int) int {
+	count := 0
+	for _, r := range a.NearestN(q, limit+2, T(math.Sqrt(float64(d)))) {
+		if r != q && r != p && r.DistanceToSqr(q) < d {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,40 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseNearest(t *testing.T) {
+	idx := NewAxdex[float64](0)
+	points := []*Point[float64]{{0, 0}, {1, 0}, {3, 0}, {10, 0}, {0, 2}}
+	for _, p := range points {
+		idx.Insert(p)
+	}
+
+	// {1, 0} is nearest to {0, 0} and {3, 0}, and second nearest to {0, 2}.
+	assert.Equal(t, []*Point[float64]{points[0], points[2]}, idx.ReverseNearest(points[1], 1))
+	assert.Equal(t, []*Point[float64]{points[0], points[2], points[4], points[3]}, idx.ReverseNearest(points[1], 2))
+	assert.Equal(t, []*Point[float64]{points[2], points[3]}, idx.ReverseNearest(&Point[float64]{5, 0}, 1))
+	assert.Empty(t, idx.ReverseNearest(points[1], 0))
+
+	random := generateIndex(500)
+	for _, p := range randomPoints(10) {
+		for _, k := range []int{1, 3} {
+			var expected []*Point[float32]
+			for _, q := range random.Points() {
+				closer := 0
+				for _, r := range random.Points() {
+					if r != q && r.DistanceToSqr(q) < p.DistanceToSqr(q) {
+						closer++
+					}
+				}
+				if closer < k {
+					expected = append(expected, q)
+				}
+			}
+			assert.ElementsMatch(t, expected, random.ReverseNearest(p, k))
+		}
+	}
+}
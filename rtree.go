@@ -0,0 +1,289 @@
+package microspace
+
+import (
+	"math"
+	"sort"
+)
+
+// RTree is an index which groups nearby points into leaves of a bounded
+// size, and groups those into nodes bounding their children, up to a
+// single root. Queries descend only into the nodes whose bounds could
+// hold a neighbor. Trees over static datasets should be built with
+// NewRTreeSTR, which packs nodes full for the fastest queries.
+type RTree[T Float] struct {
+	capacity int
+	root     *rtreeNode[T]
+	points   []*Point[T]
+}
+
+// rtreeNode is a node in the tree, which holds either points as a leaf or
+// child nodes.
+type rtreeNode[T Float] struct {
+	bounds   Rect[T]
+	leaf     bool
+	points   []*Point[T]
+	children []*rtreeNode[T]
+}
+
+// DefaultRTreeCapacity is the number of entries per node used when the
+// capacity isn't set.
+const DefaultRTreeCapacity = 16
+
+// NewRTree returns a new empty R-tree, for points to be inserted into,
+// with up to `capacity` entries in each node. A capacity below 2 is
+// replaced by DefaultRTreeCapacity.
+func NewRTree[T Float](capacity int) *RTree[T] {
+	if capacity < 2 {
+		capacity = DefaultRTreeCapacity
+	}
+
+	return &RTree[T]{
+		capacity: capacity,
+		root:     &rtreeNode[T]{leaf: true},
+	}
+}
+
+// NewRTreeSTR returns an R-tree over the points, bulk loaded using the
+// Sort-Tile-Recursive algorithm. Points are sorted into vertical slices,
+// and each slice is sorted and cut into full nodes, level by level. This
+// is both much faster than inserting the points one at a time and gives
+// a tree with less overlap between nodes. More points may be inserted
+// later, though the tree then gradually loses its packing.
+func NewRTreeSTR[T Float](points []*Point[T], capacity int) *RTree[T] {
+	r := NewRTree[T](capacity)
+	if len(points) == 0 {
+		return r
+	}
+
+	r.points = append(r.points, points...)
+	var nodes []*rtreeNode[T]
+	for _, group := range strTile(points, r.capacity, func(p *Point[T]) Point[T] { return *p }) {
+		nodes = append(nodes, newRTreeLeaf(group))
+	}
+
+	for len(nodes) > 1 {
+		groups := strTile(nodes, r.capacity, func(n *rtreeNode[T]) Point[T] { return n.bounds.center() })
+		nodes = nodes[:0:0]
+		for _, group := range groups {
+			nodes = append(nodes, newRTreeInner(group))
+		}
+	}
+
+	r.root = nodes[0]
+	return r
+}
+
+// strTile sorts the items into groups of up to `capacity` using
+// Sort-Tile-Recursive: by the X coordinate of their centers into vertical
+// slices, then by Y within each slice.
+func strTile[T Float, E any](items []E, capacity int, center func(E) Point[T]) [][]E {
+	sorted := append([]E(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return center(sorted[i]).X < center(sorted[j]).X })
+
+	groups := (len(sorted) + capacity - 1) / capacity
+	slices := int(math.Ceil(math.Sqrt(float64(groups))))
+	perSlice := ((groups + slices - 1) / slices) * capacity
+
+	var tiles [][]E
+	for start := 0; start < len(sorted); start += perSlice {
+		slice := sorted[start:min(start+perSlice, len(sorted))]
+		sort.Slice(slice, func(i, j int) bool { return center(slice[i]).Y < center(slice[j]).Y })
+		for i := 0; i < len(slice); i += capacity {
+			tiles = append(tiles, slice[i:min(i+capacity, len(slice))])
+		}
+	}
+
+	return tiles
+}
+
+// newRTreeLeaf returns a leaf holding the points.
+func newRTreeLeaf[T Float](points []*Point[T]) *rtreeNode[T] {
+	n := &rtreeNode[T]{leaf: true, points: append([]*Point[T](nil), points...)}
+	n.refit()
+	return n
+}
+
+// newRTreeInner returns an inner node holding the children.
+func newRTreeInner[T Float](children []*rtreeNode[T]) *rtreeNode[T] {
+	n := &rtreeNode[T]{children: append([]*rtreeNode[T](nil), children...)}
+	n.refit()
+	return n
+}
+
+var _ Index[float32] = new(RTree[float32])
+
+// Insert adds a point to the index.
+func (r *RTree[T]) Insert(p *Point[T]) {
+	r.points = append(r.points, p)
+	if split := r.root.insert(p, r.capacity); split != nil {
+		r.root = newRTreeInner([]*rtreeNode[T]{r.root, split})
+	}
+}
+
+// Points implements Index.Points
+func (r *RTree[T]) Points() []*Point[T] {
+	return r.points
+}
+
+// NearestN implements Index.NearestN. The point doesn't need to be in the
+// index.
+func (r *RTree[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return r.nearest(p, n, max).Sorted()
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (r *RTree[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return r.nearest(p, n, max).Neighbors()
+}
+
+// nearest returns the list of up to the `n` nearest neighbors of p
+// within `max`.
+func (r *RTree[T]) nearest(p *Point[T], n int, max T) *neighborList[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(r.points) {
+		n = len(r.points)
+	}
+	if n <= 0 {
+		return &neighborList[T]{}
+	}
+
+	results := newNeighborList[T](n)
+	r.root.search(p, max, results)
+	return results
+}
+
+// insert adds the point under the node, into the child needing the least
+// enlargement to hold it. If the node overflows it's split in two, and
+// the new sibling is returned for the parent to hold.
+func (n *rtreeNode[T]) insert(p *Point[T], capacity int) *rtreeNode[T] {
+	if n.leaf && len(n.points) == 0 {
+		n.bounds = Rect[T]{Min: *p, Max: *p}
+	} else {
+		n.bounds = n.bounds.union(Rect[T]{Min: *p, Max: *p})
+	}
+
+	if n.leaf {
+		n.points = append(n.points, p)
+		if len(n.points) > capacity {
+			return n.split()
+		}
+		return nil
+	}
+
+	best, bestGrowth, bestArea := 0, T(0), T(0)
+	for i, child := range n.children {
+		grown := child.bounds.union(Rect[T]{Min: *p, Max: *p})
+		area := child.bounds.area()
+		growth := grown.area() - area
+		if i == 0 || growth < bestGrowth || (growth == bestGrowth && area < bestArea) {
+			best, bestGrowth, bestArea = i, growth, area
+		}
+	}
+
+	if split := n.children[best].insert(p, capacity); split != nil {
+		n.children = append(n.children, split)
+		if len(n.children) > capacity {
+			return n.split()
+		}
+	}
+
+	return nil
+}
+
+// split moves the half of the node's entries furthest along its longer
+// side into a new sibling, which is returned.
+func (n *rtreeNode[T]) split() *rtreeNode[T] {
+	alongX := n.bounds.Max.X-n.bounds.Min.X >= n.bounds.Max.Y-n.bounds.Min.Y
+	key := func(c Point[T]) T {
+		if alongX {
+			return c.X
+		}
+		return c.Y
+	}
+
+	sibling := &rtreeNode[T]{leaf: n.leaf}
+	if n.leaf {
+		sort.Slice(n.points, func(i, j int) bool { return key(*n.points[i]) < key(*n.points[j]) })
+		half := len(n.points) / 2
+		sibling.points = append(sibling.points, n.points[half:]...)
+		n.points = n.points[:half:half]
+	} else {
+		sort.Slice(n.children, func(i, j int) bool {
+			return key(n.children[i].bounds.center()) < key(n.children[j].bounds.center())
+		})
+		half := len(n.children) / 2
+		sibling.children = append(sibling.children, n.children[half:]...)
+		n.children = n.children[:half:half]
+	}
+
+	n.refit()
+	sibling.refit()
+	return sibling
+}
+
+// refit recomputes the node's bounds from its entries.
+func (n *rtreeNode[T]) refit() {
+	if n.leaf {
+		n.bounds = Rect[T]{Min: *n.points[0], Max: *n.points[0]}
+		for _, p := range n.points[1:] {
+			n.bounds = n.bounds.union(Rect[T]{Min: *p, Max: *p})
+		}
+		return
+	}
+
+	n.bounds = n.children[0].bounds
+	for _, child := range n.children[1:] {
+		n.bounds = n.bounds.union(child.bounds)
+	}
+}
+
+// search adds the points under the node within `max` of p to the results,
+// visiting closer children first and skipping any that can't hold a point
+// closer than the worst result.
+func (n *rtreeNode[T]) search(p *Point[T], max T, results *neighborList[T]) {
+	if n.leaf {
+		for _, q := range n.points {
+			if d := q.DistanceToSqr(p); d <= max*max {
+				results.Insert(q, d)
+			}
+		}
+		return
+	}
+
+	children := make([]*rtreeNode[T], len(n.children))
+	dists := make([]T, len(n.children))
+	copy(children, n.children)
+	for i, child := range children {
+		dists[i] = child.bounds.DistanceToSqr(p)
+	}
+	sort.Sort(rtreeChildren[T]{children, dists})
+
+	for i, child := range children {
+		if dists[i] > max*max || (results.Full() && dists[i] >= results.Worst()) {
+			break
+		}
+		child.search(p, max, results)
+	}
+}
+
+// rtreeChildren sorts child nodes by their distance to a query point.
+type rtreeChildren[T Float] struct {
+	nodes []*rtreeNode[T]
+	dists []T
+}
+
+// Len implements sort.Interface.Len
+func (c rtreeChildren[T]) Len() int {
+	return len(c.nodes)
+}
+
+// Less implements sort.Interface.Less
+func (c rtreeChildren[T]) Less(i, j int) bool {
+	return c.dists[i] < c.dists[j]
+}
+
+// Swap implements sort.Interface.Swap
+func (c rtreeChildren[T]) Swap(i, j int) {
+	c.nodes[i], c.nodes[j] = c.nodes[j], c.nodes[i]
+	c.dists[i], c.dists[j] = c.dists[j], c.dists[i]
+}
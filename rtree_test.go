@@ -0,0 +1,34 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTreeSTR(t *testing.T) {
+	points := randomPoints(2000)
+	r := NewRTreeSTR(points, 8)
+	assert.Equal(t, points, r.Points())
+
+	assertExact(t, r, points[:50], 5, 0.1)
+	assertExact(t, r, randomPoints(50), 8, 1)
+
+	// The tree keeps working as more points are added.
+	for _, p := range randomPoints(500) {
+		r.Insert(p)
+	}
+	assert.Len(t, r.Points(), 2500)
+	assertExact(t, r, randomPoints(50), 5, 0.2)
+}
+
+func TestRTreeInsert(t *testing.T) {
+	r := NewRTree[float32](0)
+	assert.Empty(t, r.NearestN(&Point[float32]{0, 0}, 3, 1))
+
+	for _, p := range randomPoints(1000) {
+		r.Insert(p)
+	}
+	assertExact(t, r, r.Points()[:50], 5, 0.1)
+	assertExact(t, r, randomPoints(10), -1, 0.1)
+}
@@ -1,220 +1,504 @@
 package microspace
 
-import "sort"
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
 
 // Index describes a spatial index that can look
 // up a point's nearest neighbors.
-type Index interface {
+type Index[T Float] interface {
 	// NearestN returns up the `n` nearest neighbors of the point, with
 	// a `max` search distance. `n` May be set to -1 to search for all
-	// neighbors in the distance.8
-	NearestN(p *Point, n int, max float32) []*Point
+	// neighbors in the distance, and `max` may be set to 0 or less to
+	// search without a limit on distance.
+	NearestN(p *Point[T], n int, max T) []*Point[T]
 	// Points returns all points contained in the spatial index.
-	Points() []*Point
+	Points() []*Point[T]
 }
 
 // axisPoint is used for internal recordkeeping of points within an axis.
 // It's a pair of the point and the value of that point's coordinate on
 // the related axis.
-type axisPoint struct {
-	p     *Point
-	value float32
+type axisPoint[T Float] struct {
+	p     *Point[T]
+	value T
 }
 
 // axisPointList implements sort.Interface
-type axisPointList []axisPoint
+type axisPointList[T Float] []axisPoint[T]
 
 // Len implements sort.Interface.Len
-func (a axisPointList) Len() int {
+func (a axisPointList[T]) Len() int {
 	return len(a)
 }
 
 // Less implements sort.Interface.Less
-func (a axisPointList) Less(i, j int) bool {
+func (a axisPointList[T]) Less(i, j int) bool {
 	return a[i].value < a[j].value
 }
 
 // Swap implements sort.Interface.Swap
-func (a axisPointList) Swap(i, j int) {
+func (a axisPointList[T]) Swap(i, j int) {
 	a[i], a[j] = a[j], a[i]
 }
 
 // axis stores a sorted set of points along a one-dimensional line.
-type axis struct {
-	data  axisPointList
-	value func(*Point) float32
-
-	sorted  bool
-	indexed map[*Point]int
+type axis[T Float] struct {
+	data  axisPointList[T]
+	value func(*Point[T]) T
+
+	sorted bool
+	// indexed maps each point to its slot on the sorted axis.
+	indexed map[*Point[T]]int
+	// settled is the number of leading points known to be in order, so
+	// points inserted after sorting can be merged in rather than sorting
+	// everything again.
+	settled int
 }
 
-// newAxis returns an axis created with the provided capacity. It is assumed
-// that the axis will be filled with exactly `capacity` points before
-// any other operations are done on it.
-func newAxis(capacity uint, value func(*Point) float32) *axis {
-	return &axis{
-		data:  make([]axisPoint, 0, capacity),
-		value: func(p *Point) float32 { return p.Y },
+// newAxis returns an axis created with the provided capacity, which is the
+// number of points expected to be inserted.
+func newAxis[T Float](capacity uint, value func(*Point[T]) T) *axis[T] {
+	return &axis[T]{
+		data:  make([]axisPoint[T], 0, capacity),
+		value: value,
 	}
 }
 
-// IndexFor returns the index of the point on the axis. It's assumed that the
-// point will exist in the axis.
-func (a *axis) IndexFor(p *Point) int {
+// IndexFor returns the index of the point on the axis, or -1 if it isn't
+// on the axis.
+func (a *axis[T]) IndexFor(p *Point[T]) int {
 	if !a.sorted {
 		a.runSort()
 	}
 
-	return a.indexed[p]
+	if i, ok := a.indexed[p]; ok {
+		return i
+	}
+
+	return -1
 }
 
 // runSort sorts the data points stored in the axis and generates an index
-// for them.
-func (a *axis) runSort() {
-	sort.Sort(a.data)
+// for them. Points inserted since the last sort are sorted on their own
+// and merged into the rest.
+func (a *axis[T]) runSort() {
+	if a.settled == 0 || a.settled == len(a.data) {
+		a.data = sortAxisPoints(a.data)
+		a.markSorted()
+		return
+	}
+
+	old, added := a.data[:a.settled], sortAxisPoints(a.data[a.settled:])
+	a.data = mergeAxisPoints(make(axisPointList[T], 0, cap(a.data)), old, added)
+	a.markSorted()
+}
 
-	a.indexed = map[*Point]int{}
+// markSorted records that the data points are sorted, generating the
+// index for them.
+func (a *axis[T]) markSorted() {
+	if a.indexed == nil {
+		a.indexed = make(map[*Point[T]]int, len(a.data))
+	}
 	for i, pt := range a.data {
 		a.indexed[pt.p] = i
 	}
 
 	a.sorted = true
+	a.settled = len(a.data)
+}
+
+// Search returns the index of the first point on the axis whose coordinate
+// is not less than the provided value.
+func (a *axis[T]) Search(value T) int {
+	if !a.sorted {
+		a.runSort()
+	}
+
+	return sort.Search(len(a.data), func(i int) bool {
+		return a.data[i].value >= value
+	})
 }
 
 // ValueFor returns the point's coordinate on that axis.
-func (a *axis) ValueFor(p *Point) float32 {
+func (a *axis[T]) ValueFor(p *Point[T]) T {
 	return a.value(p)
 }
 
-// Insert adds a new point to the axis.
-func (a *axis) Insert(p *Point) {
-	if a.sorted {
-		panic("Cannot add items to the index after starting to use it.")
+// Insert adds a new point to the axis. Points inserted after the axis has
+// been sorted are merged in with the next sort.
+func (a *axis[T]) Insert(p *Point[T]) {
+	a.data = append(a.data, axisPoint[T]{p: p, value: a.value(p)})
+	a.sorted = false
+}
+
+type Axdex[T Float] struct {
+	axis   *axis[T]
+	along  Axis
+	points []*Point[T]
+
+	// velocities holds the optional per-point velocities used for
+	// predictive queries, and maxSpeedSqr the largest squared speed set.
+	velocities  map[*Point[T]]Point[T]
+	maxSpeedSqr T
+
+	// bounds optionally holds the world bounds enforced on insert.
+	bounds     *Rect[T]
+	boundsMode BoundsMode
+
+	// stats records query statistics once enabled.
+	stats *queryStats
+
+	// versions holds the version of every point that has been moved.
+	versions map[*Point[T]]uint64
+
+	// cross is the points sorted along the other axis, built by
+	// EnableCrossAxis or EnableDualAxis and kept up to date from then on.
+	cross *axis[T]
+	dual  bool
+
+	// maxCandidates optionally caps the candidates NearestN scans.
+	maxCandidates int
+
+	// metric optionally replaces Euclidean distance in NearestN.
+	metric Metric[T]
+}
+
+// Axis selects the coordinate that an Axdex sorts its points along.
+type Axis int
+
+const (
+	// AxisX sorts points by their X coordinate.
+	AxisX Axis = iota
+	// AxisY sorts points by their Y coordinate.
+	AxisY
+	// AxisCustom sorts points by a custom value, see NewAxdexAlong.
+	AxisCustom
+)
+
+// NewAxdex returns a new axis-based index with room for `capacity` points.
+// The capacity is only a hint: the index grows past it as needed, and may
+// be given more room later with Reserve. Points may be inserted at any
+// time, though inserting them in batches between queries is fastest, as
+// each batch is merged into the sorted axis by the next query.
+func NewAxdex[T Float](capacity uint) *Axdex[T] {
+	return NewAxdexOnAxis[T](capacity, AxisX)
+}
+
+// NewAxdexOnAxis is like NewAxdex, but sorts points along the provided
+// axis. Queries are fastest when points are spread out along the axis,
+// see Tune.
+func NewAxdexOnAxis[T Float](capacity uint, axis Axis) *Axdex[T] {
+	value := func(p *Point[T]) T { return p.X }
+	if axis == AxisY {
+		value = func(p *Point[T]) T { return p.Y }
 	}
 
-	a.data = append(a.data, axisPoint{p: p, value: a.value(p)})
+	return newAxdex(capacity, axis, value)
 }
 
-type Axdex struct {
-	axis   *axis
-	points []*Point
+// NewAxdexAlong is like NewAxdex, but sorts points by a custom value, such
+// as their projection onto a line the points are spread out along. For
+// queries to find every neighbor, the values of two points must never
+// differ by more than the distance between them, which holds for the
+// projection onto any unit vector.
+func NewAxdexAlong[T Float](capacity uint, value func(*Point[T]) T) *Axdex[T] {
+	return newAxdex(capacity, AxisCustom, value)
 }
 
-// NewAxdex returns a new axis-based index with the provided capacity.
-// It's assumed that you will insert exactly `capacity` points before
-// running queries against the index.
-func NewAxdex(capacity uint) *Axdex {
-	a := &Axdex{
-		axis: newAxis(capacity, func(p *Point) float32 { return p.X }),
+// newAxdex returns a new index sorted by the value, along the axis.
+func newAxdex[T Float](capacity uint, along Axis, value func(*Point[T]) T) *Axdex[T] {
+	return &Axdex[T]{
+		axis:   newAxis(capacity, value),
+		along:  along,
+		points: make([]*Point[T], 0, capacity),
 	}
+}
 
+// NewAxdexFromSorted returns a new axis-based index over points which are
+// already sorted by their X coordinate, such as points loaded back from a
+// previously built index. The ordering is trusted rather than checked, so
+// building the index skips the sort entirely.
+func NewAxdexFromSorted[T Float](points []*Point[T]) *Axdex[T] {
+	a := NewAxdex[T](uint(len(points)))
+	for _, p := range points {
+		a.Insert(p)
+	}
+
+	a.axis.markSorted()
 	return a
 }
 
-var _ Index = new(Axdex)
+var _ Index[float32] = new(Axdex[float32])
 
 // Insert implements Index.Insert
-func (a *Axdex) Insert(p *Point) {
+func (a *Axdex[T]) Insert(p *Point[T]) {
+	a.applyBounds(p)
 	a.axis.Insert(p)
 	a.points = append(a.points, p)
+	if a.cross != nil {
+		a.cross.Insert(p)
+	}
 }
 
 // Points implements Index.Points
-func (a *Axdex) Points() []*Point {
+func (a *Axdex[T]) Points() []*Point[T] {
 	return a.points
 }
 
-type axResults struct {
-	src   *Point
-	data  []*Point
-	worst float32
-	count int
+// Reserve makes room for at least `n` more points to be inserted without
+// growing the index, such as before loading a batch of known size.
+func (a *Axdex[T]) Reserve(n int) {
+	a.points = slices.Grow(a.points, n)
+	a.axis.data = slices.Grow(a.axis.data, n)
+	if a.cross != nil {
+		a.cross.data = slices.Grow(a.cross.data, n)
+	}
+}
+
+// searchRadius returns the distance to search within for a `max` passed
+// to a query, where a max of 0 or less is unlimited.
+func searchRadius[T Float](max T) T {
+	if max <= 0 {
+		return T(math.Inf(1))
+	}
+
+	return max
+}
+
+// axResults collects the nearest neighbors found by NearestN within the
+// max distance of src, in a rankedList over the caller's buffers.
+type axResults[T Float] struct {
+	rankedList[*Point[T], T]
+	src    *Point[T]
+	maxSqr T
 }
 
 // Viable returns true if the provided value could possible be a coordinate
-// of a nearest neighbor with coordinate src.
-func (a *axResults) Viable(p *Point) (viable bool, distance float32) {
+// of a nearest neighbor with coordinate src, within the max distance.
+func (a *axResults[T]) Viable(p *Point[T]) (viable bool, distance T) {
 	d := p.DistanceToSqr(a.src)
-	if a.data[a.count-1] == nil {
+	if d > a.maxSqr {
+		return false, d
+	}
+	if !a.Full() {
 		return true, d
 	}
 
-	return d < a.worst, d
+	return d < a.dists[0], d
 }
 
 // HasPotential returns true if the difference between the center point and
 // another point, given as delta, is less than the provided max and if it
 // could possibly yield a viable point. Once this returns false for an axis
 // points "further out" on that axis will not have potential either.
-func (a *axResults) HasPotential(delta, max float32) bool {
+func (a *axResults[T]) HasPotential(delta, max T) bool {
 	if delta > max || -delta > max {
 		return false
 	}
 
-	if a.data[a.count-1] == nil {
+	if !a.Full() {
 		return true
 	}
 
-	return delta*delta < a.worst
+	return delta*delta < a.dists[0]
 }
 
-// GetResult returns a list of results from the list. It will returns as many
-// non-nil results as it can, up to the provided count.
-func (a *axResults) GetResult() []*Point {
-	var i int
-	for i < a.count && a.data[i] != nil {
-		i++
+// rankedList keeps the `n` closest items seen so far in a bounded max-heap
+// on their squared distance, which is cached alongside each item, so the
+// worst item is always at the root and inserting is O(log n). It's the
+// bounded result list shared by the indexes and queries. Once all items
+// are in, Sort orders them by increasing distance.
+type rankedList[E any, T Float] struct {
+	// items and dists have room for `n` items, of which the first `size`
+	// are in the list.
+	items  []E
+	dists  []T
+	size   int
+	sorted bool
+}
+
+// newRankedList returns an empty list with room for `n` items.
+func newRankedList[E any, T Float](n int) rankedList[E, T] {
+	return rankedList[E, T]{items: make([]E, n), dists: make([]T, n)}
+}
+
+// Len returns the number of items in the list.
+func (l *rankedList[E, T]) Len() int {
+	return l.size
+}
+
+// Full returns true once the list holds `n` items.
+func (l *rankedList[E, T]) Full() bool {
+	return l.size == len(l.items)
+}
+
+// Worst returns the squared distance of the furthest item in the list.
+func (l *rankedList[E, T]) Worst() T {
+	return l.dists[0]
+}
+
+// Insert adds the item at squared distance d to the list if it's closer
+// than the worst item, evicting the worst item when the list is full. No
+// more items may be inserted once the list is sorted.
+func (l *rankedList[E, T]) Insert(item E, d T) {
+	if !l.Full() {
+		l.items[l.size], l.dists[l.size] = item, d
+		l.size++
+		l.up(l.size - 1)
+		return
+	}
+	if l.size == 0 || d >= l.dists[0] {
+		return
 	}
 
-	return a.data[:i]
+	l.items[0], l.dists[0] = item, d
+	l.down(0, l.size)
+}
+
+// Sort orders the items by increasing distance in place.
+func (l *rankedList[E, T]) Sort() {
+	if l.sorted {
+		return
+	}
+
+	for end := l.size - 1; end > 0; end-- {
+		l.swap(0, end)
+		l.down(0, end)
+	}
+	l.sorted = true
 }
 
-// Attempts to insert the point into the results.
-func (a *axResults) Insert(p *Point) {
-	for i := 0; i < a.count; i++ {
-		if a.data[i] == p {
+// Sorted sorts the list and returns its items.
+func (l *rankedList[E, T]) Sorted() []E {
+	l.Sort()
+	return l.items[:l.size]
+}
+
+// up moves the item at i towards the root until its parent is further.
+func (l *rankedList[E, T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if l.dists[parent] >= l.dists[i] {
 			return
 		}
+		l.swap(i, parent)
+		i = parent
+	}
+}
 
-		if a.data[i] == nil {
-			a.data[i] = p
-			break
+// down moves the item at i away from the root, among the first `size`
+// items, until both of its children are closer.
+func (l *rankedList[E, T]) down(i, size int) {
+	for {
+		largest := i
+		if c := 2*i + 1; c < size && l.dists[c] > l.dists[largest] {
+			largest = c
 		}
-
-		if a.src.DistanceToSqr(p) < a.src.DistanceToSqr(a.data[i]) {
-			copy(a.data[i+1:], a.data[i:])
-			a.data[i] = p
-			break
+		if c := 2*i + 2; c < size && l.dists[c] > l.dists[largest] {
+			largest = c
+		}
+		if largest == i {
+			return
 		}
+		l.swap(i, largest)
+		i = largest
 	}
+}
 
-	if a.data[a.count-1] != nil {
-		a.worst = a.data[a.count-1].DistanceToSqr(a.src)
-	}
+// swap swaps the items at i and j.
+func (l *rankedList[E, T]) swap(i, j int) {
+	l.items[i], l.items[j] = l.items[j], l.items[i]
+	l.dists[i], l.dists[j] = l.dists[j], l.dists[i]
+}
+
+// neighborList is a rankedList of points, which can also be returned
+// along with their distances.
+type neighborList[T Float] struct {
+	rankedList[*Point[T], T]
+}
+
+func newNeighborList[T Float](n int) *neighborList[T] {
+	return &neighborList[T]{newRankedList[*Point[T], T](n)}
 }
 
 // NearestN returns up the `n` nearest neighbors of the point, with a `max`
-// search distance. It's assumed that p is in the index!
-func (a *Axdex) NearestN(p *Point, n int, max float32) []*Point {
-	if n == -1 {
+// search distance. The point doesn't need to be in the index, in which
+// case the search starts from where it would be on the axis.
+func (a *Axdex[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	max = searchRadius(max)
+	if a.metric != nil {
+		return a.nearestMetric(p, n, max).Sorted()
+	}
+	if a.dual {
+		return a.nearestDual(p, n, max).Sorted()
+	}
+	if a.maxCandidates > 0 {
+		results, _ := a.NearestNCapped(p, n, max)
+		return results
+	}
+
+	if n == -1 || n > len(a.points) {
 		n = len(a.points)
 	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil
+	}
 
-	results := &axResults{src: p, data: make([]*Point, n), count: n}
-	results.Insert(p)
+	return a.nearestInto(p, max, make([]*Point[T], n))
+}
+
+// nearestInto finds up to len(out) nearest neighbors of p within `max`,
+// filling them into out, and returns the filled part of out.
+func (a *Axdex[T]) nearestInto(p *Point[T], max T, out []*Point[T]) []*Point[T] {
+	// Distances for small result sets are kept on the stack, so queries
+	// into a caller's buffer don't allocate.
+	var buf [64]T
+	dists := buf[:]
+	if len(out) > len(buf) {
+		dists = make([]T, len(out))
+	}
+
+	return out[:a.nearestWithin(p, max, out, dists)]
+}
+
+// nearestWithin finds up to len(out) nearest neighbors of p within `max`,
+// filling them into out and their squared distances into dists, both
+// ordered by increasing distance, and returns how many it found.
+func (a *Axdex[T]) nearestWithin(p *Point[T], max T, out []*Point[T], dists []T) int {
+	var scanned int
+	if a.stats != nil {
+		start := time.Now()
+		defer func() { a.stats.record(time.Since(start), scanned) }()
+	}
+
+	results := axResults[T]{
+		rankedList: rankedList[*Point[T], T]{items: out, dists: dists[:len(out)]},
+		src:        p,
+		maxSqr:     max * max,
+	}
 
 	// Warning: logic ahead!
 	// The general algorithm is this. We loop through the axis, starting
 	// at the point in the sorted list of points on that axis and expanding
 	// outwards. As we expand, we look for points that are near to the
 	// center point, and keep track of the n nearest.
-	idx := a.axis.IndexFor(p)
 	var (
 		size  = len(a.axis.data)
-		left  = idx - 1
-		right = idx + 1
 		value = a.axis.ValueFor(p)
+		left  int
+		right int
 	)
+	if idx := a.axis.IndexFor(p); idx != -1 {
+		results.Insert(p, 0)
+		left, right = idx-1, idx+1
+	} else {
+		right = a.axis.Search(value)
+		left = right - 1
+	}
 
 	// At each of these loops, we expand the `left` and/or the `right`
 	// outwards. We do this until the 'distance' along the axis of each
@@ -228,8 +512,8 @@ func (a *Axdex) NearestN(p *Point, n int, max float32) []*Point {
 		var (
 			// leftP/rightP are the point and axis position associated with
 			// that point, from the left/right index.
-			leftP  axisPoint
-			rightP axisPoint
+			leftP  axisPoint[T]
+			rightP axisPoint[T]
 
 			// Viable is set to true if the point at that distance is
 			// closer than the worst point in the results set.
@@ -238,13 +522,14 @@ func (a *Axdex) NearestN(p *Point, n int, max float32) []*Point {
 
 			// Euclidean distance squared of the provided point to the
 			// center point.
-			leftDistance  = float32(0)
-			rightDistance = float32(0)
+			leftDistance  = T(0)
+			rightDistance = T(0)
 		)
 
 		if left >= 0 { // if we might have something to the left of the point
 			leftP = a.axis.data[left]
 			leftViable, leftDistance = results.Viable(leftP.p)
+			scanned++
 
 			// This point wasn't viable, but we might have something
 			// further on! Decrement the left pointer.
@@ -256,6 +541,7 @@ func (a *Axdex) NearestN(p *Point, n int, max float32) []*Point {
 		if right < size { // if we might have something to the left of the point
 			rightP = a.axis.data[right]
 			rightViable, rightDistance = results.Viable(rightP.p)
+			scanned++
 
 			// This point wasn't viable, but we might have something
 			// further on! Increment the right pointer.
@@ -269,10 +555,10 @@ func (a *Axdex) NearestN(p *Point, n int, max float32) []*Point {
 		// point, or the point closer to the center, and insert it in
 		// the results.
 		if leftViable && (!rightViable || leftDistance < rightDistance) {
-			results.Insert(leftP.p)
+			results.Insert(leftP.p, leftDistance)
 			left--
 		} else if rightViable {
-			results.Insert(rightP.p)
+			results.Insert(rightP.p, rightDistance)
 			right++
 		}
 
@@ -297,5 +583,6 @@ func (a *Axdex) NearestN(p *Point, n int, max float32) []*Point {
 		}
 	}
 
-	return results.GetResult()
+	results.Sort()
+	return results.size
 }
@@ -10,8 +10,8 @@ import (
 )
 
 type pointDistanceList struct {
-	center *Point
-	list   []*Point
+	center *Point[float32]
+	list   []*Point[float32]
 }
 
 func (p pointDistanceList) Len() int {
@@ -29,11 +29,11 @@ func (p pointDistanceList) Swap(i, j int) {
 func TestIndexNearest(t *testing.T) {
 	count := 100
 	delta := 0.000001
-	tr := NewAxdex(uint(count))
+	tr := NewAxdex[float32](uint(count))
 
-	points := []*Point{}
+	points := []*Point[float32]{}
 	for i := 0; i < count; i++ {
-		p := &Point{rand.Float32(), rand.Float32()}
+		p := &Point[float32]{rand.Float32(), rand.Float32()}
 		points = append(points, p)
 		tr.Insert(p)
 	}
@@ -48,15 +48,18 @@ func TestIndexNearest(t *testing.T) {
 	testLast := 5
 	for _, p := range points {
 		n := tr.NearestN(p, testLast, 0.25)
-		pdl := pointDistanceList{center: p, list: points}
+		pdl := pointDistanceList{center: p, list: append([]*Point[float32](nil), points...)}
 		sort.Sort(pdl)
 
 		list := pdl.list[:testLast]
-		if len(n) < testLast || len(list) < testLast {
+		for len(list) > 0 && list[len(list)-1].DistanceToSqr(p) > 0.25*0.25 {
+			list = list[:len(list)-1]
+		}
+		if len(n) != len(list) {
 			t.Fatalf("Invalid nearest for point %s:\n\tResults:   %s\n\tExpecting: %s\n", p, n, list)
 		}
 
-		for k := 0; k < testLast; k++ {
+		for k := range list {
 			if math.Abs(float64(list[k].X-n[k].X)) > delta || math.Abs(float64(list[k].Y-n[k].Y)) > delta {
 				t.Fatalf("Invalid nearest for point %s:\n\tResults:   %s\n\tExpecting: %s\n\tGot: %s, expected %s\n", p, n, list, n[k], list[k])
 			}
@@ -64,14 +67,147 @@ func TestIndexNearest(t *testing.T) {
 	}
 }
 
-func finalizeIndex(t *Axdex) {
+func TestIndexNearestFloat64(t *testing.T) {
+	tr := NewAxdex[float64](3)
+	a := &Point[float64]{0, 0}
+	b := &Point[float64]{1e-9, 0}
+	c := &Point[float64]{2e-9, 0}
+	tr.Insert(c)
+	tr.Insert(a)
+	tr.Insert(b)
+
+	assert.Equal(t, []*Point[float64]{a, b, c}, tr.NearestN(a, 3, 1))
+	assert.Equal(t, 1e-18, a.DistanceToSqr(b))
+}
+
+func TestNewAxdexFromSorted(t *testing.T) {
+	points := []*Point[float32]{}
+	for i := 0; i < 50; i++ {
+		points = append(points, &Point[float32]{rand.Float32(), rand.Float32()})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].X < points[j].X })
+
+	tr := NewAxdexFromSorted(points)
+	assert.True(t, tr.axis.sorted)
+	for i, p := range points {
+		assert.Equal(t, p, tr.axis.data[i].p)
+		assert.Equal(t, i, tr.axis.IndexFor(p))
+	}
+
+	expected := NewAxdex[float32](uint(len(points)))
+	for _, p := range points {
+		expected.Insert(p)
+	}
+	for _, p := range points[:10] {
+		assert.Equal(t, expected.NearestN(p, 3, 0.25), tr.NearestN(p, 3, 0.25))
+	}
+}
+
+func TestInsertAfterQuery(t *testing.T) {
+	tr := generateIndex(100)
+	finalizeIndex(tr)
+
+	// Points inserted between queries are merged into the sorted axis.
+	for batch := 0; batch < 5; batch++ {
+		for i := 0; i < 20; i++ {
+			tr.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
+		}
+
+		p := tr.Points()[len(tr.Points())-1]
+		assert.Equal(t, p, tr.NearestN(p, 1, 0.1)[0])
+		assert.True(t, sort.IsSorted(tr.axis.data))
+		assert.Len(t, tr.axis.data, len(tr.Points()))
+		for i, ap := range tr.axis.data {
+			assert.Equal(t, i, tr.axis.IndexFor(ap.p))
+		}
+	}
+}
+
+func TestNewAxdexOnAxis(t *testing.T) {
+	// Each axis sorts by its own coordinate, not whichever newAxis used
+	// to pick regardless of the value it was given.
+	coord := map[Axis]func(p *Point[float32]) float32{
+		AxisX: func(p *Point[float32]) float32 { return p.X },
+		AxisY: func(p *Point[float32]) float32 { return p.Y },
+	}
+	for along, value := range coord {
+		tr := NewAxdexOnAxis[float32](100, along)
+		for _, p := range randomPoints(100) {
+			tr.Insert(p)
+		}
+		finalizeIndex(tr)
+
+		for i, ap := range tr.axis.data {
+			assert.Equal(t, value(ap.p), ap.value)
+			if i > 0 {
+				assert.True(t, tr.axis.data[i-1].value <= ap.value)
+			}
+		}
+	}
+}
+
+func TestNewAxdexAlong(t *testing.T) {
+	// Points scattered along the diagonal, swept by their projection on it.
+	diagonal := func(p *Point[float32]) float32 { return (p.X + p.Y) * math.Sqrt2 / 2 }
+	tr := NewAxdexAlong(300, diagonal)
+	for i := 0; i < 300; i++ {
+		v := rand.Float32()
+		tr.Insert(&Point[float32]{v + rand.Float32()*0.01, v})
+	}
+
+	exact := NewAxdex[float32](300)
+	for _, p := range tr.Points() {
+		exact.Insert(p)
+	}
+	for _, p := range tr.Points()[:30] {
+		assert.Equal(t, exact.NearestNAt(0, p, 4, 0.1), tr.NearestNAt(0, p, 4, 0.1))
+	}
+
+	f := tr.Freeze()
+	assert.Equal(t, 300, f.Len())
+	for i := 1; i < f.Len(); i++ {
+		assert.True(t, f.At(i-1).X <= f.At(i).X)
+	}
+}
+
+func TestNearestForeignPoint(t *testing.T) {
+	tr := NewAxdex[float32](5)
+	points := []*Point[float32]{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}}
+	for _, p := range points {
+		tr.Insert(p)
+	}
+
+	assert.Equal(t, []*Point[float32]{points[2], points[3]}, tr.NearestN(&Point[float32]{2.4, 0}, 2, 5))
+	assert.Equal(t, []*Point[float32]{points[0]}, tr.NearestN(&Point[float32]{-1, 0}, 1, 5))
+	assert.Equal(t, []*Point[float32]{points[4], points[3]}, tr.NearestN(&Point[float32]{9, 0}, 2, 10))
+	assert.Empty(t, NewAxdex[float32](0).NearestN(&Point[float32]{}, 2, 10))
+}
+
+func TestNearestMax(t *testing.T) {
+	tr := NewAxdex[float32](3)
+	a, b, c := &Point[float32]{0, 0}, &Point[float32]{0, 0.5}, &Point[float32]{0.1, 5}
+	for _, p := range []*Point[float32]{a, b, c} {
+		tr.Insert(p)
+	}
+
+	// c is within max along the axis, but not by distance.
+	assert.Equal(t, []*Point[float32]{a, b}, tr.NearestN(a, 3, 1))
+
+	// A max of 0 or less is unlimited, for every index.
+	for _, idx := range []Index[float32]{tr, NewKDTree(tr.Points()), NewGridIndexFor(tr.Points())} {
+		assert.Equal(t, []*Point[float32]{a, b, c}, idx.NearestN(a, -1, 0))
+		assert.Equal(t, []*Point[float32]{c, b}, idx.NearestN(c, 2, -1))
+	}
+}
+
+func finalizeIndex(t *Axdex[float32]) {
 	t.axis.runSort()
 }
 
-func generateIndex(n int) *Axdex {
-	t := NewAxdex(uint(n))
+func generateIndex(n int) *Axdex[float32] {
+	t := NewAxdex[float32](uint(n))
 	for k := 0; k < n; k++ {
-		t.Insert(&Point{rand.Float32(), rand.Float32()})
+		t.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
 	}
 
 	return t
@@ -88,20 +224,20 @@ func benchIndexNearest(b *testing.B, n int) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		t.NearestN(&Point{0.5, 0.5}, 3, 0.25)
+		t.NearestN(&Point[float32]{0.5, 0.5}, 3, 0.25)
 	}
 }
 
 func benchIndexNearestWorstCase(b *testing.B, n int) {
-	t := NewAxdex(uint(n))
+	t := NewAxdex[float32](uint(n))
 	for k := 0; k < n; k++ {
-		t.Insert(&Point{0.6, 0.6})
+		t.Insert(&Point[float32]{0.6, 0.6})
 	}
 
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		t.NearestN(&Point{0.5, 0.5}, 3, 0.25)
+		t.NearestN(&Point[float32]{0.5, 0.5}, 3, 0.25)
 	}
 }
 
@@ -119,3 +255,59 @@ func BenchmarkIndexNearestWorstCase10(b *testing.B)    { benchIndexNearestWorstC
 func BenchmarkIndexNearestWorstCase100(b *testing.B)   { benchIndexNearestWorstCase(b, 100) }
 func BenchmarkIndexNearestWorstCase1000(b *testing.B)  { benchIndexNearestWorstCase(b, 1000) }
 func BenchmarkIndexNearestWorstCase10000(b *testing.B) { benchIndexNearestWorstCase(b, 10000) }
+
+func BenchmarkIndexFor(b *testing.B) {
+	t := generateIndex(100000)
+	points := t.Points()
+	t.axis.runSort()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t.axis.IndexFor(points[i%len(points)])
+	}
+}
+
+func TestNearestLargeN(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	for _, p := range randomPoints(2000) {
+		idx.Insert(p)
+	}
+
+	assertExact(t, idx, randomPoints(10), 500, 2)
+	assertExact(t, idx, idx.Points()[:10], 100, 0.2)
+}
+
+func BenchmarkIndexNearestLargeN(b *testing.B) {
+	t := generateIndex(100000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t.NearestN(&Point[float32]{0.5, 0.5}, 5000, 0)
+	}
+}
+
+func TestGrowPastCapacity(t *testing.T) {
+	idx := NewAxdex[float32](2)
+	points := randomPoints(100)
+	for _, p := range points[:50] {
+		idx.Insert(p)
+	}
+
+	idx.Reserve(50)
+	assert.GreaterOrEqual(t, cap(idx.points)-len(idx.points), 50)
+	assert.GreaterOrEqual(t, cap(idx.axis.data)-len(idx.axis.data), 50)
+	for _, p := range points[50:] {
+		idx.Insert(p)
+	}
+
+	assert.Len(t, idx.Points(), 100)
+	assertExact(t, idx, randomPoints(10), 5, 2)
+}
+
+func TestNearestHugeN(t *testing.T) {
+	idx := generateIndex(10)
+	assert.Len(t, idx.NearestN(&Point[float32]{}, math.MaxInt, 0), 10)
+	for _, other := range []Index[float32]{NewKDTree(idx.Points()), NewGridIndexFor(idx.Points()), NewRTreeSTR(idx.Points(), 0)} {
+		assert.Len(t, other.NearestN(&Point[float32]{}, math.MaxInt, 0), 10)
+	}
+}
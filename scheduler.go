@@ -0,0 +1,92 @@
+package microspace
+
+import "sync"
+
+// Priority orders queries waiting on a Scheduler.
+type Priority int
+
+const (
+	// PriorityBackground is for queries nobody is waiting on, such as
+	// background AI.
+	PriorityBackground Priority = iota
+	// PriorityNormal is the default priority.
+	PriorityNormal
+	// PriorityHigh is for latency-sensitive, player-facing queries.
+	PriorityHigh
+
+	numPriorities
+)
+
+// Scheduler limits how many queries run against a shared index at once.
+// Under contention, waiting queries are let through highest priority
+// first, and in arrival order within a priority.
+type Scheduler[T Float] struct {
+	index Index[T]
+
+	mu      sync.Mutex
+	free    int
+	waiting [numPriorities][]chan struct{}
+}
+
+// NewScheduler returns a scheduler running at most `concurrency` queries
+// against the index at once.
+func NewScheduler[T Float](index Index[T], concurrency int) *Scheduler[T] {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &Scheduler[T]{index: index, free: concurrency}
+}
+
+// Do waits for a slot at the provided priority, then calls fn with the
+// index. It can be used to run any query the index supports.
+func (s *Scheduler[T]) Do(priority Priority, fn func(Index[T])) {
+	s.acquire(priority)
+	defer s.release()
+	fn(s.index)
+}
+
+// NearestN runs Index.NearestN at the provided priority.
+func (s *Scheduler[T]) NearestN(priority Priority, p *Point[T], n int, max T) (results []*Point[T]) {
+	s.Do(priority, func(idx Index[T]) { results = idx.NearestN(p, n, max) })
+	return results
+}
+
+// acquire blocks until the caller holds a slot.
+func (s *Scheduler[T]) acquire(priority Priority) {
+	if priority < 0 {
+		priority = 0
+	} else if priority >= numPriorities {
+		priority = numPriorities - 1
+	}
+
+	s.mu.Lock()
+	if s.free > 0 {
+		s.free--
+		s.mu.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	s.waiting[priority] = append(s.waiting[priority], ready)
+	s.mu.Unlock()
+
+	<-ready
+}
+
+// release hands the caller's slot to the highest priority waiter, or
+// frees it if nobody's waiting.
+func (s *Scheduler[T]) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for priority := numPriorities - 1; priority >= 0; priority-- {
+		if queue := s.waiting[priority]; len(queue) > 0 {
+			s.waiting[priority] = queue[1:]
+			close(queue[0])
+			return
+		}
+	}
+
+	s.free++
+}
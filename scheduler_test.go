@@ -0,0 +1,58 @@
+package microspace
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerPriority(t *testing.T) {
+	idx := generateIndex(100)
+	s := NewScheduler[float32](idx, 1)
+
+	// Hold the only slot while queries of each priority queue up.
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	go s.Do(PriorityNormal, func(Index[float32]) {
+		close(started)
+		<-unblock
+	})
+	<-started
+
+	var (
+		mu    sync.Mutex
+		order []Priority
+		wg    sync.WaitGroup
+	)
+	for _, priority := range []Priority{PriorityBackground, PriorityNormal, PriorityHigh} {
+		wg.Add(1)
+		go func(priority Priority) {
+			defer wg.Done()
+			s.Do(priority, func(Index[float32]) {
+				mu.Lock()
+				order = append(order, priority)
+				mu.Unlock()
+			})
+		}(priority)
+
+		// Wait for the query to be queued before adding the next.
+		for {
+			s.mu.Lock()
+			queued := len(s.waiting[priority])
+			s.mu.Unlock()
+			if queued == 1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, []Priority{PriorityHigh, PriorityNormal, PriorityBackground}, order)
+
+	p := idx.Points()[0]
+	assert.Equal(t, idx.NearestN(p, 3, 0.25), s.NearestN(PriorityHigh, p, 3, 0.25))
+}
@@ -0,0 +1,64 @@
+package microspace
+
+import "math"
+
+// Sector is a circular sector region, such as a view cone, including its
+// edges. It spans `HalfAngle` radians either side of the direction from
+// its apex, out to `Radius`. The direction doesn't need to be normalized.
+type Sector[T Float] struct {
+	Apex      Point[T]
+	Dir       Point[T]
+	HalfAngle T
+	Radius    T
+}
+
+var _ Region[float32] = new(Sector[float32])
+
+// Contains returns true if the point lies within the sector.
+func (s *Sector[T]) Contains(p *Point[T]) bool {
+	dx, dy := p.X-s.Apex.X, p.Y-s.Apex.Y
+	distSqr := dx*dx + dy*dy
+	if distSqr > s.Radius*s.Radius {
+		return false
+	}
+	if distSqr == 0 || s.HalfAngle >= math.Pi {
+		return true
+	}
+
+	// The angle to the point is within the half angle if its cosine is at
+	// least the half angle's, comparing unnormalized to save a root.
+	dot := float64(dx*s.Dir.X + dy*s.Dir.Y)
+	return dot >= math.Cos(float64(s.HalfAngle))*math.Sqrt(float64(distSqr)*float64(s.Dir.X*s.Dir.X+s.Dir.Y*s.Dir.Y))
+}
+
+// Bounds returns the smallest rectangle containing the sector, which
+// holds its apex, the ends of its arc, and any point of its arc furthest
+// along X or Y.
+func (s *Sector[T]) Bounds() Rect[T] {
+	r := Rect[T]{Min: s.Apex, Max: s.Apex}
+	extend := func(angle float64) {
+		p := Point[T]{X: s.Apex.X + s.Radius*T(math.Cos(angle)), Y: s.Apex.Y + s.Radius*T(math.Sin(angle))}
+		r = r.union(Rect[T]{Min: p, Max: p})
+	}
+
+	facing, half := math.Atan2(float64(s.Dir.Y), float64(s.Dir.X)), float64(s.HalfAngle)
+	if half < math.Pi {
+		extend(facing - half)
+		extend(facing + half)
+	}
+	for _, angle := range [4]float64{0, math.Pi / 2, math.Pi, -math.Pi / 2} {
+		if math.Abs(math.Remainder(angle-facing, 2*math.Pi)) <= half {
+			extend(angle)
+		}
+	}
+
+	return r
+}
+
+// WithinSector returns the points inside the sector from p facing `dir`,
+// spanning `halfAngle` radians either side of it out to `radius`, such as
+// for vision checks. Only the points in the range of the sector's bounds
+// along the axis are checked against it.
+func (a *Axdex[T]) WithinSector(p *Point[T], dir Point[T], halfAngle, radius T) []*Point[T] {
+	return a.within(&Sector[T]{Apex: *p, Dir: dir, HalfAngle: halfAngle, Radius: radius})
+}
@@ -0,0 +1,46 @@
+package microspace
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSector(t *testing.T) {
+	s := &Sector[float64]{Dir: Point[float64]{1, 0}, HalfAngle: math.Pi / 4, Radius: 2}
+	assert.True(t, s.Contains(&Point[float64]{1, 0}))
+	assert.True(t, s.Contains(&Point[float64]{1, 0.9}))
+	assert.True(t, s.Contains(&Point[float64]{}))
+	assert.False(t, s.Contains(&Point[float64]{1, 1.1}))
+	assert.False(t, s.Contains(&Point[float64]{-0.5, 0}))
+	assert.False(t, s.Contains(&Point[float64]{2.1, 0}))
+
+	b := s.Bounds()
+	assert.InDelta(t, 0, b.Min.X, 1e-9)
+	assert.InDelta(t, -math.Sqrt2, b.Min.Y, 1e-9)
+	assert.InDelta(t, 2, b.Max.X, 1e-9)
+	assert.InDelta(t, math.Sqrt2, b.Max.Y, 1e-9)
+
+	full := &Sector[float64]{Dir: Point[float64]{0, 1}, HalfAngle: math.Pi, Radius: 1}
+	assert.True(t, full.Contains(&Point[float64]{0, -1}))
+	assert.Equal(t, Rect[float64]{Min: Point[float64]{-1, -1}, Max: Point[float64]{1, 1}}, full.Bounds())
+}
+
+func TestWithinSector(t *testing.T) {
+	idx := generateIndex(2000)
+	for _, p := range randomPoints(20) {
+		dir := Point[float32]{p.Y - 0.5, 0.5 - p.X}
+		s := &Sector[float32]{Apex: *p, Dir: dir, HalfAngle: 0.6, Radius: 0.3}
+
+		bounds := s.Bounds()
+		var expected []*Point[float32]
+		for _, q := range idx.Points() {
+			if s.Contains(q) {
+				expected = append(expected, q)
+				assert.True(t, bounds.Contains(q))
+			}
+		}
+		assert.ElementsMatch(t, expected, idx.WithinSector(p, dir, 0.6, 0.3))
+	}
+}
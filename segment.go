@@ -0,0 +1,137 @@
+package microspace
+
+// Segment is a line segment between two points, such as a stretch of road.
+type Segment[T Float] struct {
+	A, B Point[T]
+}
+
+// ClosestPoint returns the point on the segment closest to p.
+func (s *Segment[T]) ClosestPoint(p *Point[T]) Point[T] {
+	dx, dy := s.B.X-s.A.X, s.B.Y-s.A.Y
+	lengthSqr := dx*dx + dy*dy
+	if lengthSqr == 0 {
+		return s.A
+	}
+
+	t := ((p.X-s.A.X)*dx + (p.Y-s.A.Y)*dy) / lengthSqr
+	t = min(max(t, 0), 1)
+	return Point[T]{X: s.A.X + t*dx, Y: s.A.Y + t*dy}
+}
+
+// DistanceToSqr returns the squared distance from p to the closest point
+// on the segment.
+func (s *Segment[T]) DistanceToSqr(p *Point[T]) T {
+	c := s.ClosestPoint(p)
+	return c.DistanceToSqr(p)
+}
+
+// Bounds returns the smallest rectangle containing the segment.
+func (s *Segment[T]) Bounds() Rect[T] {
+	return Rect[T]{
+		Min: Point[T]{X: min(s.A.X, s.B.X), Y: min(s.A.Y, s.B.Y)},
+		Max: Point[T]{X: max(s.A.X, s.B.X), Y: max(s.A.Y, s.B.Y)},
+	}
+}
+
+// SegmentIndex indexes line segments, for finding the segments nearest a
+// point, such as to snap GPS samples to a road network. It's backed by a
+// RectAxdex over the segments' bounds, so it works best when segments are
+// of similar lengths along X; long roads are best split into shorter
+// segments.
+type SegmentIndex[T Float] struct {
+	bounds   *RectAxdex[T]
+	rects    map[*Segment[T]]*Rect[T]
+	segments map[*Rect[T]]*Segment[T]
+}
+
+// NewSegmentIndex returns a new, empty index with room for `capacity`
+// segments.
+func NewSegmentIndex[T Float](capacity int) *SegmentIndex[T] {
+	return &SegmentIndex[T]{
+		bounds:   NewRectAxdex[T](capacity),
+		rects:    make(map[*Segment[T]]*Rect[T], capacity),
+		segments: make(map[*Rect[T]]*Segment[T], capacity),
+	}
+}
+
+// Insert adds the segment to the index. Inserting a segment which is
+// already in the index updates its position instead.
+func (x *SegmentIndex[T]) Insert(s *Segment[T]) {
+	if r, ok := x.rects[s]; ok {
+		x.bounds.Update(r, s.Bounds())
+		return
+	}
+
+	r := s.Bounds()
+	x.bounds.Insert(&r)
+	x.rects[s] = &r
+	x.segments[&r] = s
+}
+
+// Remove removes the segment from the index, returning false if it wasn't
+// in the index.
+func (x *SegmentIndex[T]) Remove(s *Segment[T]) bool {
+	r, ok := x.rects[s]
+	if !ok {
+		return false
+	}
+
+	x.bounds.Remove(r)
+	delete(x.rects, s)
+	delete(x.segments, r)
+	return true
+}
+
+// Len returns the number of segments in the index.
+func (x *SegmentIndex[T]) Len() int {
+	return len(x.rects)
+}
+
+// Segments returns all segments in the index.
+func (x *SegmentIndex[T]) Segments() []*Segment[T] {
+	return x.toSegments(x.bounds.Rects())
+}
+
+// NearestN returns up to the `n` segments nearest to p, by the distance to
+// their closest points, with the same semantics as Index.NearestN.
+func (x *SegmentIndex[T]) NearestN(p *Point[T], n int, max T) []*Segment[T] {
+	return x.toSegments(x.bounds.nearest(p, n, max, func(r *Rect[T]) T {
+		return x.segments[r].DistanceToSqr(p)
+	}))
+}
+
+// WithinRadius returns the segments passing within `r` of p, nearest
+// first.
+func (x *SegmentIndex[T]) WithinRadius(p *Point[T], r T) []*Segment[T] {
+	if r <= 0 {
+		return nil
+	}
+
+	return x.NearestN(p, -1, r)
+}
+
+// Snap returns the segment nearest to p within `max`, and the closest
+// point on it to p. It returns false if there's no segment within `max`,
+// which may be 0 or less to search without a limit.
+func (x *SegmentIndex[T]) Snap(p *Point[T], max T) (*Segment[T], Point[T], bool) {
+	nearest := x.NearestN(p, 1, max)
+	if len(nearest) == 0 {
+		return nil, Point[T]{}, false
+	}
+
+	return nearest[0], nearest[0].ClosestPoint(p), true
+}
+
+// toSegments returns the segments with the bounds.
+func (x *SegmentIndex[T]) toSegments(rects []*Rect[T]) []*Segment[T] {
+	if len(rects) == 0 {
+		return nil
+	}
+
+	segments := make([]*Segment[T], len(rects))
+	for i, r := range rects {
+		segments[i] = x.segments[r]
+	}
+
+	return segments
+}
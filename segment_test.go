@@ -0,0 +1,68 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentClosestPoint(t *testing.T) {
+	s := &Segment[float64]{A: Point[float64]{0, 0}, B: Point[float64]{4, 0}}
+	assert.Equal(t, Point[float64]{2, 0}, s.ClosestPoint(&Point[float64]{2, 3}))
+	assert.Equal(t, Point[float64]{0, 0}, s.ClosestPoint(&Point[float64]{-1, 1}))
+	assert.Equal(t, Point[float64]{4, 0}, s.ClosestPoint(&Point[float64]{9, -1}))
+	assert.Equal(t, 9.0, s.DistanceToSqr(&Point[float64]{2, 3}))
+
+	point := &Segment[float64]{A: Point[float64]{1, 1}, B: Point[float64]{1, 1}}
+	assert.Equal(t, 2.0, point.DistanceToSqr(&Point[float64]{0, 0}))
+	assert.Equal(t, Rect[float64]{Min: Point[float64]{0, 0}, Max: Point[float64]{4, 0}}, s.Bounds())
+}
+
+func TestSegmentIndex(t *testing.T) {
+	idx := NewSegmentIndex[float32](0)
+	var segments []*Segment[float32]
+	for i := 0; i < 300; i++ {
+		a := Point[float32]{rand.Float32(), rand.Float32()}
+		s := &Segment[float32]{A: a, B: Point[float32]{a.X + rand.Float32()*0.1 - 0.05, a.Y + rand.Float32()*0.1 - 0.05}}
+		segments = append(segments, s)
+		idx.Insert(s)
+	}
+	for _, s := range segments[:30] {
+		s.B.X += 0.02
+		idx.Insert(s)
+	}
+	for _, s := range segments[30:60] {
+		assert.True(t, idx.Remove(s))
+	}
+	segments = segments[:0:0]
+	segments = append(segments, idx.Segments()...)
+	assert.Len(t, segments, 270)
+	assert.Equal(t, 270, idx.Len())
+
+	for _, p := range randomPoints(20) {
+		var expected []float32
+		for _, s := range segments {
+			if d := s.DistanceToSqr(p); d <= 0.1*0.1 {
+				expected = append(expected, d)
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+
+		found := idx.WithinRadius(p, 0.1)
+		assert.Len(t, found, len(expected))
+		for i, s := range found {
+			assert.Equal(t, expected[i], s.DistanceToSqr(p))
+		}
+
+		s, snapped, ok := idx.Snap(p, 0)
+		assert.True(t, ok)
+		assert.Equal(t, idx.NearestN(p, 1, 0)[0], s)
+		assert.Equal(t, s.ClosestPoint(p), snapped)
+	}
+
+	_, _, ok := NewSegmentIndex[float32](0).Snap(&Point[float32]{}, 1)
+	assert.False(t, ok)
+	assert.False(t, idx.Remove(&Segment[float32]{}))
+}
@@ -0,0 +1,74 @@
+package microspace
+
+import "sync/atomic"
+
+// Snapshot is an immutable copy of an Axdex, taken with Snapshot, which
+// may be queried from any number of goroutines while the index it was
+// taken from keeps changing. It holds copies of the points, so queries
+// return the copies, which can be mapped back with Origin.
+type Snapshot[T Float] struct {
+	index  *Axdex[T]
+	origin map[*Point[T]]*Point[T]
+}
+
+// Snapshot returns an immutable copy of the index as it is now. The copy
+// is already sorted, so queries against it never modify it.
+func (a *Axdex[T]) Snapshot() *Snapshot[T] {
+	clone, copyOf := a.clone(true)
+	s := &Snapshot[T]{index: clone, origin: make(map[*Point[T]]*Point[T], len(copyOf))}
+	for p, c := range copyOf {
+		s.origin[c] = p
+	}
+
+	return s
+}
+
+var (
+	_ Index[float32]         = new(Snapshot[float32])
+	_ NeighborIndex[float32] = new(Snapshot[float32])
+)
+
+// NearestN implements Index.NearestN
+func (s *Snapshot[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	return s.index.NearestN(p, n, max)
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (s *Snapshot[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	return s.index.NearestNWithDistance(p, n, max)
+}
+
+// Points implements Index.Points. The returned slice must not be modified.
+func (s *Snapshot[T]) Points() []*Point[T] {
+	return s.index.points
+}
+
+// Len returns the number of points in the snapshot.
+func (s *Snapshot[T]) Len() int {
+	return len(s.index.points)
+}
+
+// Origin returns the point in the original index that a point in the
+// snapshot was copied from, or nil if it isn't from the snapshot.
+func (s *Snapshot[T]) Origin(p *Point[T]) *Point[T] {
+	return s.origin[p]
+}
+
+// SnapshotHolder publishes snapshots to readers: the writer rebuilding
+// an index stores a new snapshot with Swap whenever it's complete, and
+// readers Load the latest one, never observing an index partway through
+// being built. The zero value holds no snapshot.
+type SnapshotHolder[T Float] struct {
+	current atomic.Pointer[Snapshot[T]]
+}
+
+// Load returns the latest snapshot, or nil if none has been stored.
+func (h *SnapshotHolder[T]) Load() *Snapshot[T] {
+	return h.current.Load()
+}
+
+// Swap stores the snapshot as the latest, returning the previous one.
+// Readers which loaded the previous snapshot may keep using it.
+func (h *SnapshotHolder[T]) Swap(s *Snapshot[T]) *Snapshot[T] {
+	return h.current.Swap(s)
+}
@@ -0,0 +1,57 @@
+package microspace
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	idx := NewAxdex[float64](3)
+	a, b := &Point[float64]{1, 1}, &Point[float64]{5, 5}
+	idx.Insert(a)
+	idx.Insert(b)
+
+	snap := idx.Snapshot()
+	idx.Update(a, 10, 10)
+	idx.Insert(&Point[float64]{0, 0})
+
+	assert.Equal(t, 2, snap.Len())
+	found := snap.NearestN(&Point[float64]{0, 0}, 1, 0)
+	assert.Equal(t, []*Point[float64]{{1, 1}}, found)
+	assert.True(t, snap.Origin(found[0]) == a)
+	assert.Nil(t, snap.Origin(a))
+
+	near := snap.NearestNWithDistance(&Point[float64]{5, 5}, -1, 0)
+	assert.Equal(t, []Neighbor[float64]{{snap.Points()[1], 0}, {snap.Points()[0], 32}}, near)
+}
+
+func TestSnapshotHolder(t *testing.T) {
+	var holder SnapshotHolder[float32]
+	assert.Nil(t, holder.Load())
+
+	idx := NewAxdex[float32](0)
+	holder.Swap(idx.Snapshot())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, p := range randomPoints(100) {
+				snap := holder.Load()
+				assert.Len(t, snap.NearestN(p, 1, 0), min(1, snap.Len()))
+			}
+		}()
+	}
+
+	for _, p := range randomPoints(200) {
+		idx.Insert(p)
+		idx.NearestN(p, 1, 0)
+		assert.NotNil(t, holder.Swap(idx.Snapshot()))
+	}
+	wg.Wait()
+
+	assert.Equal(t, 200, holder.Load().Len())
+}
@@ -0,0 +1,27 @@
+package microspace
+
+// NearestNSpread returns up to `n` points near p, within `max`, which are
+// also at least `minSeparation` apart from each other. Points are chosen
+// greedily in order of increasing distance, skipping any point too close
+// to one already chosen, so the nearest point is always included. `n` may
+// be set to -1 to pick as many points as fit. The point doesn't need to be
+// in the index.
+func (a *Axdex[T]) NearestNSpread(p *Point[T], n int, max, minSeparation T) []*Point[T] {
+	if n == 0 {
+		return nil
+	}
+
+	var results []*Point[T]
+	a.browse(p, max, func(q *Point[T], _ T) bool {
+		for _, other := range results {
+			if q.DistanceToSqr(other) < minSeparation*minSeparation {
+				return true
+			}
+		}
+
+		results = append(results, q)
+		return len(results) != n
+	})
+
+	return results
+}
@@ -0,0 +1,22 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNSpread(t *testing.T) {
+	idx := NewAxdex[float32](20)
+	for i := 0; i < 20; i++ {
+		idx.Insert(&Point[float32]{float32(i), 0})
+	}
+	points := idx.Points()
+	p := &Point[float32]{0, 0}
+
+	assert.Equal(t, points[:3], idx.NearestNSpread(p, 3, 100, 1))
+	assert.Equal(t, []*Point[float32]{points[0], points[3], points[6]}, idx.NearestNSpread(p, 3, 100, 2.5))
+	assert.Equal(t, []*Point[float32]{points[0], points[5], points[10], points[15]}, idx.NearestNSpread(p, -1, 100, 5))
+	assert.Equal(t, []*Point[float32]{points[0], points[5]}, idx.NearestNSpread(p, -1, 7, 5))
+	assert.Empty(t, idx.NearestNSpread(p, 0, 100, 5))
+}
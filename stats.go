@@ -0,0 +1,116 @@
+package microspace
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// histogramBuckets is the number of buckets in a Histogram. Values below
+// four get a bucket each, and every power of two above that is split into
+// four buckets, so bucket bounds are within 25% of any recorded value.
+const histogramBuckets = 4 * 63
+
+// Histogram is a snapshot of a distribution of recorded values.
+type Histogram struct {
+	Counts [histogramBuckets]uint64
+}
+
+// histogramBucket returns the bucket the value is recorded in.
+func histogramBucket(v uint64) int {
+	if v < 4 {
+		return int(v)
+	}
+
+	e := bits.Len64(v) - 1
+	return 4*(e-1) + int(v>>(e-2)&3)
+}
+
+// histogramUpper returns the largest value recorded in the bucket.
+func histogramUpper(bucket int) uint64 {
+	if bucket < 4 {
+		return uint64(bucket)
+	}
+
+	e := bucket/4 + 1
+	lower := uint64(4+bucket%4) << (e - 2)
+	return lower + 1<<(e-2) - 1
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() uint64 {
+	var count uint64
+	for _, c := range h.Counts {
+		count += c
+	}
+
+	return count
+}
+
+// Quantile returns an upper bound of the value at the quantile q, between
+// 0 and 1, so Quantile(0.99) is the 99th percentile.
+func (h *Histogram) Quantile(q float64) uint64 {
+	total := h.Count()
+	if total == 0 {
+		return 0
+	}
+
+	target := uint64(q*float64(total) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+
+	var seen uint64
+	for bucket, c := range h.Counts {
+		if seen += c; seen >= target {
+			return histogramUpper(bucket)
+		}
+	}
+
+	return histogramUpper(histogramBuckets - 1)
+}
+
+// Stats is a snapshot of the queries run against an index.
+type Stats struct {
+	// Latency is the distribution of query times, in nanoseconds.
+	Latency Histogram
+	// Candidates is the distribution of the number of points examined
+	// by each query.
+	Candidates Histogram
+}
+
+// queryStats records query statistics. It's safe to use concurrently.
+type queryStats struct {
+	latency    [histogramBuckets]atomic.Uint64
+	candidates [histogramBuckets]atomic.Uint64
+}
+
+// record adds a query to the statistics.
+func (s *queryStats) record(latency time.Duration, candidates int) {
+	s.latency[histogramBucket(uint64(latency))].Add(1)
+	s.candidates[histogramBucket(uint64(candidates))].Add(1)
+}
+
+// EnableStats starts recording the latency and number of candidates
+// examined of NearestN and NearestNAt queries, retrievable with Stats.
+// It must be called before queries start.
+func (a *Axdex[T]) EnableStats() {
+	if a.stats == nil {
+		a.stats = &queryStats{}
+	}
+}
+
+// Stats returns a snapshot of the statistics recorded since EnableStats.
+func (a *Axdex[T]) Stats() Stats {
+	var s Stats
+	if a.stats == nil {
+		return s
+	}
+
+	for i := range s.Latency.Counts {
+		s.Latency.Counts[i] = a.stats.latency[i].Load()
+		s.Candidates.Counts[i] = a.stats.candidates[i].Load()
+	}
+
+	return s
+}
@@ -0,0 +1,35 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	for _, v := range []uint64{0, 1, 3, 4, 5, 7, 8, 100, 1000, 123456789, 1 << 63} {
+		bucket := histogramBucket(v)
+		assert.True(t, histogramUpper(bucket) >= v)
+		if bucket > 0 {
+			assert.True(t, histogramUpper(bucket-1) < v)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	idx := generateIndex(100)
+	stats := idx.Stats()
+	assert.Equal(t, uint64(0), stats.Latency.Count())
+
+	idx.EnableStats()
+	for _, p := range idx.Points()[:10] {
+		idx.NearestN(p, 3, 0.25)
+	}
+	idx.NearestNAt(0, &Point[float32]{0.5, 0.5}, -1, 2)
+
+	stats = idx.Stats()
+	assert.Equal(t, uint64(11), stats.Latency.Count())
+	assert.Equal(t, uint64(11), stats.Candidates.Count())
+	assert.True(t, stats.Candidates.Quantile(1) >= 100)
+	assert.True(t, stats.Candidates.Quantile(0.5) < 100)
+}
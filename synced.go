@@ -0,0 +1,139 @@
+package microspace
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Synced wraps an index to make it safe for concurrent use: any number of
+// queries run in parallel, while writes are serialized and exclude them.
+// Queries on an Axdex which find it unsorted, such as the first query
+// after a batch of inserts, briefly take the write lock to sort it, so the
+// lazy sort never races. Queries on other indexes must only read from
+// them, as those of the other indexes in this package do.
+type Synced[T Float] struct {
+	mu    sync.RWMutex
+	index Index[T]
+	axdex *Axdex[T]
+}
+
+// NewSynced returns a concurrency-safe wrapper over the index, which
+// shouldn't be used directly anymore.
+func NewSynced[T Float](index Index[T]) *Synced[T] {
+	s := &Synced[T]{index: index}
+	s.axdex, _ = index.(*Axdex[T])
+	return s
+}
+
+var (
+	_ InsertIndex[float32]   = new(Synced[float32])
+	_ NeighborIndex[float32] = new(Synced[float32])
+)
+
+// Insert adds a point to the index. It panics if the index has no Insert
+// method.
+func (s *Synced[T]) Insert(p *Point[T]) {
+	index, ok := s.index.(InsertIndex[T])
+	if !ok {
+		panic(fmt.Sprintf("Cannot insert into an index of type %T.", s.index))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index.Insert(p)
+}
+
+// Remove removes the point from the index, returning false if it wasn't
+// in the index. It panics if the index has no Remove method.
+func (s *Synced[T]) Remove(p *Point[T]) bool {
+	index, ok := s.index.(interface{ Remove(p *Point[T]) bool })
+	if !ok {
+		panic(fmt.Sprintf("Cannot remove from an index of type %T.", s.index))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return index.Remove(p)
+}
+
+// Update moves the point to (x, y), returning false if it isn't in the
+// index. Points in the index must only be moved through Update, never by
+// writing to them directly. Indexes without an Update method of their own
+// have the point removed and inserted again, and it panics if they can't
+// do either.
+func (s *Synced[T]) Update(p *Point[T], x, y T) bool {
+	if index, ok := s.index.(interface {
+		Update(p *Point[T], x, y T) bool
+	}); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return index.Update(p, x, y)
+	}
+
+	index, ok := s.index.(interface {
+		InsertIndex[T]
+		Remove(p *Point[T]) bool
+	})
+	if !ok {
+		panic(fmt.Sprintf("Cannot update an index of type %T.", s.index))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !index.Remove(p) {
+		return false
+	}
+	p.X, p.Y = x, y
+	index.Insert(p)
+	return true
+}
+
+// NearestN implements Index.NearestN
+func (s *Synced[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	s.rlock()
+	defer s.mu.RUnlock()
+	return s.index.NearestN(p, n, max)
+}
+
+// NearestNWithDistance implements NeighborIndex.NearestNWithDistance
+func (s *Synced[T]) NearestNWithDistance(p *Point[T], n int, max T) []Neighbor[T] {
+	s.rlock()
+	defer s.mu.RUnlock()
+	return NearestNWithDistance(s.index, p, n, max)
+}
+
+// Points implements Index.Points, returning a copy of the points as they
+// were at the time of the call.
+func (s *Synced[T]) Points() []*Point[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Point[T](nil), s.index.Points()...)
+}
+
+// rlock takes the read lock, first sorting an Axdex under the write lock
+// if needed, so that queries holding the read lock don't modify it.
+func (s *Synced[T]) rlock() {
+	for {
+		s.mu.RLock()
+		if s.sorted() {
+			return
+		}
+		s.mu.RUnlock()
+
+		s.mu.Lock()
+		if !s.axdex.axis.sorted {
+			s.axdex.axis.runSort()
+		}
+		if s.axdex.cross != nil && !s.axdex.cross.sorted {
+			s.axdex.cross.runSort()
+		}
+		s.mu.Unlock()
+	}
+}
+
+// sorted returns true if the index has no axes queries read from, or if
+// they're all sorted.
+func (s *Synced[T]) sorted() bool {
+	a := s.axdex
+	return a == nil || (a.axis.sorted && (a.cross == nil || a.cross.sorted))
+}
@@ -0,0 +1,70 @@
+package microspace
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynced(t *testing.T) {
+	idx := NewSynced(NewAxdex[float64](0))
+	a, b := &Point[float64]{1, 1}, &Point[float64]{5, 5}
+	idx.Insert(a)
+	idx.Insert(b)
+
+	assert.Equal(t, []*Point[float64]{a}, idx.NearestN(&Point[float64]{0, 0}, 1, 0))
+	assert.True(t, idx.Update(a, 10, 10))
+	assert.Equal(t, []Neighbor[float64]{{b, 50}}, idx.NearestNWithDistance(&Point[float64]{0, 0}, 1, 0))
+	assert.True(t, idx.Remove(b))
+	assert.Equal(t, []*Point[float64]{a}, idx.Points())
+}
+
+func TestSyncedConcurrent(t *testing.T) {
+	idx := NewSynced(NewAxdex[float32](0))
+	for _, p := range randomPoints(500) {
+		idx.Insert(p)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for _, p := range randomPoints(50) {
+				assert.Len(t, idx.NearestN(p, 5, 0), 5)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, p := range randomPoints(50) {
+				idx.Insert(p)
+				idx.Update(p, p.Y, p.X)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, idx.Points(), 900)
+}
+
+func TestSyncedIndex(t *testing.T) {
+	idx := NewSynced[float64](NewGridIndex[float64](1))
+	a, b := &Point[float64]{1, 1}, &Point[float64]{5, 5}
+	idx.Insert(a)
+	idx.Insert(b)
+
+	// The grid has no Update, so the point is moved by reinserting it.
+	assert.True(t, idx.Update(a, 10, 10))
+	assert.Equal(t, Point[float64]{10, 10}, *a)
+	assert.Equal(t, []Neighbor[float64]{{b, 50}}, idx.NearestNWithDistance(&Point[float64]{0, 0}, 1, 0))
+	assert.True(t, idx.Remove(b))
+	assert.False(t, idx.Update(b, 0, 0))
+	assert.Equal(t, []*Point[float64]{a}, idx.Points())
+
+	tree := NewSynced[float64](NewKDTree([]*Point[float64]{a}))
+	assert.Equal(t, []*Point[float64]{a}, tree.NearestN(b, 1, 0))
+	assert.Panics(t, func() { tree.Insert(b) })
+	assert.Panics(t, func() { tree.Remove(a) })
+	assert.Panics(t, func() { tree.Update(a, 0, 0) })
+}
@@ -0,0 +1,41 @@
+package microspace
+
+import "time"
+
+// tuneRounds is the number of times Tune runs the sample queries against
+// each candidate, keeping the fastest round to reduce noise.
+const tuneRounds = 3
+
+// Tune benchmarks the sample queries, asking for the `n` nearest
+// neighbors within `max`, against an Axdex over the points built on each
+// candidate axis, and returns the axis which answered them fastest. The
+// sample queries don't need to be among the points, so they should be
+// taken from the queries the application actually runs.
+func Tune[T Float](points, queries []*Point[T], n int, max T) Axis {
+	best, bestTime := AxisX, time.Duration(-1)
+	for _, axis := range []Axis{AxisX, AxisY} {
+		idx := NewAxdexOnAxis[T](uint(len(points)), axis)
+		for _, p := range points {
+			idx.Insert(p)
+		}
+		idx.axis.runSort()
+
+		taken := time.Duration(-1)
+		for round := 0; round < tuneRounds; round++ {
+			start := time.Now()
+			for _, q := range queries {
+				idx.NearestN(q, n, max)
+			}
+
+			if elapsed := time.Since(start); taken < 0 || elapsed < taken {
+				taken = elapsed
+			}
+		}
+
+		if bestTime < 0 || taken < bestTime {
+			best, bestTime = axis, taken
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,25 @@
+package microspace
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTune(t *testing.T) {
+	points := []*Point[float32]{}
+	for i := 0; i < 5000; i++ {
+		points = append(points, &Point[float32]{rand.Float32() * 0.001, rand.Float32()})
+	}
+
+	queries := points[:200]
+	assert.Equal(t, AxisY, Tune(points, queries, 3, 0.05))
+
+	idx := NewAxdexOnAxis[float32](uint(len(points)), AxisY)
+	for _, p := range points {
+		idx.Insert(p)
+	}
+	assert.Equal(t, points[0], idx.NearestN(points[0], 1, 0.05)[0])
+	assert.Equal(t, points[0].Y, idx.axis.ValueFor(points[0]))
+}
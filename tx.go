@@ -0,0 +1,189 @@
+package microspace
+
+import "sync"
+
+// Transactional wraps an Axdex so that groups of inserts, removes and
+// moves can be made visible to queries atomically: a query sees either
+// none or all of a transaction's changes. Queries may run concurrently
+// with each other and with transactions being built and committed.
+type Transactional[T Float] struct {
+	mu    sync.RWMutex
+	index *Axdex[T]
+
+	// commitMu serializes commits.
+	commitMu sync.Mutex
+}
+
+// NewTransactional returns a transactional wrapper over the index, which
+// must not be used directly from then on.
+func NewTransactional[T Float](index *Axdex[T]) *Transactional[T] {
+	if !index.axis.sorted {
+		index.axis.runSort()
+	}
+	if index.cross != nil && !index.cross.sorted {
+		index.cross.runSort()
+	}
+
+	return &Transactional[T]{index: index}
+}
+
+var _ Index[float32] = new(Transactional[float32])
+
+// NearestN implements Index.NearestN
+func (x *Transactional[T]) NearestN(p *Point[T], n int, max T) []*Point[T] {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.index.NearestN(p, n, max)
+}
+
+// Points implements Index.Points
+func (x *Transactional[T]) Points() []*Point[T] {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.index.Points()
+}
+
+// applyBounds enforces the index's world bounds on a point being inserted
+// or moved.
+func (x *Transactional[T]) applyBounds(p *Point[T]) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	x.index.applyBounds(p)
+}
+
+// Begin starts a new transaction. Nothing it does is visible to queries
+// until it's committed.
+func (x *Transactional[T]) Begin() *Tx[T] {
+	return &Tx[T]{
+		owner:    x,
+		removes:  map[*Point[T]]bool{},
+		moves:    map[*Point[T]]Point[T]{},
+		expected: map[*Point[T]]uint64{},
+	}
+}
+
+// Tx is a group of changes to a Transactional index.
+type Tx[T Float] struct {
+	owner   *Transactional[T]
+	inserts []*Point[T]
+	removes map[*Point[T]]bool
+	moves   map[*Point[T]]Point[T]
+	done    bool
+
+	// expected holds the versions points must be at for the commit to
+	// succeed.
+	expected map[*Point[T]]uint64
+}
+
+// Insert adds a point to the index when the transaction is committed. The
+// index's world bounds are applied to the point right away.
+func (tx *Tx[T]) Insert(p *Point[T]) {
+	tx.owner.applyBounds(p)
+	tx.inserts = append(tx.inserts, p)
+}
+
+// Remove removes a point from the index when the transaction is committed.
+func (tx *Tx[T]) Remove(p *Point[T]) {
+	tx.removes[p] = true
+}
+
+// Move moves a point in the index to a new position when the transaction
+// is committed. The point itself isn't changed before then, but the
+// index's world bounds are applied to the new position right away.
+func (tx *Tx[T]) Move(p *Point[T], to Point[T]) {
+	tx.owner.applyBounds(&to)
+	tx.moves[p] = to
+}
+
+// MoveIfVersion is like Move, but the commit fails with a version conflict
+// unless the point is still at the provided version, meaning nothing else
+// has moved it since that version was read.
+func (tx *Tx[T]) MoveIfVersion(p *Point[T], to Point[T], version uint64) {
+	tx.Move(p, to)
+	tx.expected[p] = version
+}
+
+// Rollback discards the transaction.
+func (tx *Tx[T]) Rollback() {
+	tx.done = true
+}
+
+// Commit applies the transaction's changes, bumping the versions of moved
+// points. The new index is built while queries keep running against the
+// old one, and queries are only paused while moved points are updated and
+// the new index is swapped in. If any point isn't at its expected version,
+// nothing is applied and ErrVersionConflict is returned.
+func (tx *Tx[T]) Commit() error {
+	if tx.done {
+		panic("Cannot commit a transaction that has already finished.")
+	}
+	tx.done = true
+
+	x := tx.owner
+	x.commitMu.Lock()
+	defer x.commitMu.Unlock()
+
+	// Only commits write the index pointer, and they're serialized, so
+	// it's safe to read here without the lock.
+	old := x.index
+	for p, version := range tx.expected {
+		if old.VersionOf(p) != version {
+			return ErrVersionConflict
+		}
+	}
+
+	next := newAxdex(uint(len(old.points)+len(tx.inserts)), old.along, old.axis.value)
+	next.bounds, next.boundsMode, next.stats = old.bounds, old.boundsMode, old.stats
+	next.maxCandidates, next.dual, next.metric = old.maxCandidates, old.dual, old.metric
+	if old.cross != nil {
+		next.cross = next.newCrossAxis(uint(cap(next.points)))
+	}
+
+	add := func(p *Point[T]) {
+		if tx.removes[p] {
+			return
+		}
+
+		pos := *p
+		if to, ok := tx.moves[p]; ok {
+			pos = to
+		}
+
+		next.points = append(next.points, p)
+		next.axis.data = append(next.axis.data, axisPoint[T]{p: p, value: next.axis.ValueFor(&pos)})
+		if next.cross != nil {
+			next.cross.data = append(next.cross.data, axisPoint[T]{p: p, value: next.cross.ValueFor(&pos)})
+		}
+		if v, ok := old.velocities[p]; ok {
+			next.SetVelocity(p, v)
+		}
+		if v, ok := old.versions[p]; ok {
+			if next.versions == nil {
+				next.versions = map[*Point[T]]uint64{}
+			}
+			next.versions[p] = v
+		}
+	}
+	for _, p := range old.points {
+		add(p)
+	}
+	for _, p := range tx.inserts {
+		add(p)
+	}
+	next.axis.runSort()
+	if next.cross != nil {
+		next.cross.runSort()
+	}
+
+	x.mu.Lock()
+	for p, to := range tx.moves {
+		if !tx.removes[p] {
+			*p = to
+			next.bumpVersion(p)
+		}
+	}
+	x.index = next
+	x.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactional(t *testing.T) {
+	a, b, c := &Point[float32]{0, 0}, &Point[float32]{1, 0}, &Point[float32]{2, 0}
+	idx := NewAxdex[float32](3)
+	idx.Insert(a)
+	idx.Insert(b)
+	idx.Insert(c)
+	x := NewTransactional(idx)
+
+	d := &Point[float32]{10, 0}
+	tx := x.Begin()
+	tx.Insert(d)
+	tx.Remove(b)
+	tx.Move(c, Point[float32]{11, 0})
+	assert.Equal(t, []*Point[float32]{a, b, c}, x.Points())
+	assert.Equal(t, Point[float32]{2, 0}, *c)
+
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, []*Point[float32]{a, c, d}, x.Points())
+	assert.Equal(t, Point[float32]{11, 0}, *c)
+	assert.Equal(t, []*Point[float32]{d, c}, x.NearestN(d, 2, 5))
+	assert.Panics(t, func() { tx.Commit() })
+
+	tx = x.Begin()
+	tx.Remove(a)
+	tx.Rollback()
+	assert.Len(t, x.Points(), 3)
+}
+
+func TestTransactionalCrossAxis(t *testing.T) {
+	a, b := &Point[float32]{0, 0}, &Point[float32]{0, 5}
+	idx := NewAxdex[float32](2)
+	idx.Insert(a)
+	idx.Insert(b)
+	idx.EnableDualAxis()
+	x := NewTransactional(idx)
+
+	c := &Point[float32]{0, 9}
+	tx := x.Begin()
+	tx.Insert(c)
+	tx.Move(a, Point[float32]{0, 10})
+	assert.NoError(t, tx.Commit())
+
+	assert.Equal(t, []*Point[float32]{a, c, b}, x.NearestN(&Point[float32]{0, 11}, 3, 0))
+	assert.Equal(t, []*Point[float32]{a}, x.index.NearestNWith(&Point[float32]{0, 11}, 1, 0, QueryOptions[float32]{CrossAxis: true}))
+}
+
+func TestTransactionalBounds(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	idx.SetBounds(Rect[float32]{Max: Point[float32]{10, 10}}, BoundsClamp)
+	x := NewTransactional(idx)
+
+	p := &Point[float32]{20, 5}
+	tx := x.Begin()
+	tx.Insert(p)
+	tx.Move(p, Point[float32]{-5, 5})
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, Point[float32]{0, 5}, *p)
+
+	rejecting := NewAxdex[float32](0)
+	rejecting.SetBounds(Rect[float32]{Max: Point[float32]{10, 10}}, BoundsReject)
+	x = NewTransactional(rejecting)
+	assert.Panics(t, func() { x.Begin().Insert(&Point[float32]{20, 5}) })
+	assert.Panics(t, func() { x.Begin().Move(p, Point[float32]{20, 5}) })
+}
@@ -0,0 +1,103 @@
+package microspace
+
+// Update moves the point to (x, y), returning false if it isn't in the
+// index. Rather than re-sorting the whole axis, the point is shifted
+// along it to its new place, which is cheap for the small moves points
+// typically make between frames. The new position is subject to the
+// index's bounds like inserted points, and the point's version is bumped.
+func (a *Axdex[T]) Update(p *Point[T], x, y T) bool {
+	i := a.axis.IndexFor(p)
+	if i == -1 {
+		return false
+	}
+
+	to := Point[T]{X: x, Y: y}
+	a.applyBounds(&to)
+	if to == *p {
+		return true
+	}
+
+	*p = to
+	a.bumpVersion(p)
+	a.axis.shift(i)
+	if a.cross != nil {
+		a.cross.shift(a.cross.IndexFor(p))
+	}
+
+	return true
+}
+
+// shift moves the point in slot `i` of the sorted axis to its place for
+// its current value by shifting the points between its old and new places.
+func (a *axis[T]) shift(i int) {
+	data := a.data
+	moved := axisPoint[T]{p: data[i].p, value: a.ValueFor(data[i].p)}
+	for ; i > 0 && data[i-1].value > moved.value; i-- {
+		data[i] = data[i-1]
+		a.indexed[data[i].p] = i
+	}
+	for ; i < len(data)-1 && data[i+1].value < moved.value; i++ {
+		data[i] = data[i+1]
+		a.indexed[data[i].p] = i
+	}
+	data[i] = moved
+	a.indexed[moved.p] = i
+}
+
+// repairBudget is how many shifts per point UpdateAll's insertion sort
+// may make before it gives up and sorts the axis from scratch.
+const repairBudget = 4
+
+// UpdateAll calls fn with every point in the index, which may move the
+// point, such as to step a simulation. The world bounds are applied to
+// each moved point, and its version is bumped. When most points only move
+// a little the axis stays nearly sorted, so it's repaired with an
+// insertion sort, which is much cheaper than sorting it again or
+// rebuilding the index. fn mustn't insert or remove points.
+func (a *Axdex[T]) UpdateAll(fn func(*Point[T])) {
+	for i := range a.axis.data {
+		ap := &a.axis.data[i]
+		before := *ap.p
+		fn(ap.p)
+		if *ap.p != before {
+			a.applyBounds(ap.p)
+			a.bumpVersion(ap.p)
+		}
+		ap.value = a.axis.ValueFor(ap.p)
+	}
+
+	a.axis.repair()
+	if a.cross != nil {
+		for i := range a.cross.data {
+			a.cross.data[i].value = a.cross.ValueFor(a.cross.data[i].p)
+		}
+		a.cross.repair()
+	}
+}
+
+// repair sorts the axis after the values of its points have changed,
+// using an insertion sort on its settled points when they're nearly in
+// order and falling back to a full sort when they're not. Points inserted
+// since the last sort are merged in after.
+func (a *axis[T]) repair() {
+	data := a.data[:a.settled]
+	shifts, budget := 0, repairBudget*len(data)
+	for i := 1; i < len(data) && shifts <= budget; i++ {
+		moved := data[i]
+		j := i
+		for ; j > 0 && data[j-1].value > moved.value; j-- {
+			data[j] = data[j-1]
+		}
+		data[j] = moved
+		shifts += i - j
+	}
+
+	switch {
+	case shifts > budget:
+		a.settled = 0
+	case a.settled == len(a.data):
+		a.markSorted()
+		return
+	}
+	a.runSort()
+}
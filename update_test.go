@@ -0,0 +1,110 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAxdexUpdate(t *testing.T) {
+	idx := generateIndex(200)
+	for i := 0; i < 500; i++ {
+		p := idx.Points()[rand.Intn(200)]
+		assert.True(t, idx.Update(p, p.X+rand.Float32()*0.2-0.1, rand.Float32()))
+	}
+
+	assert.True(t, sort.IsSorted(idx.axis.data))
+	for i, ap := range idx.axis.data {
+		assert.Equal(t, i, idx.axis.IndexFor(ap.p))
+		assert.Equal(t, ap.p.X, ap.value)
+	}
+
+	p := idx.Points()[0]
+	version := idx.VersionOf(p)
+	assert.True(t, idx.Update(p, 5, 5))
+	assert.Equal(t, Point[float32]{5, 5}, *p)
+	assert.Equal(t, version+1, idx.VersionOf(p))
+	assert.Equal(t, p, idx.axis.data[len(idx.axis.data)-1].p)
+	assert.Equal(t, []*Point[float32]{p}, idx.NearestNAt(0, &Point[float32]{4.9, 5}, 3, 1))
+
+	assert.False(t, idx.Update(&Point[float32]{}, 1, 1))
+}
+
+func TestAxdexUpdateBounds(t *testing.T) {
+	idx := NewAxdex[float32](1)
+	idx.SetBounds(Rect[float32]{Max: Point[float32]{10, 10}}, BoundsReject)
+	p := &Point[float32]{1, 1}
+	idx.Insert(p)
+
+	assert.Panics(t, func() { idx.Update(p, 11, 1) })
+	assert.Equal(t, Point[float32]{1, 1}, *p)
+}
+
+func TestAxdexUpdateAll(t *testing.T) {
+	idx := NewAxdex[float32](0)
+	idx.SetBounds(Rect[float32]{Max: Point[float32]{1, 1}}, BoundsClamp)
+	for _, p := range randomPoints(300) {
+		idx.Insert(p)
+	}
+	idx.EnableDualAxis()
+	finalizeIndex(idx)
+
+	// Jitter every point a little, then scatter them, which falls back to
+	// a full sort. Points inserted in between are merged in.
+	for _, spread := range []float32{0.01, 2} {
+		idx.UpdateAll(func(p *Point[float32]) {
+			p.X += (rand.Float32() - 0.5) * spread
+			p.Y += (rand.Float32() - 0.5) * spread
+		})
+		idx.Insert(&Point[float32]{rand.Float32(), rand.Float32()})
+
+		assertExact(t, idx, randomPoints(10), 5, 2)
+		for _, p := range idx.Points() {
+			assert.True(t, idx.InBounds(p))
+		}
+	}
+
+	assert.True(t, sort.IsSorted(idx.axis.data))
+	assert.True(t, sort.IsSorted(idx.cross.data))
+	assert.Len(t, idx.cross.data, 302)
+
+	p := idx.Points()[0]
+	version := idx.VersionOf(p)
+	idx.UpdateAll(func(q *Point[float32]) {
+		if q == p {
+			q.X = 0.5
+		}
+	})
+	assert.Equal(t, version+1, idx.VersionOf(p))
+}
+
+func benchmarkJitter(b *testing.B, step func(*Axdex[float32], func(*Point[float32]))) {
+	idx := generateIndex(100000)
+	finalizeIndex(idx)
+	jitter := func(p *Point[float32]) { p.X += (rand.Float32() - 0.5) * 0.0001 }
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		step(idx, jitter)
+	}
+}
+
+func BenchmarkUpdateAll(b *testing.B) {
+	benchmarkJitter(b, (*Axdex[float32]).UpdateAll)
+}
+
+func BenchmarkUpdateAllRebuild(b *testing.B) {
+	benchmarkJitter(b, func(idx *Axdex[float32], fn func(*Point[float32])) {
+		points := idx.Points()
+		for _, p := range points {
+			fn(p)
+		}
+		rebuilt := NewAxdex[float32](uint(len(points)))
+		for _, p := range points {
+			rebuilt.Insert(p)
+		}
+		finalizeIndex(rebuilt)
+	})
+}
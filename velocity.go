@@ -0,0 +1,93 @@
+package microspace
+
+import (
+	"math"
+	"time"
+)
+
+// SetVelocity attaches a velocity to a point in the index, given in
+// coordinate units per unit of time. Velocities are used by NearestNAt
+// to extrapolate positions. Setting a zero velocity detaches it again.
+func (a *Axdex[T]) SetVelocity(p *Point[T], v Point[T]) {
+	if v.X == 0 && v.Y == 0 {
+		delete(a.velocities, p)
+		return
+	}
+
+	if a.velocities == nil {
+		a.velocities = map[*Point[T]]Point[T]{}
+	}
+
+	a.velocities[p] = v
+	if speed := v.X*v.X + v.Y*v.Y; speed > a.maxSpeedSqr {
+		a.maxSpeedSqr = speed
+	}
+}
+
+// VelocityOf returns the velocity attached to the point, or a zero
+// velocity if it has none.
+func (a *Axdex[T]) VelocityOf(p *Point[T]) Point[T] {
+	return a.velocities[p]
+}
+
+// PositionAt returns the position of the point extrapolated `t` units of
+// time into the future, p + v·t. Negative times extrapolate backwards.
+func (a *Axdex[T]) PositionAt(p *Point[T], t T) Point[T] {
+	v := a.velocities[p]
+	return Point[T]{X: p.X + v.X*t, Y: p.Y + v.Y*t}
+}
+
+// NearestNAt is like NearestN, but evaluates the query against the
+// positions every point will have `t` units of time from now, including
+// the query point itself if it has a velocity. Unlike NearestN the query
+// point does not need to be in the index, and results are never further
+// than `max` from the extrapolated query position.
+func (a *Axdex[T]) NearestNAt(t T, p *Point[T], n int, max T) []*Point[T] {
+	max = searchRadius(max)
+	if n == -1 || n > len(a.points) {
+		n = len(a.points)
+	}
+	if n <= 0 || len(a.points) == 0 {
+		return nil
+	}
+
+	var scanned int
+	if a.stats != nil {
+		start := time.Now()
+		defer func() { a.stats.record(time.Since(start), scanned) }()
+	}
+
+	center := a.PositionAt(p, t)
+	results := newNeighborList[T](n)
+
+	// Points were sorted by their current position. After `t` units of
+	// time no point can have drifted along the axis by more than the
+	// fastest speed times t, so we widen the search band by that slack.
+	slack := T(math.Sqrt(float64(a.maxSpeedSqr))) * t
+	if slack < 0 {
+		slack = -slack
+	}
+
+	// Always visit whichever side is closer along the axis. Once the
+	// closer side can no longer contain a viable point, neither can the
+	// other, and we're done.
+	c := a.axis.cursor(a.axis.ValueFor(&center))
+	for {
+		i, gap, ok := c.Next()
+		if !ok {
+			break
+		}
+		if gap -= slack; gap > 0 && (gap > max || (results.Full() && gap*gap >= results.Worst())) {
+			break
+		}
+
+		scanned++
+		pt := a.axis.data[i].p
+		pos := a.PositionAt(pt, t)
+		if d := pos.DistanceToSqr(&center); d <= max*max {
+			results.Insert(pt, d)
+		}
+	}
+
+	return results.Sorted()
+}
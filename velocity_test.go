@@ -0,0 +1,68 @@
+package microspace
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNAt(t *testing.T) {
+	count := 200
+	tr := NewAxdex[float32](uint(count))
+
+	points := []*Point[float32]{}
+	for i := 0; i < count; i++ {
+		p := &Point[float32]{rand.Float32(), rand.Float32()}
+		points = append(points, p)
+		tr.Insert(p)
+		if i%3 != 0 {
+			tr.SetVelocity(p, Point[float32]{rand.Float32() - 0.5, rand.Float32() - 0.5})
+		}
+	}
+
+	for _, when := range []float32{0, 0.1, 0.5, -0.25} {
+		for _, p := range points[:20] {
+			center := tr.PositionAt(p, when)
+			expected := []*Point[float32]{}
+			for _, other := range points {
+				pos := tr.PositionAt(other, when)
+				if pos.DistanceToSqr(&center) <= 0.3*0.3 {
+					expected = append(expected, other)
+				}
+			}
+			sort.Slice(expected, func(i, j int) bool {
+				a, b := tr.PositionAt(expected[i], when), tr.PositionAt(expected[j], when)
+				return a.DistanceToSqr(&center) < b.DistanceToSqr(&center)
+			})
+			if len(expected) > 5 {
+				expected = expected[:5]
+			}
+
+			results := tr.NearestNAt(when, p, 5, 0.3)
+			assert.Equal(t, len(expected), len(results))
+			for i := range results {
+				a, b := tr.PositionAt(results[i], when), tr.PositionAt(expected[i], when)
+				assert.InDelta(t, b.DistanceToSqr(&center), a.DistanceToSqr(&center), 1e-6)
+			}
+		}
+	}
+}
+
+func TestNearestNAtUnindexedPoint(t *testing.T) {
+	tr := NewAxdex[float32](3)
+	a, b, c := &Point[float32]{0, 0}, &Point[float32]{10, 0}, &Point[float32]{20, 0}
+	tr.Insert(a)
+	tr.Insert(b)
+	tr.Insert(c)
+	tr.SetVelocity(c, Point[float32]{-8, 0})
+
+	missile := &Point[float32]{14, 0}
+	assert.Equal(t, []*Point[float32]{b, c}, tr.NearestNAt(0, missile, 2, 100))
+	assert.Equal(t, []*Point[float32]{c, b}, tr.NearestNAt(1, missile, 2, 100))
+	assert.Equal(t, []*Point[float32]{c}, tr.NearestNAt(1, missile, 2, 3))
+
+	tr.SetVelocity(c, Point[float32]{})
+	assert.Equal(t, Point[float32]{}, tr.VelocityOf(c))
+}
@@ -0,0 +1,56 @@
+package microspace
+
+import "errors"
+
+// ErrVersionConflict is returned when committing a transaction which
+// expected a point to be at a version it's no longer at.
+var ErrVersionConflict = errors.New("microspace: point version conflict")
+
+// Versioned is a point returned along with its version.
+type Versioned[T Float] struct {
+	Point   *Point[T]
+	Version uint64
+}
+
+// VersionOf returns the point's version. Versions start at zero and are
+// bumped every time the index moves the point, so external systems can
+// tell whether a position they read earlier is stale.
+func (a *Axdex[T]) VersionOf(p *Point[T]) uint64 {
+	return a.versions[p]
+}
+
+// bumpVersion increments the point's version.
+func (a *Axdex[T]) bumpVersion(p *Point[T]) {
+	if a.versions == nil {
+		a.versions = map[*Point[T]]uint64{}
+	}
+
+	a.versions[p]++
+}
+
+// NearestNVersioned is like NearestN, but returns each point along with
+// its current version.
+func (a *Axdex[T]) NearestNVersioned(p *Point[T], n int, max T) []Versioned[T] {
+	points := a.NearestN(p, n, max)
+	results := make([]Versioned[T], len(points))
+	for i, q := range points {
+		results[i] = Versioned[T]{Point: q, Version: a.versions[q]}
+	}
+
+	return results
+}
+
+// NearestNVersioned runs Axdex.NearestNVersioned against the current
+// index, so the versions are consistent with the returned positions.
+func (x *Transactional[T]) NearestNVersioned(p *Point[T], n int, max T) []Versioned[T] {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.index.NearestNVersioned(p, n, max)
+}
+
+// VersionOf returns the point's current version.
+func (x *Transactional[T]) VersionOf(p *Point[T]) uint64 {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.index.VersionOf(p)
+}
@@ -0,0 +1,36 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions(t *testing.T) {
+	a, b := &Point[float32]{0, 0}, &Point[float32]{1, 0}
+	idx := NewAxdex[float32](2)
+	idx.Insert(a)
+	idx.Insert(b)
+
+	idx.ApplyWithin(&Circle[float32]{Radius: 0.5}, func(p *Point[float32]) { p.Y = 0.25 })
+	assert.Equal(t, uint64(1), idx.VersionOf(a))
+	assert.Equal(t, []Versioned[float32]{{a, 1}, {b, 0}}, idx.NearestNVersioned(a, 2, 5))
+
+	x := NewTransactional(idx)
+	stale := x.VersionOf(b)
+
+	tx := x.Begin()
+	tx.MoveIfVersion(b, Point[float32]{2, 0}, stale)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, uint64(1), x.VersionOf(b))
+	assert.Equal(t, uint64(1), x.VersionOf(a))
+
+	// A second writer that read b before the first commit loses.
+	tx = x.Begin()
+	tx.Insert(&Point[float32]{5, 5})
+	tx.MoveIfVersion(b, Point[float32]{3, 0}, stale)
+	assert.ErrorIs(t, tx.Commit(), ErrVersionConflict)
+	assert.Equal(t, Point[float32]{2, 0}, *b)
+	assert.Len(t, x.Points(), 2)
+	assert.Equal(t, []Versioned[float32]{{b, 1}}, x.NearestNVersioned(b, 1, 5))
+}
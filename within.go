@@ -0,0 +1,174 @@
+package microspace
+
+// RangeIndex is an Index which can also look up every point within a
+// rectangle, such as for culling to a viewport.
+type RangeIndex[T Float] interface {
+	Index[T]
+	// WithinRect returns the points inside the axis-aligned rectangle
+	// from min to max, including its edges.
+	WithinRect(min, max Point[T]) []*Point[T]
+}
+
+var (
+	_ RangeIndex[float32] = new(Axdex[float32])
+	_ RangeIndex[float32] = new(KDTree[float32])
+	_ RangeIndex[float32] = new(Quadtree[float32])
+	_ RangeIndex[float32] = new(RTree[float32])
+	_ RangeIndex[float32] = new(GridIndex[float32])
+)
+
+// WithinRect implements RangeIndex.WithinRect. The range of the rectangle
+// along the axis is found with binary search, and only the points in it
+// are checked against the rectangle's other sides.
+func (a *Axdex[T]) WithinRect(min, max Point[T]) []*Point[T] {
+	return a.within(&Rect[T]{Min: min, Max: max})
+}
+
+// within returns the points inside the region, only checking those in the
+// range of its bounds along the axis.
+func (a *Axdex[T]) within(region Region[T]) []*Point[T] {
+	if !a.axis.sorted {
+		a.axis.runSort()
+	}
+
+	lo, hi := a.axis.rangeOf(region.Bounds())
+
+	var results []*Point[T]
+	for i := a.axis.Search(lo); i < len(a.axis.data) && a.axis.data[i].value <= hi; i++ {
+		if p := a.axis.data[i].p; region.Contains(p) {
+			results = append(results, p)
+		}
+	}
+
+	return results
+}
+
+// rangeOf returns the smallest and largest values on the axis of any
+// point in the rectangle. Corners are checked as custom axes may sort by
+// the projection onto any line.
+func (a *axis[T]) rangeOf(r Rect[T]) (lo, hi T) {
+	lo, hi = a.ValueFor(&r.Min), a.ValueFor(&r.Min)
+	for _, corner := range [3]Point[T]{r.Max, {X: r.Min.X, Y: r.Max.Y}, {X: r.Max.X, Y: r.Min.Y}} {
+		v := a.ValueFor(&corner)
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	return lo, hi
+}
+
+// WithinRect implements RangeIndex.WithinRect
+func (t *KDTree[T]) WithinRect(min, max Point[T]) []*Point[T] {
+	var results []*Point[T]
+	t.within(t.nodes, 0, &Rect[T]{Min: min, Max: max}, &results)
+	return results
+}
+
+// within adds the points in the subtree inside the rectangle to results,
+// only descending into sides of a split that overlap the rectangle.
+func (t *KDTree[T]) within(nodes []*Point[T], depth int, r *Rect[T], results *[]*Point[T]) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	if r.Contains(node) {
+		*results = append(*results, node)
+	}
+
+	value := kdValue(node, depth)
+	if kdValue(&r.Min, depth) <= value {
+		t.within(nodes[:mid], depth+1, r, results)
+	}
+	if kdValue(&r.Max, depth) >= value {
+		t.within(nodes[mid+1:], depth+1, r, results)
+	}
+}
+
+// WithinRect implements RangeIndex.WithinRect
+func (q *Quadtree[T]) WithinRect(min, max Point[T]) []*Point[T] {
+	var results []*Point[T]
+	q.root.within(&Rect[T]{Min: min, Max: max}, &results)
+	return results
+}
+
+// within adds the points under the node inside the rectangle to results.
+func (n *quadNode[T]) within(r *Rect[T], results *[]*Point[T]) {
+	if n.count == 0 || !r.overlaps(&n.extent) {
+		return
+	}
+
+	if n.children == nil {
+		for _, p := range n.points {
+			if r.Contains(p) {
+				*results = append(*results, p)
+			}
+		}
+		return
+	}
+
+	for _, child := range n.children {
+		child.within(r, results)
+	}
+}
+
+// WithinRect implements RangeIndex.WithinRect
+func (r *RTree[T]) WithinRect(min, max Point[T]) []*Point[T] {
+	var results []*Point[T]
+	if len(r.points) > 0 {
+		r.root.within(&Rect[T]{Min: min, Max: max}, &results)
+	}
+
+	return results
+}
+
+// within adds the points under the node inside the rectangle to results.
+func (n *rtreeNode[T]) within(r *Rect[T], results *[]*Point[T]) {
+	if !r.overlaps(&n.bounds) {
+		return
+	}
+
+	if n.leaf {
+		for _, p := range n.points {
+			if r.Contains(p) {
+				*results = append(*results, p)
+			}
+		}
+		return
+	}
+
+	for _, child := range n.children {
+		child.within(r, results)
+	}
+}
+
+// WithinRect implements RangeIndex.WithinRect
+func (g *GridIndex[T]) WithinRect(min, max Point[T]) []*Point[T] {
+	if len(g.points) == 0 {
+		return nil
+	}
+
+	r := Rect[T]{Min: min, Max: max}
+	from, to := g.cellsOf(&r)
+
+	var results []*Point[T]
+	for x := from.X; x <= to.X; x++ {
+		for y := from.Y; y <= to.Y; y++ {
+			for _, p := range g.cells[gridCell{X: x, Y: y}] {
+				if r.Contains(p) {
+					results = append(results, p)
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// cellsOf returns the first and last cells in use covering the rectangle.
+func (g *GridIndex[T]) cellsOf(r *Rect[T]) (from, to gridCell) {
+	from, to = g.cellOf(&r.Min), g.cellOf(&r.Max)
+	from = gridCell{X: max(from.X, g.lo.X), Y: max(from.Y, g.lo.Y)}
+	to = gridCell{X: min(to.X, g.hi.X), Y: min(to.Y, g.hi.Y)}
+	return from, to
+}
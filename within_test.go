@@ -0,0 +1,50 @@
+package microspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithinRect(t *testing.T) {
+	points := randomPoints(1000)
+	indexes := map[string]RangeIndex[float32]{
+		"axdex":    NewAxdex[float32](1000),
+		"custom":   NewAxdexAlong(1000, func(p *Point[float32]) float32 { return (p.Y - p.X) * 0.7 }),
+		"kdtree":   NewKDTree(points),
+		"quadtree": NewQuadtree[float32](Rect[float32]{Max: Point[float32]{1, 1}}, 0, 0),
+		"rtree":    NewRTreeSTR(points, 0),
+		"grid":     NewGridIndexFor(points),
+	}
+	for _, name := range []string{"axdex", "custom", "quadtree"} {
+		for _, p := range points {
+			indexes[name].(interface{ Insert(*Point[float32]) }).Insert(p)
+		}
+	}
+
+	rects := []Rect[float32]{
+		{Min: Point[float32]{0.2, 0.3}, Max: Point[float32]{0.4, 0.9}},
+		{Min: Point[float32]{-1, -1}, Max: Point[float32]{2, 2}},
+		{Min: Point[float32]{0.5, 0.5}, Max: Point[float32]{0.5, 0.5}},
+		{Min: Point[float32]{3, 3}, Max: Point[float32]{4, 4}},
+	}
+	for _, r := range rects {
+		expected := map[*Point[float32]]bool{}
+		for _, p := range points {
+			if r.Contains(p) {
+				expected[p] = true
+			}
+		}
+
+		for name, idx := range indexes {
+			found := map[*Point[float32]]bool{}
+			for _, p := range idx.WithinRect(r.Min, r.Max) {
+				found[p] = true
+			}
+			assert.Equal(t, expected, found, name)
+		}
+	}
+
+	assert.Empty(t, NewRTree[float32](0).WithinRect(Point[float32]{}, Point[float32]{1, 1}))
+	assert.Empty(t, NewGridIndex[float32](1).WithinRect(Point[float32]{}, Point[float32]{1, 1}))
+}